@@ -0,0 +1,280 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/table"
+	"github.com/oscal-compass/oscal-sdk-go/extensions"
+	"github.com/oscal-compass/oscal-sdk-go/validation"
+	"github.com/spf13/cobra"
+
+	"github.com/complytime/complyctl/cmd/complyctl/option"
+	"github.com/complytime/complyctl/internal/bundle"
+	"github.com/complytime/complyctl/internal/complytime"
+)
+
+// errFrameworkInUse is returned when removing a bundle would orphan a framework used by a workspace.
+var errFrameworkInUse = errors.New("framework is used by a workspace")
+
+// bundleOptions defines options for the "bundle" subcommands
+type bundleOptions struct {
+	*option.Common
+	source     string
+	name       string
+	digest     string
+	plainHTTP  bool
+	workspaces []string
+	force      bool
+}
+
+var bundleExample = `
+# Install the bundle in a directory
+complyctl bundle install ./my-bundle
+
+# Install a bundle archive under a custom name
+complyctl bundle install my-bundle-1.0.tar.gz --name my-bundle
+
+# Install a bundle from an OCI registry pinned to a manifest digest
+complyctl bundle install oci://quay.io/my-org/my-bundle:1.0 --digest sha256:<digest>
+
+# Install a bundle from an OCI image layout on disk
+complyctl bundle install oci-layout:./my-bundle-layout:1.0
+
+# List the installed bundles
+complyctl bundle list
+
+# Remove a bundle unless its frameworks are used by the plans in the given workspaces
+complyctl bundle remove my-bundle --workspace ./complytime --workspace ./other-workspace
+`
+
+// bundleCmd creates a new cobra.Command for the "bundle" subcommand
+func bundleCmd(common *option.Common) *cobra.Command {
+	bundleOpts := &bundleOptions{
+		Common: common,
+	}
+	cmd := &cobra.Command{
+		Use:     "bundle",
+		Short:   "Install, list, and remove bundles of component definitions and their controls.",
+		Example: bundleExample,
+		Args:    cobra.NoArgs,
+	}
+	cmd.AddCommand(
+		bundleInstallCmd(bundleOpts),
+		&cobra.Command{
+			Use:          "list",
+			Short:        "List the installed bundles.",
+			SilenceUsage: true,
+			Args:         cobra.NoArgs,
+			RunE:         func(_ *cobra.Command, _ []string) error { return runBundleList(bundleOpts) },
+		},
+		bundleRemoveCmd(bundleOpts),
+	)
+	return cmd
+}
+
+func bundleInstallCmd(opts *bundleOptions) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "install [flags] source",
+		Short: "Install a bundle from a directory, archive, OCI image layout, or OCI registry.",
+		Long: "Install the component definitions found in a directory, a .tar, .tar.gz, .tgz, or .zip archive, " +
+			"an OCI image layout referenced as oci-layout:<path>[:<tag>][@<digest>], or an OCI registry repository " +
+			"referenced as oci://<registry>/<repository>[:<tag>][@<digest>]. " +
+			"The component definitions are validated and the profiles and catalogs they reference are copied " +
+			"to the application directory with their references rewritten. Files whose references are not rewritten " +
+			"are installed as is with their signatures.",
+		SilenceUsage: true,
+		Args:         cobra.ExactArgs(1),
+		PreRun: func(_ *cobra.Command, args []string) {
+			opts.source = args[0]
+		},
+		RunE: func(cmd *cobra.Command, _ []string) error { return runBundleInstall(cmd.Context(), opts) },
+	}
+	cmd.Flags().StringVarP(&opts.name, "name", "n", "", "name of the installed bundle (defaults to the source name)")
+	cmd.Flags().StringVar(&opts.digest, "digest", "", "sha256 manifest digest the OCI bundle must resolve to")
+	cmd.Flags().BoolVar(&opts.plainHTTP, "plain-http", false, "connect to the OCI registry over HTTP instead of HTTPS")
+	return cmd
+}
+
+func bundleRemoveCmd(opts *bundleOptions) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "remove [flags] name",
+		Short: "Remove an installed bundle.",
+		Long: "Remove the files installed by a bundle. The removal is refused when a framework only " +
+			"provided by the bundle is used by the assessment plan of one of the workspaces.",
+		SilenceUsage: true,
+		Args:         cobra.ExactArgs(1),
+		PreRun: func(_ *cobra.Command, args []string) {
+			opts.name = args[0]
+		},
+		RunE: func(_ *cobra.Command, _ []string) error { return runBundleRemove(opts) },
+	}
+	cmd.Flags().StringSliceVarP(&opts.workspaces, "workspace", "w", []string{"./complytime"}, "workspaces to check for assessment plans using the bundle")
+	cmd.Flags().BoolVarP(&opts.force, "force", "f", false, "remove the bundle even if a workspace uses its frameworks")
+	return cmd
+}
+
+func runBundleInstall(ctx context.Context, opts *bundleOptions) error {
+	appDir, err := complytime.NewApplicationDirectory(true, logger)
+	if err != nil {
+		return err
+	}
+	logger.Debug(fmt.Sprintf("Using application directory: %s", appDir.AppDir()))
+
+	var entry bundle.Entry
+	if bundle.IsOCIReference(opts.source) {
+		ociOpts := bundle.OCIOptions{
+			Name:      opts.name,
+			Digest:    opts.digest,
+			PlainHTTP: opts.plainHTTP,
+		}
+		entry, err = bundle.InstallOCI(ctx, appDir, opts.source, ociOpts, logger)
+	} else {
+		if opts.digest != "" || opts.plainHTTP {
+			return fmt.Errorf("--digest and --plain-http are only supported for %s and %s sources", bundle.OCILayoutPrefix, bundle.OCIRegistryPrefix)
+		}
+		entry, err = bundle.Install(appDir, opts.source, opts.name, logger)
+	}
+	if err != nil {
+		return err
+	}
+	logger.Info(fmt.Sprintf("Bundle %s installed with %d component definition(s) and %d file(s).",
+		entry.Name, len(entry.ComponentDefinitions), len(entry.Files)), "digest", entry.Digest)
+	return nil
+}
+
+func runBundleList(opts *bundleOptions) error {
+	appDir, err := complytime.NewApplicationDirectory(true, logger)
+	if err != nil {
+		return err
+	}
+	index, err := bundle.LoadIndex(appDir)
+	if err != nil {
+		return err
+	}
+	if len(index.Bundles) == 0 {
+		_, _ = fmt.Fprintln(opts.Out, "No bundles installed.")
+		return nil
+	}
+	columns, rows := getBundleColumnsAndRows(index.Bundles)
+	writePlainTable(opts.Out, columns, rows)
+	return nil
+}
+
+func runBundleRemove(opts *bundleOptions) error {
+	appDir, err := complytime.NewApplicationDirectory(true, logger)
+	if err != nil {
+		return err
+	}
+	index, err := bundle.LoadIndex(appDir)
+	if err != nil {
+		return err
+	}
+	if _, found := index.Find(opts.name); !found {
+		return fmt.Errorf("%w: %s", bundle.ErrNotInstalled, opts.name)
+	}
+	if err := checkFrameworksInUse(index.OrphanedFrameworks(opts.name), opts.workspaces); err != nil {
+		if !opts.force {
+			return err
+		}
+		logger.Warn(fmt.Sprintf("Removing bundle %s: %v", opts.name, err))
+	}
+	entry, err := bundle.Remove(appDir, opts.name)
+	if err != nil {
+		return err
+	}
+	logger.Info(fmt.Sprintf("Bundle %s removed.", entry.Name))
+	return nil
+}
+
+// checkFrameworksInUse returns an error when an assessment plan of a workspace uses one of the frameworks.
+// The plans of the frameworks in subdirectories of a workspace are checked as well.
+func checkFrameworksInUse(frameworks []string, workspaces []string) error {
+	if len(frameworks) == 0 {
+		return nil
+	}
+	var inUse []string
+	for _, workspace := range workspaces {
+		planWorkspaces, err := frameworkWorkspaces(&option.ComplyTime{UserWorkspace: workspace})
+		if err != nil {
+			return fmt.Errorf("failed to find assessment plans of workspace %s: %w", workspace, err)
+		}
+		for _, planWorkspace := range planWorkspaces {
+			frameworkID, err := workspaceFramework(planWorkspace.UserWorkspace)
+			if err != nil {
+				return err
+			}
+			for _, framework := range frameworks {
+				if frameworkID == framework {
+					inUse = append(inUse, fmt.Sprintf("%s (%s)", framework, planWorkspace.UserWorkspace))
+				}
+			}
+		}
+	}
+	if len(inUse) > 0 {
+		return fmt.Errorf("%w: %s", errFrameworkInUse, strings.Join(inUse, ", "))
+	}
+	return nil
+}
+
+// workspaceFramework returns the framework of the assessment plan of the workspace, or an empty string
+// when the workspace has no assessment plan.
+func workspaceFramework(workspace string) (string, error) {
+	apPath := filepath.Clean(filepath.Join(workspace, assessmentPlanLocation))
+	if _, err := os.Stat(apPath); err != nil {
+		logger.Debug(fmt.Sprintf("Skipping workspace %s: %v", workspace, err))
+		return "", nil
+	}
+	plan, err := complytime.ReadPlan(apPath, validation.NoopValidator{})
+	if err != nil {
+		return "", fmt.Errorf("failed to read assessment plan of workspace %s: %w", workspace, err)
+	}
+	if plan.Metadata.Props == nil {
+		return "", nil
+	}
+	frameworkProp, found := extensions.GetTrestleProp(extensions.FrameworkProp, *plan.Metadata.Props)
+	if !found {
+		return "", nil
+	}
+	return frameworkProp.Value, nil
+}
+
+// getBundleColumnsAndRows returns the columns and rows to print the installed bundles as a table.
+func getBundleColumnsAndRows(bundles []bundle.Entry) ([]table.Column, []table.Row) {
+	var rows []table.Row
+	for _, entry := range bundles {
+		rows = append(rows, table.Row{
+			entry.Name,
+			valueOrDash(entry.Version),
+			valueOrDash(strings.Join(entry.Frameworks, ", ")),
+			entry.InstalledAt.Local().Format(time.DateTime),
+			entry.Source,
+			valueOrDash(shortDigest(entry.Digest)),
+		})
+	}
+	columns := []table.Column{
+		{Title: "Name", Width: 5},
+		{Title: "Version", Width: 8},
+		{Title: "Frameworks", Width: 11},
+		{Title: "Installed", Width: 10},
+		{Title: "Source", Width: 7},
+		{Title: "Digest", Width: 7},
+	}
+	return columns, rows
+}
+
+// shortDigest abbreviates a sha256 digest to its first 12 hex characters.
+func shortDigest(digest string) string {
+	algorithm, encoded, found := strings.Cut(digest, ":")
+	if !found || len(encoded) <= 12 {
+		return digest
+	}
+	return algorithm + ":" + encoded[:12]
+}
@@ -0,0 +1,90 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package cli
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/charmbracelet/bubbles/table"
+	"github.com/oscal-compass/oscal-sdk-go/validation"
+	"github.com/stretchr/testify/require"
+
+	"github.com/complytime/complyctl/internal/bundle"
+	"github.com/complytime/complyctl/internal/complytime"
+)
+
+func TestCheckFrameworksInUse(t *testing.T) {
+	workspace := t.TempDir()
+	plan, err := complytime.ReadPlan(filepath.Join("testdata", assessmentPlanLocation), validation.NoopValidator{})
+	require.NoError(t, err)
+	require.NoError(t, complytime.WritePlan(plan, "cis", filepath.Join(workspace, assessmentPlanLocation)))
+	emptyWorkspace := t.TempDir()
+	// The plans of several frameworks are in subdirectories of the workspace.
+	multiWorkspace := t.TempDir()
+	plan, err = complytime.ReadPlan(filepath.Join("testdata", assessmentPlanLocation), validation.NoopValidator{})
+	require.NoError(t, err)
+	require.NoError(t, complytime.WritePlan(plan, "anssi", filepath.Join(multiWorkspace, "anssi", assessmentPlanLocation)))
+
+	tests := []struct {
+		name       string
+		frameworks []string
+		wantErr    string
+	}{
+		{
+			name:       "Valid/NoOrphanedFrameworks",
+			frameworks: nil,
+		},
+		{
+			name:       "Valid/FrameworkNotUsed",
+			frameworks: []string{"fedramp"},
+		},
+		{
+			name:       "Invalid/FrameworkUsed",
+			frameworks: []string{"fedramp", "cis"},
+			wantErr:    "framework is used by a workspace: cis (" + workspace + ")",
+		},
+		{
+			name:       "Invalid/FrameworkUsedInSubdirectory",
+			frameworks: []string{"anssi"},
+			wantErr:    "framework is used by a workspace: anssi (" + filepath.Join(multiWorkspace, "anssi") + ")",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := checkFrameworksInUse(tt.frameworks, []string{workspace, emptyWorkspace, multiWorkspace})
+			if tt.wantErr != "" {
+				require.EqualError(t, err, tt.wantErr)
+				require.ErrorIs(t, err, errFrameworkInUse)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestGetBundleColumnsAndRows(t *testing.T) {
+	installedAt := time.Date(2025, 3, 1, 12, 0, 0, 0, time.Local)
+	bundles := []bundle.Entry{
+		{
+			Name:        "cis-fedora",
+			Version:     "1.0.0",
+			Source:      "oci://quay.io/complytime/cis-fedora:1.0",
+			Digest:      "sha256:0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef",
+			InstalledAt: installedAt,
+			Frameworks:  []string{"cis", "cis-l2"},
+		},
+		{
+			Name:        "mixed",
+			Source:      "./mixed",
+			InstalledAt: installedAt,
+		},
+	}
+	columns, rows := getBundleColumnsAndRows(bundles)
+	require.Len(t, columns, 6)
+	require.Equal(t, []table.Row{
+		{"cis-fedora", "1.0.0", "cis, cis-l2", "2025-03-01 12:00:00", "oci://quay.io/complytime/cis-fedora:1.0", "sha256:0123456789ab"},
+		{"mixed", "-", "-", "2025-03-01 12:00:00", "./mixed", "-"},
+	}, rows)
+}
@@ -0,0 +1,244 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package cli
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/table"
+	"github.com/oscal-compass/oscal-sdk-go/validation"
+	"github.com/spf13/cobra"
+
+	"github.com/complytime/complyctl/cmd/complyctl/option"
+	"github.com/complytime/complyctl/internal/complytime"
+	"github.com/complytime/complyctl/internal/terminal"
+)
+
+// Supported output formats for the "diff" subcommand
+const (
+	diffOutputTable    = "table"
+	diffOutputMarkdown = "markdown"
+	diffOutputJSON     = "json"
+)
+
+// errRegressions is returned when newly failing rules or controls are detected.
+var errRegressions = errors.New("regressions detected")
+
+// diffOptions defines options for the "diff" subcommand
+type diffOptions struct {
+	*option.Common
+	oldResultsPath string
+	newResultsPath string
+	// output format for the detected changes
+	output string
+}
+
+var diffExample = `
+# Compare the assessment results of two scans of the same host.
+complyctl diff old/assessment-results.json complytime/assessment-results.json
+
+# Print the changes as markdown or JSON.
+complyctl diff old/assessment-results.json complytime/assessment-results.json --output markdown
+complyctl diff old/assessment-results.json complytime/assessment-results.json --output json
+`
+
+// diffCmd creates a new cobra.Command for the "diff" subcommand
+func diffCmd(common *option.Common) *cobra.Command {
+	diffOpts := &diffOptions{
+		Common: common,
+	}
+	cmd := &cobra.Command{
+		Use:          "diff [flags] old-results new-results",
+		Short:        "Compare two assessment results and report drift.",
+		Long:         "Compare two assessment results and report drift. The command exits with an error when regressions are detected.",
+		Example:      diffExample,
+		SilenceUsage: true,
+		Args:         cobra.ExactArgs(2),
+		PreRun: func(_ *cobra.Command, args []string) {
+			diffOpts.oldResultsPath = filepath.Clean(args[0])
+			diffOpts.newResultsPath = filepath.Clean(args[1])
+		},
+		RunE: func(_ *cobra.Command, _ []string) error {
+			if err := validateDiff(diffOpts); err != nil {
+				return err
+			}
+			return runDiff(diffOpts)
+		},
+	}
+	cmd.Flags().StringVarP(&diffOpts.output, "output", "o", diffOutputTable, "output format, one of: table, markdown, json")
+	return cmd
+}
+
+func validateDiff(opts *diffOptions) error {
+	switch opts.output {
+	case diffOutputTable, diffOutputMarkdown, diffOutputJSON:
+		return nil
+	default:
+		return fmt.Errorf("invalid output format %q: must be one of table, markdown, json", opts.output)
+	}
+}
+
+func runDiff(opts *diffOptions) error {
+	validator := validation.NewSchemaValidator()
+	oldResults, err := complytime.ReadAssessmentResults(opts.oldResultsPath, validator)
+	if err != nil {
+		return err
+	}
+	newResults, err := complytime.ReadAssessmentResults(opts.newResultsPath, validator)
+	if err != nil {
+		return err
+	}
+
+	diff := complytime.DiffAssessmentResults(oldResults, newResults)
+	switch opts.output {
+	case diffOutputJSON:
+		err = writeDiffJSON(opts.Out, diff)
+	case diffOutputMarkdown:
+		writeDiffMarkdown(opts.Out, diff)
+	default:
+		writeDiffTable(opts.Out, diff)
+	}
+	if err != nil {
+		return err
+	}
+
+	if diff.HasRegressions() {
+		return fmt.Errorf("%w between %s and %s", errRegressions, opts.oldResultsPath, opts.newResultsPath)
+	}
+	return nil
+}
+
+// getDiffColumnsAndRows returns the columns and rows to print the detected changes as a table.
+func getDiffColumnsAndRows(diff complytime.ResultsDiff) ([]table.Column, []table.Row) {
+	var rows []table.Row
+	addRuleRows := func(change string, ruleChanges []complytime.RuleChange) {
+		for _, ruleChange := range ruleChanges {
+			rows = append(rows, table.Row{change, "rule", ruleChange.RuleID, valueOrDash(ruleChange.OldStatus), valueOrDash(ruleChange.NewStatus)})
+		}
+	}
+	addControlRows := func(change string, controlIDs []string, oldStatus, newStatus string) {
+		for _, controlID := range controlIDs {
+			rows = append(rows, table.Row{change, "control", controlID, oldStatus, newStatus})
+		}
+	}
+
+	addRuleRows("newly failed", diff.NewlyFailedRules)
+	addRuleRows("fixed", diff.FixedRules)
+	addRuleRows("appeared", diff.AddedRules)
+	addRuleRows("disappeared", diff.RemovedRules)
+	addControlRows("newly failed", diff.NewlyFailedControls, "satisfied", "not-satisfied")
+	addControlRows("fixed", diff.FixedControls, "not-satisfied", "satisfied")
+	addControlRows("appeared", diff.AddedControls, "-", "reviewed")
+	addControlRows("disappeared", diff.RemovedControls, "reviewed", "-")
+	for _, subjectChange := range diff.SubjectChanges {
+		for _, subject := range subjectChange.Added {
+			rows = append(rows, table.Row{"subject added", "rule", subjectChange.RuleID, "-", subject})
+		}
+		for _, subject := range subjectChange.Removed {
+			rows = append(rows, table.Row{"subject removed", "rule", subjectChange.RuleID, subject, "-"})
+		}
+		for _, changed := range subjectChange.Changed {
+			rows = append(rows, table.Row{"subject changed", "rule", subjectChange.RuleID,
+				fmt.Sprintf("%s: %s", changed.Subject, changed.OldResult),
+				fmt.Sprintf("%s: %s", changed.Subject, changed.NewResult)})
+		}
+	}
+
+	columns := []table.Column{
+		{Title: "Change", Width: 18},
+		{Title: "Type", Width: 10},
+		{Title: "ID", Width: 30},
+		{Title: "Old", Width: 16},
+		{Title: "New", Width: 16},
+	}
+	return calculateDynamicColumnWidths(columns, rows), rows
+}
+
+// writeDiffTable prints the detected changes as a plain table.
+func writeDiffTable(writer io.Writer, diff complytime.ResultsDiff) {
+	if diff.IsEmpty() {
+		_, _ = fmt.Fprintln(writer, "No changes detected.")
+		return
+	}
+	columns, rows := getDiffColumnsAndRows(diff)
+	// Leave room between columns for readability
+	for i := range columns {
+		columns[i].Width += 2
+	}
+	terminal.ShowPlainTable(writer, columns, rows)
+}
+
+// writeDiffMarkdown prints the detected changes as a markdown document.
+func writeDiffMarkdown(writer io.Writer, diff complytime.ResultsDiff) {
+	_, _ = fmt.Fprintln(writer, "# Assessment Results Diff")
+	if diff.IsEmpty() {
+		_, _ = fmt.Fprintln(writer, "\nNo changes detected.")
+		return
+	}
+
+	writeRuleSection := func(title string, ruleChanges []complytime.RuleChange) {
+		if len(ruleChanges) == 0 {
+			return
+		}
+		_, _ = fmt.Fprintf(writer, "\n## %s\n\n| Rule ID | Old | New |\n| --- | --- | --- |\n", title)
+		for _, ruleChange := range ruleChanges {
+			_, _ = fmt.Fprintf(writer, "| %s | %s | %s |\n", ruleChange.RuleID, valueOrDash(ruleChange.OldStatus), valueOrDash(ruleChange.NewStatus))
+		}
+	}
+	writeControlSection := func(title string, controlIDs []string) {
+		if len(controlIDs) == 0 {
+			return
+		}
+		_, _ = fmt.Fprintf(writer, "\n## %s\n\n", title)
+		for _, controlID := range controlIDs {
+			_, _ = fmt.Fprintf(writer, "- %s\n", controlID)
+		}
+	}
+
+	writeRuleSection("Newly Failed Rules", diff.NewlyFailedRules)
+	writeRuleSection("Fixed Rules", diff.FixedRules)
+	writeRuleSection("Appeared Rules", diff.AddedRules)
+	writeRuleSection("Disappeared Rules", diff.RemovedRules)
+	writeControlSection("Newly Failed Controls", diff.NewlyFailedControls)
+	writeControlSection("Fixed Controls", diff.FixedControls)
+	writeControlSection("Appeared Controls", diff.AddedControls)
+	writeControlSection("Disappeared Controls", diff.RemovedControls)
+
+	if len(diff.SubjectChanges) > 0 {
+		_, _ = fmt.Fprintf(writer, "\n## Changed Subjects\n\n| Rule ID | Added | Removed | Changed |\n| --- | --- | --- | --- |\n")
+		for _, subjectChange := range diff.SubjectChanges {
+			var changed []string
+			for _, c := range subjectChange.Changed {
+				changed = append(changed, fmt.Sprintf("%s (%s -> %s)", c.Subject, c.OldResult, c.NewResult))
+			}
+			_, _ = fmt.Fprintf(writer, "| %s | %s | %s | %s |\n",
+				subjectChange.RuleID,
+				strings.Join(subjectChange.Added, ", "),
+				strings.Join(subjectChange.Removed, ", "),
+				strings.Join(changed, ", "))
+		}
+	}
+}
+
+// writeDiffJSON prints the detected changes as JSON.
+func writeDiffJSON(writer io.Writer, diff complytime.ResultsDiff) error {
+	data, err := json.MarshalIndent(diff, "", "  ")
+	if err != nil {
+		return fmt.Errorf("error marshalling json content: %w", err)
+	}
+	_, err = fmt.Fprintln(writer, string(data))
+	return err
+}
+
+// valueOrDash returns "-" for empty values to keep table cells aligned.
+func valueOrDash(value string) string {
+	if value == "" {
+		return "-"
+	}
+	return value
+}
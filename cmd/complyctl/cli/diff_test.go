@@ -0,0 +1,90 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package cli
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/charmbracelet/bubbles/table"
+	"github.com/stretchr/testify/require"
+
+	"github.com/complytime/complyctl/internal/complytime"
+)
+
+func TestValidateDiff(t *testing.T) {
+	tests := []struct {
+		name    string
+		output  string
+		wantErr string
+	}{
+		{
+			name:   "Valid/Table",
+			output: "table",
+		},
+		{
+			name:   "Valid/JSON",
+			output: "json",
+		},
+		{
+			name:    "Invalid/UnknownFormat",
+			output:  "xml",
+			wantErr: "invalid output format \"xml\": must be one of table, markdown, json",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateDiff(&diffOptions{output: tt.output})
+			if tt.wantErr != "" {
+				require.EqualError(t, err, tt.wantErr)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestGetDiffColumnsAndRows(t *testing.T) {
+	diff := complytime.ResultsDiff{
+		NewlyFailedRules:    []complytime.RuleChange{{RuleID: "rule-1", OldStatus: "pass", NewStatus: "fail"}},
+		AddedRules:          []complytime.RuleChange{{RuleID: "rule-2", NewStatus: "pass"}},
+		FixedControls:       []string{"control-1"},
+		SubjectChanges:      []complytime.SubjectChange{{RuleID: "rule-1", Changed: []complytime.SubjectStatusChange{{Subject: "host-a", OldResult: "pass", NewResult: "fail"}}}},
+		NewlyFailedControls: []string{},
+	}
+	expectedRows := []table.Row{
+		{"newly failed", "rule", "rule-1", "pass", "fail"},
+		{"appeared", "rule", "rule-2", "-", "pass"},
+		{"fixed", "control", "control-1", "not-satisfied", "satisfied"},
+		{"subject changed", "rule", "rule-1", "host-a: pass", "host-a: fail"},
+	}
+
+	columns, rows := getDiffColumnsAndRows(diff)
+	require.Equal(t, expectedRows, rows)
+	require.Len(t, columns, 5)
+}
+
+func TestWriteDiffMarkdown(t *testing.T) {
+	var buf bytes.Buffer
+	writeDiffMarkdown(&buf, complytime.ResultsDiff{})
+	require.Equal(t, "# Assessment Results Diff\n\nNo changes detected.\n", buf.String())
+
+	buf.Reset()
+	writeDiffMarkdown(&buf, complytime.ResultsDiff{
+		FixedRules:      []complytime.RuleChange{{RuleID: "rule-1", OldStatus: "fail", NewStatus: "pass"}},
+		RemovedControls: []string{"control-1"},
+	})
+	expected := `# Assessment Results Diff
+
+## Fixed Rules
+
+| Rule ID | Old | New |
+| --- | --- | --- |
+| rule-1 | fail | pass |
+
+## Disappeared Controls
+
+- control-1
+`
+	require.Equal(t, expected, buf.String())
+}
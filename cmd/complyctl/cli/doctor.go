@@ -0,0 +1,74 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/complytime/complyctl/cmd/complyctl/option"
+	"github.com/complytime/complyctl/internal/complytime"
+	"github.com/complytime/complyctl/pkg/doctor"
+)
+
+// errDoctorFailures is returned when at least one environment check fails.
+var errDoctorFailures = errors.New("environment checks failed")
+
+// doctorOptions defines options for the "doctor" subcommand
+type doctorOptions struct {
+	*option.Common
+}
+
+// doctorCmd creates a new cobra.Command for the "doctor" subcommand
+func doctorCmd(common *option.Common) *cobra.Command {
+	doctorOpts := &doctorOptions{
+		Common: common,
+	}
+	cmd := &cobra.Command{
+		Use:   "doctor",
+		Short: "Check the environment for common problems.",
+		Long: "Check the application directories, bundle, plugin discovery, plugin options, and plugin prerequisites " +
+			"and print a checklist with hints to fix the problems found.",
+		SilenceUsage: true,
+		Args:         cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return runDoctor(cmd, doctorOpts)
+		},
+	}
+	return cmd
+}
+
+func runDoctor(cmd *cobra.Command, opts *doctorOptions) error {
+	// The directories are not created so missing directories are reported.
+	appDir, err := complytime.NewApplicationDirectory(false, logger)
+	if err != nil {
+		return err
+	}
+	checks := complytime.Diagnose(cmd.Context(), appDir, logger)
+	if failed := writeChecks(opts.Out, checks); failed > 0 {
+		return fmt.Errorf("%w: %d", errDoctorFailures, failed)
+	}
+	return nil
+}
+
+// writeChecks writes the checks as a checklist followed by a summary and returns the number of failed checks.
+func writeChecks(w io.Writer, checks []doctor.Check) int {
+	counts := make(map[doctor.Status]int)
+	for _, check := range checks {
+		counts[check.Status]++
+		line := fmt.Sprintf("[%s] %s", strings.ToUpper(string(check.Status)), check.Name)
+		if check.Message != "" {
+			line += ": " + check.Message
+		}
+		fmt.Fprintln(w, line)
+		if check.Hint != "" && check.Status != doctor.StatusPass {
+			fmt.Fprintf(w, "       hint: %s\n", check.Hint)
+		}
+	}
+	fmt.Fprintf(w, "\n%d passed, %d warnings, %d failed\n", counts[doctor.StatusPass], counts[doctor.StatusWarn], counts[doctor.StatusFail])
+	return counts[doctor.StatusFail]
+}
@@ -0,0 +1,31 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package cli
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/complytime/complyctl/pkg/doctor"
+)
+
+func TestWriteChecks(t *testing.T) {
+	var buf bytes.Buffer
+	failed := writeChecks(&buf, []doctor.Check{
+		doctor.Pass("development mode", "disabled, using /usr/share/complytime"),
+		doctor.Warn("plugin configuration", "/etc/complytime/config.d/ does not exist", "Create it to override the manifest defaults."),
+		doctor.Fail("openscap: oscap installed", "executable file not found in $PATH", "Install the openscap-scanner package."),
+	})
+	require.Equal(t, 1, failed)
+	expected := `[PASS] development mode: disabled, using /usr/share/complytime
+[WARN] plugin configuration: /etc/complytime/config.d/ does not exist
+       hint: Create it to override the manifest defaults.
+[FAIL] openscap: oscap installed: executable file not found in $PATH
+       hint: Install the openscap-scanner package.
+
+1 passed, 1 warnings, 1 failed
+`
+	require.Equal(t, expected, buf.String())
+}
@@ -4,6 +4,7 @@ package cli
 
 import (
 	"fmt"
+	"path/filepath"
 
 	"github.com/oscal-compass/compliance-to-policy-go/v2/framework"
 	"github.com/oscal-compass/compliance-to-policy-go/v2/framework/actions"
@@ -19,19 +20,31 @@ import (
 type generateOptions struct {
 	*option.Common
 	complyTimeOpts   *option.ComplyTime
+	verifyOpts       *option.Verification
+	selectionOpts    *option.Selection
 	withPluginConfig string
 }
 
+var generateExample = `
+# Generate the policy of each plugin from the assessment plan in the workspace.
+complyctl generate
+
+# Generate the policy of the selected rules only, in the partial directory of the workspace.
+complyctl generate --rule sshd_set_idle_timeout --control "ac-*"
+`
+
 // generateCmd creates a new cobra.Command for the "generate" subcommand
 func generateCmd(common *option.Common) *cobra.Command {
 	generateOpts := &generateOptions{
 		Common:         common,
 		complyTimeOpts: &option.ComplyTime{},
+		verifyOpts:     &option.Verification{},
+		selectionOpts:  &option.Selection{},
 	}
 	cmd := &cobra.Command{
 		Use:     "generate [flags]",
 		Short:   "Generate PVP policy from an assessment plan",
-		Example: "complyctl generate",
+		Example: generateExample,
 		Args:    cobra.NoArgs,
 		RunE: func(cmd *cobra.Command, _ []string) error {
 			return runGenerate(cmd, generateOpts)
@@ -39,27 +52,59 @@ func generateCmd(common *option.Common) *cobra.Command {
 	}
 	cmd.Flags().StringVarP(&generateOpts.withPluginConfig, "plugin-config", "c", "", "Directory where user customized plugin manifests are located")
 	generateOpts.complyTimeOpts.BindFlags(cmd.Flags())
+	generateOpts.verifyOpts.BindFlags(cmd.Flags())
+	generateOpts.selectionOpts.BindFlags(cmd.Flags())
 	return cmd
 }
 
 func runGenerate(cmd *cobra.Command, opts *generateOptions) error {
-	validator := validation.NewSchemaValidator()
-	ap, _, err := loadPlan(opts.complyTimeOpts, validator)
+	workspaces, err := frameworkWorkspaces(opts.complyTimeOpts)
 	if err != nil {
 		return err
 	}
 
-	inputContext, err := complytime.ActionsContextFromPlan(ap)
+	// Create the application directory if it does not exist
+	appDir, err := complytime.NewApplicationDirectory(true, logger)
 	if err != nil {
 		return err
 	}
+	logger.Debug(fmt.Sprintf("Using application directory: %s", appDir.AppDir()))
+	if _, err := verifyContent(appDir, opts.verifyOpts); err != nil {
+		return err
+	}
 
-	// Create the application directory if it does not exist
-	appDir, err := complytime.NewApplicationDirectory(true, logger)
+	for _, workspace := range workspaces {
+		if len(workspaces) > 1 {
+			logger.Info(fmt.Sprintf("Generating policy for framework %s in %s.", workspace.FrameworkID, workspace.UserWorkspace))
+		}
+		frameworkOpts := *opts
+		frameworkOpts.complyTimeOpts = workspace
+		if err := generateFramework(cmd, &frameworkOpts, appDir); err != nil {
+			return fmt.Errorf("error generating policy for framework %s: %w", workspace.FrameworkID, err)
+		}
+	}
+	return nil
+}
+
+// generateFramework generates the policy of the assessment plan in the workspace with each plugin.
+func generateFramework(cmd *cobra.Command, opts *generateOptions, appDir complytime.ApplicationDirectory) error {
+	validator := validation.NewSchemaValidator()
+	ap, _, err := loadPlan(opts.complyTimeOpts, validator)
 	if err != nil {
 		return err
 	}
-	logger.Debug(fmt.Sprintf("Using application directory: %s", appDir.AppDir()))
+	selection := opts.selectionOpts.ToSelection()
+	if !selection.IsEmpty() {
+		if err := selection.NarrowPlan(ap); err != nil {
+			return fmt.Errorf("error selecting rules and controls: %w", err)
+		}
+	}
+
+	inputContext, err := complytime.ActionsContextFromPlan(ap)
+	if err != nil {
+		return err
+	}
+
 	cfg, err := complytime.Config(appDir)
 	if err != nil {
 		return err
@@ -84,16 +129,29 @@ func runGenerate(cmd *cobra.Command, opts *generateOptions) error {
 
 	pluginOptions := opts.complyTimeOpts.ToPluginOptions()
 	pluginOptions.UserConfigRoot = opts.withPluginConfig
-	plugins, cleanup, err := complytime.Plugins(manager, inputContext, pluginOptions, logger)
+	if !selection.IsEmpty() {
+		// The policy of the selected rules does not replace the policy of the assessment plan
+		pluginOptions.Workspace = filepath.Join(pluginOptions.Workspace, partialWorkspaceLocation)
+		logger.Info(fmt.Sprintf("Generating the policy of the selected rules in %s.", pluginOptions.Workspace))
+	}
+	plugins, cleanup, err := complytime.Plugins(cmd.Context(), manager, inputContext, pluginOptions, logger)
 	if cleanup != nil {
 		defer cleanup()
 	}
 	if err != nil {
+		if cmd.Context().Err() != nil {
+			return fmt.Errorf("policy generation canceled before the plugins were launched: %w", cmd.Context().Err())
+		}
 		return fmt.Errorf("errors launching plugins: %w", err)
 	}
 
 	err = actions.GeneratePolicy(cmd.Context(), inputContext, plugins)
 	if err != nil {
+		if cmd.Context().Err() != nil {
+			// The policy of the plugins that did not finish is incomplete
+			return fmt.Errorf("policy generation canceled, the policy in %s may be incomplete and must be generated again: %w",
+				pluginOptions.Workspace, cmd.Context().Err())
+		}
 		return err
 	}
 
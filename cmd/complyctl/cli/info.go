@@ -3,6 +3,7 @@
 package cli
 
 import (
+	"errors"
 	"fmt"
 	"io"
 	"path/filepath"
@@ -22,6 +23,7 @@ import (
 
 	"github.com/complytime/complyctl/cmd/complyctl/option"
 	"github.com/complytime/complyctl/internal/complytime"
+	"github.com/complytime/complyctl/internal/report"
 	"github.com/complytime/complyctl/internal/terminal"
 )
 
@@ -64,26 +66,38 @@ var (
 
 // parameter represents details about a parameter for easy mapping of parameters to set values.
 type parameter struct {
-	ID          string // Name of param
-	Description string
-	Values      []string // Current set value(s) - used for rule display
+	ID           string   `json:"id"` // Name of param
+	Description  string   `json:"description,omitempty"`
+	Values       []string `json:"values,omitempty"`       // Current set value(s) - used for rule display
+	Alternatives []string `json:"alternatives,omitempty"` // Valid alternative values - used for machine-readable output
+	Rules        []string `json:"rules,omitempty"`        // Rules using the parameter - used for machine-readable output
 }
 
 // rule represents details about a rule for easy mapping of rules to plugins.
 type rule struct {
-	ID          string
-	Plugin      string
-	Description string
-	Parameters  []parameter
+	ID          string      `json:"id"`
+	Plugin      string      `json:"plugin,omitempty"`
+	Description string      `json:"description,omitempty"`
+	Parameters  []parameter `json:"parameters,omitempty"`
 }
 
 // control repsents details about a control across component sources.
 type control struct {
-	ID                   string
-	Title                string
-	Description          string
-	ImplementationStatus string
-	Rules                []rule
+	ID                   string `json:"id"`
+	Title                string `json:"title,omitempty"`
+	Description          string `json:"description,omitempty"`
+	ImplementationStatus string `json:"implementationStatus,omitempty"`
+	Rules                []rule `json:"rules,omitempty"`
+}
+
+// frameworkInfo is the machine-readable output of the "info" subcommand. Controls are set
+// when showing the framework or a control, Rules when showing a rule, and Parameters when
+// showing a parameter.
+type frameworkInfo struct {
+	FrameworkID string      `json:"frameworkId"`
+	Controls    []control   `json:"controls,omitempty"`
+	Rules       []rule      `json:"rules,omitempty"`
+	Parameters  []parameter `json:"parameters,omitempty"`
 }
 
 // rulePluginMap maps a Rule ID to the plugin that implements it.
@@ -109,6 +123,21 @@ type infoOptions struct {
 	parameterID    string // show info for a specific parameter ID
 	limit          int    // limit number for table rows shown in terminal
 	plain          bool   // print plain table only
+	export         string // export the control, rule, and parameter matrix as csv or tsv
+	output         string // print the info in a machine-readable format, json or yaml
+}
+
+// controlMatrixHeader is the header of the exported control, rule, and parameter matrix.
+var controlMatrixHeader = []string{
+	"control_id",
+	"control_title",
+	"implementation_status",
+	"rule_id",
+	"rule_description",
+	"plugin",
+	"parameter_id",
+	"parameter_description",
+	"parameter_values",
 }
 
 func infoCmd(common *option.Common) *cobra.Command {
@@ -119,24 +148,51 @@ func infoCmd(common *option.Common) *cobra.Command {
 	cmd := &cobra.Command{
 		Use:     "info <framework-id> [flags]",
 		Short:   "Show information about a framework's controls and rules",
-		Example: " complyctl info anssi_bp28_minimal\n complyctl info anssi_bp28_minimal --control r31\n complyctl info anssi_bp28_minimal --rule enable_authselect\n complyctl info anssi_bp28_minimal --parameter var_accounts_password_minlen_login_defs",
+		Example: " complyctl info anssi_bp28_minimal\n complyctl info anssi_bp28_minimal --control r31\n complyctl info anssi_bp28_minimal --rule enable_authselect\n complyctl info anssi_bp28_minimal --parameter var_accounts_password_minlen_login_defs\n complyctl info anssi_bp28_minimal --export csv > matrix.csv\n complyctl info anssi_bp28_minimal --control r31 --output json",
 		Args:    cobra.ExactArgs(1),
 		PreRun: func(cmd *cobra.Command, args []string) {
 			if len(args) == 1 {
 				infoOpts.complyTimeOpts.FrameworkID = filepath.Clean(args[0])
 			}
 		},
-		RunE: func(_ *cobra.Command, _ []string) error { return runInfo(infoOpts) },
+		RunE: func(_ *cobra.Command, _ []string) error {
+			if err := validateInfo(infoOpts); err != nil {
+				return err
+			}
+			return runInfo(infoOpts)
+		},
 	}
 	cmd.Flags().StringVarP(&infoOpts.controlID, "control", "c", "", "show info for a specific control ID")
 	cmd.Flags().StringVarP(&infoOpts.ruleID, "rule", "r", "", "show info for a specific rule ID")
 	cmd.Flags().StringVarP(&infoOpts.parameterID, "parameter", "P", "", "show info for a specific parameter ID")
 	cmd.Flags().IntVarP(&infoOpts.limit, "limit", "l", 0, "limit the number of table rows")
 	cmd.Flags().BoolVarP(&infoOpts.plain, "plain", "p", false, "print the table with minimal formatting")
+	cmd.Flags().StringVar(&infoOpts.export, "export", "", "export the control, rule, and parameter matrix to stdout, one of: csv, tsv")
+	cmd.Flags().StringVarP(&infoOpts.output, "output", "o", "", "print the info in a machine-readable format, one of: json, yaml")
 	infoOpts.complyTimeOpts.BindFlags(cmd.Flags())
 	return cmd
 }
 
+// validateInfo validates the info command options.
+func validateInfo(opts *infoOptions) error {
+	if err := validateOutputFormat(opts.output); err != nil {
+		return err
+	}
+	if opts.export == "" {
+		return nil
+	}
+	if opts.output != "" {
+		return errors.New("invalid command flags: \"--export\" cannot be used with \"--output\"")
+	}
+	if opts.export != report.FormatCSV && opts.export != report.FormatTSV {
+		return fmt.Errorf("invalid export format %q: must be one of csv, tsv", opts.export)
+	}
+	if opts.controlID != "" || opts.ruleID != "" || opts.parameterID != "" {
+		return errors.New("invalid command flags: \"--export\" cannot be used with \"--control\", \"--rule\", or \"--parameter\"")
+	}
+	return nil
+}
+
 // runInfo executes the info command using the provided options.
 func runInfo(opts *infoOptions) error {
 
@@ -164,6 +220,19 @@ func runInfo(opts *infoOptions) error {
 
 	indexedControls, indexedSetParameters := processControlImplementations(frameworkComponents, rulePlugins, appDir, validator)
 
+	if opts.export != "" {
+		rows := getControlMatrixRows(indexedControls, ruleRemarks, remarksProps, indexedSetParameters)
+		return report.WriteDelimited(opts.Out, opts.export, controlMatrixHeader, rows)
+	}
+
+	if opts.output != "" {
+		info, err := newFrameworkInfo(opts, indexedControls, rulePlugins, ruleRemarks, remarksProps, indexedSetParameters)
+		if err != nil {
+			return err
+		}
+		return writeStructured(opts.Out, opts.output, info)
+	}
+
 	// Display info based on controlID, ruleID, or parameterID flag being passed at CLI
 	if opts.controlID != "" {
 		return displayControlInfo(opts, indexedControls)
@@ -788,31 +857,9 @@ func displayParameterInfo(opts *infoOptions, parameterID string, indexedSetParam
 		return fmt.Errorf("parameter '%s' does not exist in framework '%s'", parameterID, opts.complyTimeOpts.FrameworkID)
 	}
 
-	var paramDescription string
-
-	// Find description for parameter across all remarks
-	for _, props := range remarksProps {
-		for _, prop := range props {
-			// Check for Parameter_Id patterns
-			if isParameterIdProperty(prop.Name) && prop.Value == parameterID {
-				// Found the parameter, look for its description in the same property set
-				for _, descProp := range props {
-					if isParameterDescriptionProperty(descProp.Name) {
-						paramDescription = descProp.Value
-						break
-					}
-				}
-				break
-			}
-		}
-		if paramDescription != "" {
-			break
-		}
-	}
-
 	paramDetails := parameter{
 		ID:          parameterID,
-		Description: paramDescription,
+		Description: findParameterDescription(parameterID, remarksProps),
 		Values:      paramValues,
 	}
 
@@ -852,20 +899,48 @@ func displayParameterInfo(opts *infoOptions, parameterID string, indexedSetParam
 	}
 }
 
-// displayRuleInfo handles displaying information for a specific rule.
-func displayRuleInfo(opts *infoOptions, ruleID string, ruleRemarksMap ruleRemarksMap, remarksPropsMap remarksPropertiesMap, setParameters indexedSetParameters) error {
+// findParameterDescription finds the description of a parameter across all remarks.
+func findParameterDescription(parameterID string, remarksProps remarksPropertiesMap) string {
+	for _, props := range remarksProps {
+		for _, prop := range props {
+			// Check for Parameter_Id patterns
+			if isParameterIdProperty(prop.Name) && prop.Value == parameterID {
+				// Found the parameter, look for its description in the same property set
+				for _, descProp := range props {
+					if isParameterDescriptionProperty(descProp.Name) && descProp.Value != "" {
+						return descProp.Value
+					}
+				}
+				break
+			}
+		}
+	}
+	return ""
+}
+
+// findRuleDetails returns the details of a rule from the rule set of the rule in the component properties.
+func findRuleDetails(ruleID string, ruleRemarksMap ruleRemarksMap, remarksPropsMap remarksPropertiesMap) (rule, error) {
 	remarksForRule, ok := ruleRemarksMap[ruleID]
 	if !ok || remarksForRule == "" {
-		return fmt.Errorf("rule '%s' remarks not found", ruleID)
+		return rule{}, fmt.Errorf("rule '%s' remarks not found", ruleID)
 	}
 
 	propsForRule, ok := remarksPropsMap[remarksForRule]
 	if !ok || len(propsForRule) == 0 {
-		return fmt.Errorf("properties for rule '%s' (remarks '%s') not found", ruleID, remarksForRule)
+		return rule{}, fmt.Errorf("properties for rule '%s' (remarks '%s') not found", ruleID, remarksForRule)
 	}
 
 	ruleDetails := extractRuleDetails(propsForRule)
 	ruleDetails.ID = ruleID // Ensure ID is set for consistency
+	return ruleDetails, nil
+}
+
+// displayRuleInfo handles displaying information for a specific rule.
+func displayRuleInfo(opts *infoOptions, ruleID string, ruleRemarksMap ruleRemarksMap, remarksPropsMap remarksPropertiesMap, setParameters indexedSetParameters) error {
+	ruleDetails, err := findRuleDetails(ruleID, ruleRemarksMap, remarksPropsMap)
+	if err != nil {
+		return err
+	}
 
 	if opts.plain {
 		_, _ = fmt.Fprintf(opts.Out, "Rule ID: %s \n", ruleDetails.ID)
@@ -898,6 +973,114 @@ func displayAllControls(opts *infoOptions, indexedControls indexedControls) erro
 	}
 }
 
+// getControlMatrixRows returns one row per control, rule, and parameter sorted by control and rule ID.
+// Rule details are resolved from the rule set of the rule in the component properties when available.
+func getControlMatrixRows(indexedControls indexedControls, ruleRemarks ruleRemarksMap, remarksProps remarksPropertiesMap, setParameters indexedSetParameters) [][]string {
+	var controls []control
+	for _, control := range indexedControls {
+		controls = append(controls, control)
+	}
+	sort.Slice(controls, func(i, j int) bool {
+		return controls[i].ID < controls[j].ID
+	})
+
+	var rows [][]string
+	for _, control := range controls {
+		if len(control.Rules) == 0 {
+			rows = append(rows, []string{control.ID, control.Title, control.ImplementationStatus, "", "", "", "", "", ""})
+			continue
+		}
+		sort.Slice(control.Rules, func(i, j int) bool {
+			return control.Rules[i].ID < control.Rules[j].ID
+		})
+		for _, controlRule := range control.Rules {
+			ruleDetails := resolveRuleDetails(controlRule, ruleRemarks, remarksProps)
+			ruleRow := []string{control.ID, control.Title, control.ImplementationStatus, ruleDetails.ID, ruleDetails.Description, ruleDetails.Plugin}
+			if len(ruleDetails.Parameters) == 0 {
+				rows = append(rows, append(ruleRow, "", "", ""))
+				continue
+			}
+			for _, param := range ruleDetails.Parameters {
+				row := append([]string{}, ruleRow...)
+				rows = append(rows, append(row, param.ID, param.Description, strings.Join(setParameters[param.ID], "; ")))
+			}
+		}
+	}
+	return rows
+}
+
+// resolveRuleDetails returns the rule with the details from the rule set of the rule in the
+// component properties when available.
+func resolveRuleDetails(controlRule rule, ruleRemarks ruleRemarksMap, remarksProps remarksPropertiesMap) rule {
+	ruleDetails, err := findRuleDetails(controlRule.ID, ruleRemarks, remarksProps)
+	if err != nil {
+		return controlRule
+	}
+	ruleDetails.Plugin = controlRule.Plugin
+	return ruleDetails
+}
+
+// newFrameworkInfo returns the machine-readable info of the framework, control, rule, or parameter
+// selected in the options. Rule parameters carry their set values and valid alternatives.
+func newFrameworkInfo(opts *infoOptions, indexedControls indexedControls, rulePlugins rulePluginMap, ruleRemarks ruleRemarksMap, remarksProps remarksPropertiesMap, setParameters indexedSetParameters) (frameworkInfo, error) {
+	info := frameworkInfo{FrameworkID: opts.complyTimeOpts.FrameworkID}
+	withValues := func(ruleDetails rule) rule {
+		var parameters []parameter
+		for _, param := range ruleDetails.Parameters {
+			param.Values = setParameters[param.ID]
+			param.Alternatives = findParameterAlternativesFromRemarks(param.ID, remarksProps)
+			parameters = append(parameters, param)
+		}
+		ruleDetails.Parameters = parameters
+		return ruleDetails
+	}
+	withRules := func(controlDetails control) control {
+		var rules []rule
+		for _, controlRule := range controlDetails.Rules {
+			rules = append(rules, withValues(resolveRuleDetails(controlRule, ruleRemarks, remarksProps)))
+		}
+		sort.Slice(rules, func(i, j int) bool { return rules[i].ID < rules[j].ID })
+		controlDetails.Rules = rules
+		return controlDetails
+	}
+
+	switch {
+	case opts.controlID != "":
+		selected, ok := indexedControls[opts.controlID]
+		if !ok {
+			return info, fmt.Errorf("control '%s' does not exist in workspace", opts.controlID)
+		}
+		info.Controls = []control{withRules(selected)}
+	case opts.ruleID != "":
+		ruleDetails, err := findRuleDetails(opts.ruleID, ruleRemarks, remarksProps)
+		if err != nil {
+			return info, err
+		}
+		ruleDetails.Plugin = rulePlugins[ruleDetails.ID]
+		info.Rules = []rule{withValues(ruleDetails)}
+	case opts.parameterID != "":
+		paramValues, ok := setParameters[opts.parameterID]
+		if !ok {
+			return info, fmt.Errorf("parameter '%s' does not exist in framework '%s'", opts.parameterID, opts.complyTimeOpts.FrameworkID)
+		}
+		rules := findRulesUsingParameter(opts.parameterID, ruleRemarks, remarksProps)
+		sort.Strings(rules)
+		info.Parameters = []parameter{{
+			ID:           opts.parameterID,
+			Description:  findParameterDescription(opts.parameterID, remarksProps),
+			Values:       paramValues,
+			Alternatives: findParameterAlternativesFromRemarks(opts.parameterID, remarksProps),
+			Rules:        rules,
+		}}
+	default:
+		for _, controlDetails := range indexedControls {
+			info.Controls = append(info.Controls, withRules(controlDetails))
+		}
+		sort.Slice(info.Controls, func(i, j int) bool { return info.Controls[i].ID < info.Controls[j].ID })
+	}
+	return info, nil
+}
+
 // calculateRowLimit determines how many rows should be displayed based
 // on the number of rows available and the limit set by the user.
 func calculateRowLimit(rowLimit int, availableRows int) int {
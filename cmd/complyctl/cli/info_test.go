@@ -7,7 +7,10 @@ import (
 	"testing"
 
 	"github.com/charmbracelet/bubbles/table"
+	"github.com/oscal-compass/oscal-sdk-go/extensions"
 	"github.com/stretchr/testify/require"
+
+	"github.com/complytime/complyctl/cmd/complyctl/option"
 )
 
 func TestRenderKeyValuePair(t *testing.T) {
@@ -224,3 +227,180 @@ func TestCalculateRowLimit(t *testing.T) {
 		})
 	}
 }
+
+func TestGetControlMatrixRows(t *testing.T) {
+	controls := indexedControls{
+		"r2": {ID: "r2", Title: "Control 2", ImplementationStatus: "planned"},
+		"r1": {
+			ID:                   "r1",
+			Title:                "Control 1",
+			ImplementationStatus: "implemented",
+			Rules: []rule{
+				{ID: "rule-2", Plugin: "openscap"},
+				{ID: "rule-1", Plugin: "openscap"},
+			},
+		},
+	}
+	ruleRemarks := ruleRemarksMap{"rule-1": "rule_set_00"}
+	remarksProps := remarksPropertiesMap{
+		"rule_set_00": {
+			{Name: extensions.RuleIdProp, Value: "rule-1", Remarks: "rule_set_00"},
+			{Name: extensions.RuleDescriptionProp, Value: "My first rule", Remarks: "rule_set_00"},
+			{Name: extensions.ParameterIdProp, Value: "param-1", Remarks: "rule_set_00"},
+			{Name: extensions.ParameterDescriptionProp, Value: "A parameter", Remarks: "rule_set_00"},
+		},
+	}
+	setParameters := indexedSetParameters{"param-1": {"value-1", "value-2"}}
+
+	expectedRows := [][]string{
+		{"r1", "Control 1", "implemented", "rule-1", "My first rule", "openscap", "param-1", "A parameter", "value-1; value-2"},
+		{"r1", "Control 1", "implemented", "rule-2", "", "openscap", "", "", ""},
+		{"r2", "Control 2", "planned", "", "", "", "", "", ""},
+	}
+	rows := getControlMatrixRows(controls, ruleRemarks, remarksProps, setParameters)
+	require.Equal(t, expectedRows, rows)
+}
+
+func TestNewFrameworkInfo(t *testing.T) {
+	controls := indexedControls{
+		"r2": {ID: "r2", Title: "Control 2", ImplementationStatus: "planned"},
+		"r1": {
+			ID:                   "r1",
+			Title:                "Control 1",
+			ImplementationStatus: "implemented",
+			Rules: []rule{
+				{ID: "rule-2", Plugin: "openscap"},
+				{ID: "rule-1", Plugin: "openscap"},
+			},
+		},
+	}
+	rulePlugins := rulePluginMap{"rule-1": "openscap", "rule-2": "openscap"}
+	ruleRemarks := ruleRemarksMap{"rule-1": "rule_set_00"}
+	remarksProps := remarksPropertiesMap{
+		"rule_set_00": {
+			{Name: extensions.RuleIdProp, Value: "rule-1", Remarks: "rule_set_00"},
+			{Name: extensions.RuleDescriptionProp, Value: "My first rule", Remarks: "rule_set_00"},
+			{Name: extensions.ParameterIdProp, Value: "param-1", Remarks: "rule_set_00"},
+			{Name: extensions.ParameterDescriptionProp, Value: "A parameter", Remarks: "rule_set_00"},
+			{Name: "Parameter_Value_Alternatives_1", Value: `{"value-1": "value-1", "value-2": "value-2"}`, Remarks: "rule_set_00"},
+		},
+	}
+	setParameters := indexedSetParameters{"param-1": {"value-1"}}
+	param := parameter{ID: "param-1", Description: "A parameter", Values: []string{"value-1"}, Alternatives: []string{"value-1", "value-2"}}
+	rule1 := rule{ID: "rule-1", Plugin: "openscap", Description: "My first rule", Parameters: []parameter{param}}
+	control1 := control{
+		ID:                   "r1",
+		Title:                "Control 1",
+		ImplementationStatus: "implemented",
+		Rules:                []rule{rule1, {ID: "rule-2", Plugin: "openscap"}},
+	}
+
+	tests := []struct {
+		name    string
+		opts    infoOptions
+		want    frameworkInfo
+		wantErr string
+	}{
+		{
+			name: "Valid/Framework",
+			want: frameworkInfo{FrameworkID: "example", Controls: []control{
+				control1,
+				{ID: "r2", Title: "Control 2", ImplementationStatus: "planned"},
+			}},
+		},
+		{
+			name: "Valid/Control",
+			opts: infoOptions{controlID: "r1"},
+			want: frameworkInfo{FrameworkID: "example", Controls: []control{control1}},
+		},
+		{
+			name: "Valid/Rule",
+			opts: infoOptions{ruleID: "rule-1"},
+			want: frameworkInfo{FrameworkID: "example", Rules: []rule{rule1}},
+		},
+		{
+			name: "Valid/Parameter",
+			opts: infoOptions{parameterID: "param-1"},
+			want: frameworkInfo{FrameworkID: "example", Parameters: []parameter{{
+				ID:           "param-1",
+				Description:  "A parameter",
+				Values:       []string{"value-1"},
+				Alternatives: []string{"value-1", "value-2"},
+				Rules:        []string{"rule-1"},
+			}}},
+		},
+		{
+			name:    "Invalid/UnknownControl",
+			opts:    infoOptions{controlID: "r9"},
+			wantErr: "control 'r9' does not exist in workspace",
+		},
+		{
+			name:    "Invalid/UnknownParameter",
+			opts:    infoOptions{parameterID: "param-9"},
+			wantErr: "parameter 'param-9' does not exist in framework 'example'",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.opts.complyTimeOpts = &option.ComplyTime{FrameworkID: "example"}
+			info, err := newFrameworkInfo(&tt.opts, controls, rulePlugins, ruleRemarks, remarksProps, setParameters)
+			if tt.wantErr != "" {
+				require.EqualError(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.want, info)
+		})
+	}
+}
+
+func TestValidateInfo(t *testing.T) {
+	tests := []struct {
+		name    string
+		opts    infoOptions
+		wantErr string
+	}{
+		{
+			name: "Valid/NoExport",
+			opts: infoOptions{controlID: "r1"},
+		},
+		{
+			name: "Valid/ExportTSV",
+			opts: infoOptions{export: "tsv"},
+		},
+		{
+			name:    "Invalid/ExportFormat",
+			opts:    infoOptions{export: "xlsx"},
+			wantErr: "invalid export format \"xlsx\": must be one of csv, tsv",
+		},
+		{
+			name: "Valid/OutputYAML",
+			opts: infoOptions{output: "yaml", ruleID: "rule-1"},
+		},
+		{
+			name:    "Invalid/OutputFormat",
+			opts:    infoOptions{output: "xml"},
+			wantErr: "invalid output format \"xml\": must be one of json, yaml",
+		},
+		{
+			name:    "Invalid/ExportWithOutput",
+			opts:    infoOptions{export: "csv", output: "json"},
+			wantErr: "invalid command flags: \"--export\" cannot be used with \"--output\"",
+		},
+		{
+			name:    "Invalid/ExportWithControl",
+			opts:    infoOptions{export: "csv", controlID: "r1"},
+			wantErr: "invalid command flags: \"--export\" cannot be used with \"--control\", \"--rule\", or \"--parameter\"",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateInfo(&tt.opts)
+			if tt.wantErr != "" {
+				require.EqualError(t, err, tt.wantErr)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
@@ -24,6 +24,13 @@ type listOptions struct {
 	*option.Common
 	// print a plain table only
 	plain bool
+	// output format for machine-readable output
+	output string
+}
+
+// frameworkList is the machine-readable output of the "list" subcommand.
+type frameworkList struct {
+	Frameworks []complytime.Framework `json:"frameworks"`
 }
 
 // listCmd creates a new cobra.Command for the "list" subcommand
@@ -35,11 +42,17 @@ func listCmd(common *option.Common) *cobra.Command {
 		Use:          "list [flags]",
 		Short:        "List information about supported frameworks and components.",
 		SilenceUsage: true,
-		Example:      "complyctl list",
+		Example:      "complyctl list\ncomplyctl list --output json",
 		Args:         cobra.NoArgs,
-		RunE:         func(_ *cobra.Command, _ []string) error { return runList(listOpts) },
+		RunE: func(_ *cobra.Command, _ []string) error {
+			if err := validateOutputFormat(listOpts.output); err != nil {
+				return err
+			}
+			return runList(listOpts)
+		},
 	}
 	cmd.Flags().BoolVarP(&listOpts.plain, "plain", "p", false, "print the table with minimal formatting")
+	cmd.Flags().StringVarP(&listOpts.output, "output", "o", "", "print the frameworks in a machine-readable format, one of: json, yaml")
 	return cmd
 }
 
@@ -56,6 +69,10 @@ func runList(opts *listOptions) error {
 		return err
 	}
 
+	if opts.output != "" {
+		return writeStructured(opts.Out, opts.output, newFrameworkList(frameworks))
+	}
+
 	if opts.plain {
 		showDefinitionTable(opts.Out, frameworks)
 	} else {
@@ -67,6 +84,14 @@ func runList(opts *listOptions) error {
 	return nil
 }
 
+// newFrameworkList returns the frameworks sorted by ID for machine-readable output.
+func newFrameworkList(frameworks []complytime.Framework) frameworkList {
+	list := frameworkList{Frameworks: make([]complytime.Framework, 0, len(frameworks))}
+	list.Frameworks = append(list.Frameworks, frameworks...)
+	sort.SliceStable(list.Frameworks, func(i, j int) bool { return list.Frameworks[i].ID < list.Frameworks[j].ID })
+	return list
+}
+
 // ShowDefinitionTable prints a plain table with given framework data.
 func showDefinitionTable(writer io.Writer, frameworks []complytime.Framework) {
 	columns, rows := getDefinitionColumnsAndRows(frameworks)
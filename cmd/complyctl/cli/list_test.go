@@ -87,7 +87,79 @@ func TestShowDefinitionTable(t *testing.T) {
 	}
 }
 
+func TestWriteFrameworkList(t *testing.T) {
+	frameworks := []complytime.Framework{
+		{ID: "example", Title: "Example Profile (low)", SupportedComponents: []string{"My Software"}},
+		{ID: "anotherexample", Title: "Example Profile (moderate)", SupportedComponents: []string{"My Software"}},
+	}
+
+	tests := []struct {
+		name       string
+		format     string
+		frameworks []complytime.Framework
+		wantOutput string
+	}{
+		{
+			name:       "Valid/JSON",
+			format:     outputJSON,
+			frameworks: frameworks,
+			wantOutput: frameworksJSON,
+		},
+		{
+			name:       "Valid/YAML",
+			format:     outputYAML,
+			frameworks: frameworks,
+			wantOutput: frameworksYAML,
+		},
+		{
+			name:       "Valid/EmptyJSON",
+			format:     outputJSON,
+			wantOutput: "{\n  \"frameworks\": []\n}\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out := bytes.NewBuffer(nil)
+			require.NoError(t, writeStructured(out, tt.format, newFrameworkList(tt.frameworks)))
+			require.Equal(t, tt.wantOutput, out.String())
+		})
+	}
+
+	require.EqualError(t, writeStructured(bytes.NewBuffer(nil), "xml", newFrameworkList(frameworks)),
+		"invalid output format \"xml\": must be one of json, yaml")
+}
+
 var (
+	frameworksJSON = `{
+  "frameworks": [
+    {
+      "id": "anotherexample",
+      "title": "Example Profile (moderate)",
+      "supportedComponents": [
+        "My Software"
+      ]
+    },
+    {
+      "id": "example",
+      "title": "Example Profile (low)",
+      "supportedComponents": [
+        "My Software"
+      ]
+    }
+  ]
+}
+`
+	frameworksYAML = `frameworks:
+- id: anotherexample
+  title: Example Profile (moderate)
+  supportedComponents:
+  - My Software
+- id: example
+  title: Example Profile (low)
+  supportedComponents:
+  - My Software
+`
+
 	emptyTable = `┌──────────────────────────────────────────────────────────────────────────────────────┐
 │ Title                           Framework ID          Supported Components           │
 │──────────────────────────────────────────────────────────────────────────────────────│
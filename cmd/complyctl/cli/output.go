@@ -0,0 +1,50 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/goccy/go-yaml"
+)
+
+// Machine-readable output formats of the "list" and "info" subcommands
+const (
+	outputJSON = "json"
+	outputYAML = "yaml"
+)
+
+// validateOutputFormat checks that the format is empty or one of the machine-readable formats.
+func validateOutputFormat(format string) error {
+	switch format {
+	case "", outputJSON, outputYAML:
+		return nil
+	default:
+		return fmt.Errorf("invalid output format %q: must be one of json, yaml", format)
+	}
+}
+
+// writeStructured prints the value as JSON or YAML.
+func writeStructured(writer io.Writer, format string, value any) error {
+	var data []byte
+	var err error
+	switch format {
+	case outputJSON:
+		data, err = json.MarshalIndent(value, "", "  ")
+		if err != nil {
+			return fmt.Errorf("error marshalling json content: %w", err)
+		}
+		data = append(data, '\n')
+	case outputYAML:
+		data, err = yaml.Marshal(value)
+		if err != nil {
+			return fmt.Errorf("error marshalling yaml content: %w", err)
+		}
+	default:
+		return fmt.Errorf("invalid output format %q: must be one of json, yaml", format)
+	}
+	_, err = writer.Write(data)
+	return err
+}
@@ -3,34 +3,62 @@
 package cli
 
 import (
+	"context"
 	"errors"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
 
+	"github.com/charmbracelet/bubbles/table"
+	tea "github.com/charmbracelet/bubbletea"
 	oscalTypes "github.com/defenseunicorns/go-oscal/src/types/oscal-1-1-3"
 	"github.com/goccy/go-yaml"
 	"github.com/hashicorp/go-hclog"
+	"github.com/oscal-compass/oscal-sdk-go/extensions"
 	"github.com/oscal-compass/oscal-sdk-go/transformers"
 	"github.com/oscal-compass/oscal-sdk-go/validation"
 	"github.com/spf13/cobra"
 
 	"github.com/complytime/complyctl/cmd/complyctl/option"
 	"github.com/complytime/complyctl/internal/complytime"
+	"github.com/complytime/complyctl/internal/terminal"
 )
 
 const assessmentPlanLocation = "assessment-plan.json"
 
+// assessmentScopeLocation records the scope config applied to the assessment plan of the workspace
+const assessmentScopeLocation = "assessment-scope.yml"
+
 // PlanOptions defines options for the "plan" subcommand
 type planOptions struct {
 	*option.Common
 	complyTimeOpts *option.ComplyTime
+	verifyOpts     *option.Verification
+
+	// frameworkIDs are the frameworks to plan, each in its own subdirectory of the workspace when there are several
+	frameworkIDs []string
 
 	// dryRun loads the defaults and prints the config to stdout
 	dryRun bool
 
-	// WithScopeConfig "config.yml" to customize the generated assessment plan
-	withScopeConfig string
+	// scopeConfigs are the "config.yml" layers merged in order to customize the generated assessment plan
+	scopeConfigs []string
+
+	// interactive opens an editor to build the scope config
+	interactive bool
+
+	// strict fails when the scope config references unknown controls, rules, or parameters
+	strict bool
+
+	// refresh regenerates the assessment plans of the workspace with the scope recorded in it
+	refresh bool
+
+	// fromResults is the path of assessment results whose failed rules are re-checked by the plan
+	fromResults string
 
 	// Out
 	output string
@@ -48,6 +76,36 @@ complytime plan myframework --dry-run --out config.yml
 
 # Alter the configuration and use it as input for plan customization.
 complytime plan myframework --scope-config config.yml
+
+# Merge an environment overlay after the baseline configuration.
+complytime plan myframework --scope-config baseline.yml --scope-config prod.yml
+
+# Show the merged configuration with the layer of each value, and the controls and rules matched by its patterns.
+complytime plan myframework --dry-run --scope-config baseline.yml --scope-config prod.yml
+
+# Fail when config.yml references controls, rules, or parameters unknown to the framework.
+complytime plan myframework --scope-config config.yml --strict
+
+# Build the scope interactively and write the assessment plan.
+complytime plan myframework --interactive
+
+# Edit an existing configuration interactively and save it.
+complytime plan myframework --interactive --scope-config config.yml --out config.yml
+
+# Plan several frameworks in one workspace, each scoped by the config.yml selecting it.
+complytime plan myframework otherframework --scope-config myframework.yml --scope-config otherframework.yml
+
+# Regenerate the assessment plan from updated bundles with the scope used last time, and print the changes.
+complytime plan --refresh
+
+# Print the changes a refresh would make without writing the assessment plan.
+complytime plan --refresh --dry-run
+
+# Re-check only the rules that failed or errored in the latest scan.
+complytime plan myframework --from-results complytime/assessment-results.json
+
+# Write the scope of the re-check to a file instead.
+complytime plan myframework --from-results complytime/assessment-results.json --dry-run --out followup.yml
 `
 
 // planCmd creates a new cobra.Command for the "plan" subcommand
@@ -55,12 +113,19 @@ func planCmd(common *option.Common) *cobra.Command {
 	planOpts := &planOptions{
 		Common:         common,
 		complyTimeOpts: &option.ComplyTime{},
+		verifyOpts:     &option.Verification{},
 	}
 	cmd := &cobra.Command{
-		Use:     "plan [flags] id",
-		Short:   "Generate a new assessment plan for a given compliance framework id.",
+		Use:     "plan [flags] id...",
+		Short:   "Generate a new assessment plan for the given compliance framework ids.",
 		Example: planExample,
-		Args:    cobra.ExactArgs(1),
+		Args: func(cmd *cobra.Command, args []string) error {
+			// The frameworks to refresh are read from the workspace
+			if planOpts.refresh {
+				return nil
+			}
+			return cobra.MinimumNArgs(1)(cmd, args)
+		},
 		PreRun: func(cmd *cobra.Command, args []string) {
 			completePlan(planOpts, args)
 		},
@@ -72,26 +137,76 @@ func planCmd(common *option.Common) *cobra.Command {
 		},
 	}
 	cmd.Flags().BoolVar(&planOpts.dryRun, "dry-run", false, "load the defaults and print the config to stdout")
-	cmd.Flags().StringVarP(&planOpts.withScopeConfig, "scope-config", "s", "", "load config.yml to customize the generated assessment plan")
+	cmd.Flags().StringSliceVarP(&planOpts.scopeConfigs, "scope-config", "s", nil, "load config.yml to customize the generated assessment plan. Repeat to merge overlays in order.")
+	cmd.Flags().BoolVar(&planOpts.strict, "strict", false, "fail when the scope config references unknown controls, rules, or parameters")
+	cmd.Flags().BoolVarP(&planOpts.interactive, "interactive", "i", false, "browse the controls, rules, and parameters to build the scope config")
+	cmd.Flags().StringVar(&planOpts.fromResults, "from-results", "", "path to assessment results whose failed or errored rules are the only ones planned")
+	cmd.Flags().BoolVar(&planOpts.refresh, "refresh", false, "regenerate the assessment plans of the workspace from the current bundles with the scope used last time")
 	cmd.Flags().StringVarP(&planOpts.output, "out", "o", "-", "path to output file. Use '-' for stdout. Default '-'.")
 	planOpts.complyTimeOpts.BindFlags(cmd.Flags())
+	planOpts.verifyOpts.BindFlags(cmd.Flags())
 	return cmd
 }
 
 func completePlan(opts *planOptions, args []string) {
-	if len(args) == 1 {
-		opts.complyTimeOpts.FrameworkID = filepath.Clean(args[0])
+	opts.frameworkIDs = nil
+	for _, arg := range args {
+		frameworkID := filepath.Clean(arg)
+		if !slices.Contains(opts.frameworkIDs, frameworkID) {
+			opts.frameworkIDs = append(opts.frameworkIDs, frameworkID)
+		}
+	}
+	if len(opts.frameworkIDs) > 0 {
+		opts.complyTimeOpts.FrameworkID = opts.frameworkIDs[0]
 	}
 }
 
 func validatePlan(opts *planOptions) error {
-	if opts.output != "-" && !opts.dryRun {
-		return errors.New("invalid command flags: \"--dry-run\" must be used with \"--out\"")
+	if opts.refresh {
+		switch {
+		case len(opts.frameworkIDs) > 0:
+			return errors.New("invalid command flags: \"--refresh\" cannot be used with framework ids")
+		case opts.interactive || len(opts.scopeConfigs) > 0:
+			return errors.New("invalid command flags: \"--refresh\" cannot be used with \"--interactive\" or \"--scope-config\"")
+		case opts.output != "-":
+			return errors.New("invalid command flags: \"--refresh\" cannot be used with \"--out\"")
+		}
+		return nil
+	}
+	for _, frameworkID := range opts.frameworkIDs {
+		// The plan of each framework is written to a subdirectory of the workspace named after it
+		if filepath.IsAbs(frameworkID) || strings.ContainsAny(frameworkID, `/\`) || frameworkID == "." || frameworkID == ".." {
+			return fmt.Errorf("invalid framework id %q: must not be a path", frameworkID)
+		}
+	}
+	if opts.fromResults != "" {
+		switch {
+		case opts.refresh || opts.interactive || len(opts.scopeConfigs) > 0:
+			return errors.New("invalid command flags: \"--from-results\" cannot be used with \"--refresh\", \"--interactive\", or \"--scope-config\"")
+		case len(opts.frameworkIDs) > 1:
+			return errors.New("invalid command flags: \"--from-results\" can only be used with a single framework")
+		}
+	}
+	if len(opts.frameworkIDs) > 1 && (opts.dryRun || opts.interactive) {
+		return errors.New("invalid command flags: \"--dry-run\" and \"--interactive\" can only be used with a single framework")
+	}
+	if opts.dryRun && opts.interactive {
+		return errors.New("invalid command flags: \"--dry-run\" and \"--interactive\" cannot be used together")
+	}
+	if opts.strict && len(opts.scopeConfigs) == 0 {
+		return errors.New("invalid command flags: \"--strict\" must be used with \"--scope-config\"")
+	}
+	if opts.output != "-" && !opts.dryRun && !opts.interactive {
+		return errors.New("invalid command flags: \"--dry-run\" or \"--interactive\" must be used with \"--out\"")
 	}
 	return nil
 }
 
 func runPlan(cmd *cobra.Command, opts *planOptions) error {
+	if opts.refresh {
+		return refreshPlans(cmd, opts)
+	}
+
 	// Create the application directory if it does not exist
 	appDir, err := complytime.NewApplicationDirectory(true, logger)
 	if err != nil {
@@ -99,37 +214,232 @@ func runPlan(cmd *cobra.Command, opts *planOptions) error {
 	}
 	logger.Debug(fmt.Sprintf("Using application directory: %s", appDir.AppDir()))
 
+	signatureResults, err := verifyContent(appDir, opts.verifyOpts)
+	if err != nil {
+		return err
+	}
+
 	validator := validation.NewSchemaValidator()
 	componentDefs, err := complytime.FindComponentDefinitions(appDir.BundleDir(), validator)
 	if err != nil {
 		return err
 	}
+	logger.Debug(fmt.Sprintf("Using bundle directory: %s for component definitions.", appDir.BundleDir()))
+
+	if len(opts.frameworkIDs) > 1 {
+		return planFrameworks(cmd, opts, appDir, componentDefs, signatureResults)
+	}
+	return planFramework(cmd, opts, appDir, componentDefs, signatureResults)
+}
+
+// planFrameworks writes the assessment plan of each framework to a subdirectory of the workspace named
+// after the framework. Each scope config is applied to the framework it selects.
+func planFrameworks(cmd *cobra.Command, opts *planOptions, appDir complytime.ApplicationDirectory, componentDefs []oscalTypes.ComponentDefinition, signatureResults []complytime.SignatureResult) error {
+	scopeConfigs, err := groupScopeConfigs(opts.scopeConfigs)
+	if err != nil {
+		return err
+	}
+	for frameworkID, paths := range scopeConfigs {
+		if !slices.Contains(opts.frameworkIDs, frameworkID) {
+			return fmt.Errorf("scope config %s selects framework %q, which is not planned", paths[0], frameworkID)
+		}
+	}
+
+	workspace := opts.complyTimeOpts.UserWorkspace
+	for _, frameworkID := range opts.frameworkIDs {
+		frameworkOpts := *opts
+		frameworkOpts.complyTimeOpts = &option.ComplyTime{
+			UserWorkspace: filepath.Join(workspace, frameworkID),
+			FrameworkID:   frameworkID,
+		}
+		frameworkOpts.frameworkIDs = []string{frameworkID}
+		frameworkOpts.scopeConfigs = scopeConfigs[frameworkID]
+		if err := planFramework(cmd, &frameworkOpts, appDir, componentDefs, signatureResults); err != nil {
+			return fmt.Errorf("error planning framework %s: %w", frameworkID, err)
+		}
+	}
+
+	// A plan at the root of the workspace would be used instead of the plans of the frameworks.
+	rootPlan := filepath.Clean(filepath.Join(workspace, assessmentPlanLocation))
+	if err := os.Remove(rootPlan); err == nil {
+		logger.Info(fmt.Sprintf("Removed the assessment plan %s replaced by the plans of the frameworks.", rootPlan))
+	} else if !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+// groupScopeConfigs returns the scope config paths by the framework they select, in order.
+func groupScopeConfigs(paths []string) (map[string][]string, error) {
+	scopeConfigs := make(map[string][]string)
+	for _, path := range paths {
+		layered, err := complytime.LoadLayeredScope(path)
+		if err != nil {
+			return nil, err
+		}
+		frameworkID := layered.Scope.FrameworkID
+		if frameworkID == "" {
+			return nil, fmt.Errorf("scope config %s does not set a frameworkId", path)
+		}
+		scopeConfigs[frameworkID] = append(scopeConfigs[frameworkID], path)
+	}
+	return scopeConfigs, nil
+}
+
+// planFramework writes the assessment plan of a single framework to the workspace.
+func planFramework(cmd *cobra.Command, opts *planOptions, appDir complytime.ApplicationDirectory, componentDefs []oscalTypes.ComponentDefinition, signatureResults []complytime.SignatureResult) error {
+	if len(opts.scopeConfigs) > 0 {
+		if err := checkScopeConfig(opts, appDir, componentDefs); err != nil {
+			return err
+		}
+	}
+
+	if opts.fromResults != "" {
+		return planFollowUp(cmd, opts, appDir, componentDefs, signatureResults)
+	}
 
 	if opts.dryRun {
+		if len(opts.scopeConfigs) > 0 {
+			// Write the scope config with its selectors and patterns expanded
+			return planDryRunScope(opts, appDir, componentDefs)
+		}
 		// Write the plan configuration to stdout
 		return planDryRun(opts.complyTimeOpts.FrameworkID, componentDefs, opts.output, logger)
 	}
 
-	logger.Debug(fmt.Sprintf("Using bundle directory: %s for component definitions.", appDir.BundleDir()))
-	assessmentPlan, err := transformers.ComponentDefinitionsToAssessmentPlan(cmd.Context(), componentDefs, opts.complyTimeOpts.FrameworkID)
+	if opts.interactive {
+		return planInteractive(cmd, opts, appDir, componentDefs, signatureResults)
+	}
+
+	var assessmentScope *complytime.AssessmentScope
+	if len(opts.scopeConfigs) > 0 {
+		// The scope is recorded with its selectors and patterns, which are expanded when it is applied
+		layered, err := complytime.LoadLayeredScope(opts.scopeConfigs...)
+		if err != nil {
+			return err
+		}
+		assessmentScope = &layered.Scope
+	}
+	return writeScopedPlan(cmd.Context(), opts, appDir, componentDefs, assessmentScope, signatureResults)
+}
+
+// planFollowUp writes an assessment plan that re-checks only the rules that failed or errored in the
+// assessment results, with the parameter values of the assessment plan in the workspace.
+func planFollowUp(cmd *cobra.Command, opts *planOptions, appDir complytime.ApplicationDirectory, componentDefs []oscalTypes.ComponentDefinition, signatureResults []complytime.SignatureResult) error {
+	validator := validation.NewSchemaValidator()
+	assessmentResults, err := complytime.ReadAssessmentResults(filepath.Clean(opts.fromResults), validator)
 	if err != nil {
 		return err
 	}
+	planPath := filepath.Clean(filepath.Join(opts.complyTimeOpts.UserWorkspace, assessmentPlanLocation))
+	assessmentPlan, err := complytime.ReadPlan(planPath, validator)
+	if err != nil {
+		logger.Warn(fmt.Sprintf("The default parameter values of the framework are used, the assessment plan %s cannot be read: %v", planPath, err))
+		assessmentPlan = nil
+	}
 
-	if opts.withScopeConfig != "" {
-		configBytes, err := os.ReadFile(filepath.Clean(opts.withScopeConfig))
+	assessmentScope := complytime.FollowUpScope(opts.complyTimeOpts.FrameworkID, assessmentResults, assessmentPlan)
+	if len(assessmentScope.IncludeControls) == 0 {
+		return fmt.Errorf("no rules failed or errored in %s, there is nothing to re-check", opts.fromResults)
+	}
+	logger.Info(fmt.Sprintf("Planning a re-check of %d control(s) with failed or errored rules.", len(assessmentScope.IncludeControls)))
+
+	if opts.dryRun {
+		data, err := yaml.Marshal(&assessmentScope)
 		if err != nil {
-			return fmt.Errorf("error reading assessment plan: %w", err)
+			return fmt.Errorf("error marshalling yaml content: %w", err)
 		}
-		assessmentScope := complytime.AssessmentScope{}
-		if err := yaml.Unmarshal(configBytes, &assessmentScope); err != nil {
-			return fmt.Errorf("error unmarshaling assessment plan: %w", err)
+		return writeScopeData(data, opts.output)
+	}
+	// The re-check scope is not recorded, so a refresh restores the scope of the plan it re-checks.
+	assessmentPlan, err = newScopedPlan(cmd.Context(), opts, appDir, componentDefs, &assessmentScope, signatureResults)
+	if err != nil {
+		return err
+	}
+	// The scope is recorded in the plan instead, so the results of its scan are reported as partial
+	complytime.AddFollowUpProps(assessmentPlan, assessmentScope)
+	return writePlan(opts, assessmentPlan)
+}
+
+// checkScopeConfig reports the unknown controls, rules, and parameters in the scope config.
+// They are logged as warnings unless strict mode is enabled, where they fail the command.
+func checkScopeConfig(opts *planOptions, appDir complytime.ApplicationDirectory, componentDefs []oscalTypes.ComponentDefinition) error {
+	issues, err := lintScopeConfig(opts.scopeConfigs, appDir, componentDefs)
+	if err != nil {
+		if opts.strict {
+			return err
 		}
-		if err := assessmentScope.ApplyScope(assessmentPlan, logger, componentDefs...); err != nil {
-			return fmt.Errorf("error applying assessment scope: %w", err)
+		logger.Warn(err.Error())
+		return nil
+	}
+	if opts.strict {
+		writeScopeIssues(opts.Out, issues)
+		if len(issues) > 0 {
+			return fmt.Errorf("%w: %d", errScopeProblems, len(issues))
 		}
+		return nil
+	}
+	for _, issue := range issues {
+		logger.Warn(formatScopeIssue(issue))
+	}
+	return nil
+}
+
+// loadScopeConfig merges the scope config layers and replaces their control selectors and rule
+// patterns with the controls and rules they match in the framework.
+func loadScopeConfig(opts *planOptions, appDir complytime.ApplicationDirectory, componentDefs []oscalTypes.ComponentDefinition) (complytime.LayeredScope, error) {
+	layered, err := complytime.LoadLayeredScope(opts.scopeConfigs...)
+	if err != nil {
+		return complytime.LayeredScope{}, err
 	}
+	frameworkID := opts.complyTimeOpts.FrameworkID
+	controlGroups := complytime.ControlGroups(frameworkID, appDir, validation.NewSchemaValidator(), componentDefs...)
+	expanded, err := layered.ExpandPatterns(complytime.ControlRules(frameworkID, componentDefs), controlGroups)
+	if err != nil {
+		return complytime.LayeredScope{}, fmt.Errorf("error expanding assessment scope: %w", err)
+	}
+	return expanded, nil
+}
 
+// writeScopedPlan writes the assessment plan of the framework to the workspace with the scope applied,
+// and records the scope in the workspace for refreshes.
+func writeScopedPlan(ctx context.Context, opts *planOptions, appDir complytime.ApplicationDirectory, componentDefs []oscalTypes.ComponentDefinition, assessmentScope *complytime.AssessmentScope, signatureResults []complytime.SignatureResult) error {
+	assessmentPlan, err := newScopedPlan(ctx, opts, appDir, componentDefs, assessmentScope, signatureResults)
+	if err != nil {
+		return err
+	}
+	if err := writePlan(opts, assessmentPlan); err != nil {
+		return err
+	}
+	return recordScope(opts.complyTimeOpts, assessmentScope)
+}
+
+// newScopedPlan returns the assessment plan of the framework with the scope applied. The control selectors
+// and rule patterns of the scope are matched against the component definitions.
+func newScopedPlan(ctx context.Context, opts *planOptions, appDir complytime.ApplicationDirectory, componentDefs []oscalTypes.ComponentDefinition, assessmentScope *complytime.AssessmentScope, signatureResults []complytime.SignatureResult) (*oscalTypes.AssessmentPlan, error) {
+	frameworkID := opts.complyTimeOpts.FrameworkID
+	assessmentPlan, err := transformers.ComponentDefinitionsToAssessmentPlan(ctx, componentDefs, frameworkID)
+	if err != nil {
+		return nil, err
+	}
+
+	if assessmentScope != nil {
+		controlGroups := complytime.ControlGroups(frameworkID, appDir, validation.NewSchemaValidator(), componentDefs...)
+		expanded, err := assessmentScope.ExpandPatterns(complytime.ControlRules(frameworkID, componentDefs), controlGroups)
+		if err != nil {
+			return nil, fmt.Errorf("error expanding assessment scope: %w", err)
+		}
+		if err := expanded.ApplyScope(assessmentPlan, logger, componentDefs...); err != nil {
+			return nil, fmt.Errorf("error applying assessment scope: %w", err)
+		}
+	}
+
+	complytime.AddSignatureProps(assessmentPlan, signatureResults, opts.verifyOpts.InsecureSkipVerify)
+	return assessmentPlan, nil
+}
+
+// writePlan writes the assessment plan to the workspace.
+func writePlan(opts *planOptions, assessmentPlan *oscalTypes.AssessmentPlan) error {
 	filePath := filepath.Join(opts.complyTimeOpts.UserWorkspace, assessmentPlanLocation)
 	cleanedPath := filepath.Clean(filePath)
 
@@ -140,6 +450,178 @@ func runPlan(cmd *cobra.Command, opts *planOptions) error {
 	return nil
 }
 
+// recordScope writes the scope applied to the assessment plan to the workspace. The recorded scope
+// of an earlier plan is removed when the plan has the default scope of the framework.
+func recordScope(opts *option.ComplyTime, assessmentScope *complytime.AssessmentScope) error {
+	scopePath := filepath.Clean(filepath.Join(opts.UserWorkspace, assessmentScopeLocation))
+	if assessmentScope == nil {
+		if err := os.Remove(scopePath); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+		return nil
+	}
+	recorded := *assessmentScope
+	recorded.Extends = ""
+	if recorded.FrameworkID == "" {
+		recorded.FrameworkID = opts.FrameworkID
+	}
+	data, err := yaml.Marshal(&recorded)
+	if err != nil {
+		return fmt.Errorf("error marshalling yaml content: %w", err)
+	}
+	if err := os.WriteFile(scopePath, data, 0600); err != nil {
+		return fmt.Errorf("error recording assessment scope to %s: %w", scopePath, err)
+	}
+	return nil
+}
+
+// recordedScope returns the scope recorded in the workspace, or nil when the assessment plan has the
+// default scope of the framework.
+func recordedScope(opts *option.ComplyTime) (*complytime.AssessmentScope, error) {
+	layered, err := complytime.LoadLayeredScope(filepath.Join(opts.UserWorkspace, assessmentScopeLocation))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &layered.Scope, nil
+}
+
+// refreshPlans regenerates the assessment plan of each framework of the workspace.
+func refreshPlans(cmd *cobra.Command, opts *planOptions) error {
+	workspaces, err := frameworkWorkspaces(opts.complyTimeOpts)
+	if err != nil {
+		return err
+	}
+
+	appDir, err := complytime.NewApplicationDirectory(true, logger)
+	if err != nil {
+		return err
+	}
+	logger.Debug(fmt.Sprintf("Using application directory: %s", appDir.AppDir()))
+	signatureResults, err := verifyContent(appDir, opts.verifyOpts)
+	if err != nil {
+		return err
+	}
+	componentDefs, err := complytime.FindComponentDefinitions(appDir.BundleDir(), validation.NewSchemaValidator())
+	if err != nil {
+		return err
+	}
+
+	for _, workspace := range workspaces {
+		frameworkOpts := *opts
+		frameworkOpts.complyTimeOpts = workspace
+		if err := refreshPlan(cmd, &frameworkOpts, appDir, componentDefs, signatureResults); err != nil {
+			if len(workspaces) > 1 {
+				return fmt.Errorf("error refreshing framework %s: %w", workspace.FrameworkID, err)
+			}
+			return err
+		}
+	}
+	return nil
+}
+
+// refreshPlan regenerates the assessment plan of the workspace from the current component definitions
+// with the recorded scope, and prints the changes before the plan is overwritten.
+func refreshPlan(cmd *cobra.Command, opts *planOptions, appDir complytime.ApplicationDirectory, componentDefs []oscalTypes.ComponentDefinition, signatureResults []complytime.SignatureResult) error {
+	validator := validation.NewSchemaValidator()
+	oldPlan, _, err := loadPlan(opts.complyTimeOpts, validator)
+	if err != nil {
+		return err
+	}
+	if oldPlan.Metadata.Props == nil {
+		return fmt.Errorf("error reading framework property from assessment plan")
+	}
+	frameworkProp, valid := extensions.GetTrestleProp(extensions.FrameworkProp, *oldPlan.Metadata.Props)
+	if !valid {
+		return fmt.Errorf("error reading framework property from assessment plan")
+	}
+	opts.complyTimeOpts.FrameworkID = frameworkProp.Value
+
+	assessmentScope, err := recordedScope(opts.complyTimeOpts)
+	if err != nil {
+		return err
+	}
+	if assessmentScope == nil {
+		logger.Info(fmt.Sprintf("No assessment scope is recorded in %s, the default scope of framework %s is used.",
+			opts.complyTimeOpts.UserWorkspace, frameworkProp.Value))
+	}
+
+	newPlan, err := newScopedPlan(cmd.Context(), opts, appDir, componentDefs, assessmentScope, signatureResults)
+	if err != nil {
+		return err
+	}
+	writePlanDiff(opts.Out, frameworkProp.Value, complytime.DiffAssessmentPlans(oldPlan, newPlan))
+	if opts.dryRun {
+		logger.Info("The assessment plan was not written in dry-run mode.")
+		return nil
+	}
+	return writePlan(opts, newPlan)
+}
+
+// writePlanDiff prints the changes to the assessment plan of the framework as a plain table.
+func writePlanDiff(writer io.Writer, frameworkID string, diff complytime.PlanDiff) {
+	if diff.IsEmpty() {
+		_, _ = fmt.Fprintf(writer, "No changes to the assessment plan of framework %s.\n", frameworkID)
+		return
+	}
+	_, _ = fmt.Fprintf(writer, "Changes to the assessment plan of framework %s:\n\n", frameworkID)
+	var rows []table.Row
+	addRows := func(change, kind string, ids []string) {
+		for _, id := range ids {
+			rows = append(rows, table.Row{change, kind, id, "-", "-"})
+		}
+	}
+	addRows("added", "control", diff.AddedControls)
+	addRows("removed", "control", diff.RemovedControls)
+	addRows("added", "rule", diff.AddedRules)
+	addRows("removed", "rule", diff.RemovedRules)
+	addRows("added", "activity", diff.AddedActivities)
+	addRows("removed", "activity", diff.RemovedActivities)
+	for _, change := range diff.ChangedParameters {
+		rows = append(rows, table.Row{"changed", "parameter", fmt.Sprintf("%s (%s)", change.Parameter, change.Activity),
+			valueOrDash(change.OldValue), valueOrDash(change.NewValue)})
+	}
+	columns := []table.Column{
+		{Title: "Change", Width: 10},
+		{Title: "Type", Width: 10},
+		{Title: "ID", Width: 30},
+		{Title: "Old", Width: 10},
+		{Title: "New", Width: 10},
+	}
+	columns = calculateDynamicColumnWidths(columns, rows)
+	// Leave room between columns for readability
+	for i := range columns {
+		columns[i].Width += 2
+	}
+	terminal.ShowPlainTable(writer, columns, rows)
+}
+
+// frameworkWorkspaces returns the workspace options of each framework planned in the workspace. The plans of
+// several frameworks are in subdirectories of the workspace named after them, and are only used when there
+// is no plan at the root of the workspace.
+func frameworkWorkspaces(opts *option.ComplyTime) ([]*option.ComplyTime, error) {
+	if _, err := os.Stat(filepath.Join(opts.UserWorkspace, assessmentPlanLocation)); err == nil {
+		return []*option.ComplyTime{opts}, nil
+	}
+	planPaths, err := filepath.Glob(filepath.Join(opts.UserWorkspace, "*", assessmentPlanLocation))
+	if err != nil {
+		return nil, err
+	}
+	if len(planPaths) == 0 {
+		// The missing plan is reported when it is loaded
+		return []*option.ComplyTime{opts}, nil
+	}
+	sort.Strings(planPaths)
+	workspaces := make([]*option.ComplyTime, 0, len(planPaths))
+	for _, planPath := range planPaths {
+		workspace := filepath.Dir(planPath)
+		workspaces = append(workspaces, &option.ComplyTime{UserWorkspace: workspace, FrameworkID: filepath.Base(workspace)})
+	}
+	return workspaces, nil
+}
+
 // loadPlan returns the loaded assessment plan and path from the workspace.
 func loadPlan(opts *option.ComplyTime, validator validation.Validator) (*oscalTypes.AssessmentPlan, string, error) {
 	apPath := filepath.Join(opts.UserWorkspace, assessmentPlanLocation)
@@ -147,6 +629,11 @@ func loadPlan(opts *option.ComplyTime, validator validation.Validator) (*oscalTy
 	assessmentPlan, err := complytime.ReadPlan(apCleanedPath, validator)
 	if err != nil {
 		if errors.Is(err, os.ErrNotExist) {
+			// The plans of several frameworks are in subdirectories of the workspace
+			if workspaces, globErr := frameworkWorkspaces(opts); globErr == nil && workspaces[0] != opts {
+				return nil, "", fmt.Errorf("error: assessment plans of frameworks are in subdirectories of workspace %s: %w\n\n"+
+					"Select one with --workspace %s", opts.UserWorkspace, err, workspaces[0].UserWorkspace)
+			}
 			return nil, "", fmt.Errorf("error: assessment plan does not exist in workspace %s: %w\n\nDid you run the plan command?",
 				opts.UserWorkspace,
 				err)
@@ -176,7 +663,40 @@ func planDryRun(frameworkId string, cds []oscalTypes.ComponentDefinition, output
 	if err != nil {
 		return fmt.Errorf("error marshalling yaml content: %v", err)
 	}
+	return writeScopeData(data, output)
+}
 
+// planDryRunScope writes the scope config merged from its layers, with the controls and rules matched by
+// its selectors and patterns, so their effect is visible before the assessment plan is written. Each value
+// is annotated with the scope config layer that set it.
+func planDryRunScope(opts *planOptions, appDir complytime.ApplicationDirectory, componentDefs []oscalTypes.ComponentDefinition) error {
+	frameworkID := opts.complyTimeOpts.FrameworkID
+	expanded, err := loadScopeConfig(opts, appDir, componentDefs)
+	if err != nil {
+		return err
+	}
+	defaults, err := complytime.NewAssessmentScopeFromCDs(frameworkID, appDir, validation.NewSchemaValidator(), componentDefs...)
+	if err != nil {
+		return fmt.Errorf("error creating assessment scope for %s: %w", frameworkID, err)
+	}
+	titles := make(map[string]string)
+	for _, entry := range defaults.IncludeControls {
+		titles[entry.ControlID] = entry.ControlTitle
+	}
+	for i := range expanded.Scope.IncludeControls {
+		if expanded.Scope.IncludeControls[i].ControlTitle == "" {
+			expanded.Scope.IncludeControls[i].ControlTitle = titles[expanded.Scope.IncludeControls[i].ControlID]
+		}
+	}
+	data, err := expanded.AnnotatedYAML()
+	if err != nil {
+		return err
+	}
+	return writeScopeData(data, opts.output)
+}
+
+// writeScopeData writes the assessment scope YAML to the output file, or to stdout for "-".
+func writeScopeData(data []byte, output string) error {
 	if output == "-" {
 		fmt.Fprintln(os.Stdout, string(data))
 	} else {
@@ -184,3 +704,52 @@ func planDryRun(frameworkId string, cds []oscalTypes.ComponentDefinition, output
 	}
 	return nil
 }
+
+// planInteractive opens the scope editor on the defaults of the framework, or on the given scope config.
+// The saved scope is written to the output file, or applied to write the assessment plan.
+func planInteractive(cmd *cobra.Command, opts *planOptions, appDir complytime.ApplicationDirectory, componentDefs []oscalTypes.ComponentDefinition, signatureResults []complytime.SignatureResult) error {
+	frameworkID := opts.complyTimeOpts.FrameworkID
+	defaults, err := complytime.NewAssessmentScopeFromCDs(frameworkID, appDir, validation.NewSchemaValidator(), componentDefs...)
+	if err != nil {
+		return fmt.Errorf("error creating assessment scope for %s: %w", frameworkID, err)
+	}
+	scope := defaults
+	if len(opts.scopeConfigs) > 0 {
+		layered, err := loadScopeConfig(opts, appDir, componentDefs)
+		if err != nil {
+			return err
+		}
+		scope = layered.Scope
+	}
+
+	alternatives := make(map[string][]string)
+	for _, entry := range append(defaults.IncludeControls, scope.IncludeControls...) {
+		for _, parameter := range entry.SelectParameters {
+			if _, found := alternatives[parameter.Name]; !found {
+				alternatives[parameter.Name] = complytime.ParameterAlternatives(parameter.Name, componentDefs)
+			}
+		}
+	}
+	editor := terminal.NewScopeEditor(scope, defaults, complytime.ControlRules(frameworkID, componentDefs), alternatives)
+	if _, err := tea.NewProgram(editor, tea.WithInput(cmd.InOrStdin()), tea.WithOutput(opts.Out), tea.WithAltScreen()).Run(); err != nil {
+		return fmt.Errorf("failed to run the scope editor: %w", err)
+	}
+	if !editor.Saved() {
+		logger.Info("The assessment scope was not saved.")
+		return nil
+	}
+
+	edited := editor.Scope()
+	if opts.output == "-" {
+		return writeScopedPlan(cmd.Context(), opts, appDir, componentDefs, &edited, signatureResults)
+	}
+	data, err := yaml.Marshal(&edited)
+	if err != nil {
+		return fmt.Errorf("error marshalling yaml content: %v", err)
+	}
+	if err := os.WriteFile(opts.output, data, 0600); err != nil {
+		return err
+	}
+	logger.Info(fmt.Sprintf("Assessment scope written to %s", opts.output))
+	return nil
+}
@@ -3,13 +3,21 @@
 package cli
 
 import (
+	"bytes"
+	"context"
 	"fmt"
+	"io"
+	"os"
+	"path/filepath"
 	"testing"
 
+	"github.com/oscal-compass/oscal-sdk-go/extensions"
 	"github.com/oscal-compass/oscal-sdk-go/validation"
 	"github.com/stretchr/testify/require"
 
 	"github.com/complytime/complyctl/cmd/complyctl/option"
+	"github.com/complytime/complyctl/internal/complytime"
+	"github.com/complytime/complyctl/internal/complytime/complytimetest"
 )
 
 func TestPlansInWorkspace(t *testing.T) {
@@ -50,6 +58,13 @@ func TestValidatePlan(t *testing.T) {
 				output: "myconfig.yml",
 			},
 		},
+		{
+			name: "Valid/InteractiveOut",
+			opts: planOptions{
+				interactive: true,
+				output:      "myconfig.yml",
+			},
+		},
 		{
 			name: "Invalid/OutNoDryRun",
 			opts: planOptions{
@@ -57,7 +72,125 @@ func TestValidatePlan(t *testing.T) {
 				output: "myconfig.yml",
 			},
 			wantErr: "" +
-				"invalid command flags: \"--dry-run\" must be used with \"--out\"",
+				"invalid command flags: \"--dry-run\" or \"--interactive\" must be used with \"--out\"",
+		},
+		{
+			name: "Valid/Strict",
+			opts: planOptions{
+				strict:       true,
+				scopeConfigs: []string{"config.yml"},
+				output:       "-",
+			},
+		},
+		{
+			name: "Invalid/StrictNoScopeConfig",
+			opts: planOptions{
+				strict: true,
+				output: "-",
+			},
+			wantErr: "invalid command flags: \"--strict\" must be used with \"--scope-config\"",
+		},
+		{
+			name: "Invalid/DryRunInteractive",
+			opts: planOptions{
+				dryRun:      true,
+				interactive: true,
+				output:      "-",
+			},
+			wantErr: "invalid command flags: \"--dry-run\" and \"--interactive\" cannot be used together",
+		},
+		{
+			name: "Valid/Frameworks",
+			opts: planOptions{
+				frameworkIDs: []string{"example", "other"},
+				scopeConfigs: []string{"example.yml", "other.yml"},
+				output:       "-",
+			},
+		},
+		{
+			name: "Invalid/FrameworksDryRun",
+			opts: planOptions{
+				frameworkIDs: []string{"example", "other"},
+				dryRun:       true,
+				output:       "-",
+			},
+			wantErr: "invalid command flags: \"--dry-run\" and \"--interactive\" can only be used with a single framework",
+		},
+		{
+			name: "Valid/RefreshDryRun",
+			opts: planOptions{
+				refresh: true,
+				dryRun:  true,
+				output:  "-",
+			},
+		},
+		{
+			name: "Invalid/RefreshFrameworks",
+			opts: planOptions{
+				refresh:      true,
+				frameworkIDs: []string{"example"},
+				output:       "-",
+			},
+			wantErr: "invalid command flags: \"--refresh\" cannot be used with framework ids",
+		},
+		{
+			name: "Invalid/RefreshScopeConfig",
+			opts: planOptions{
+				refresh:      true,
+				scopeConfigs: []string{"config.yml"},
+				output:       "-",
+			},
+			wantErr: "invalid command flags: \"--refresh\" cannot be used with \"--interactive\" or \"--scope-config\"",
+		},
+		{
+			name: "Valid/FromResultsDryRunOut",
+			opts: planOptions{
+				fromResults: "assessment-results.json",
+				dryRun:      true,
+				output:      "followup.yml",
+			},
+		},
+		{
+			name: "Invalid/FromResultsScopeConfig",
+			opts: planOptions{
+				fromResults:  "assessment-results.json",
+				scopeConfigs: []string{"config.yml"},
+				output:       "-",
+			},
+			wantErr: "invalid command flags: \"--from-results\" cannot be used with \"--refresh\", \"--interactive\", or \"--scope-config\"",
+		},
+		{
+			name: "Invalid/FromResultsFrameworks",
+			opts: planOptions{
+				fromResults:  "assessment-results.json",
+				frameworkIDs: []string{"example", "other"},
+				output:       "-",
+			},
+			wantErr: "invalid command flags: \"--from-results\" can only be used with a single framework",
+		},
+		{
+			name: "Invalid/FrameworkPath",
+			opts: planOptions{
+				output:       "-",
+				frameworkIDs: []string{"example", "../outside"},
+			},
+			wantErr: "invalid framework id \"../outside\": must not be a path",
+		},
+		{
+			name: "Invalid/FrameworkAbsolutePath",
+			opts: planOptions{
+				output:       "-",
+				frameworkIDs: []string{"/tmp/outside"},
+			},
+			wantErr: "invalid framework id \"/tmp/outside\": must not be a path",
+		},
+		{
+			name: "Invalid/FrameworkParentDirectory",
+			opts: planOptions{
+				output:       "-",
+				frameworkIDs: []string{"example", ".."},
+			},
+			wantErr: "invalid framework id \"..\": must not be a path",
 		},
 	}
 
@@ -73,3 +206,163 @@ func TestValidatePlan(t *testing.T) {
 		})
 	}
 }
+
+func TestFrameworkWorkspaces(t *testing.T) {
+	workspace := t.TempDir()
+	opts := &option.ComplyTime{UserWorkspace: workspace}
+
+	workspaces, err := frameworkWorkspaces(opts)
+	require.NoError(t, err)
+	require.Equal(t, []*option.ComplyTime{opts}, workspaces)
+
+	for _, frameworkID := range []string{"other", "example"} {
+		require.NoError(t, os.MkdirAll(filepath.Join(workspace, frameworkID), 0700))
+		require.NoError(t, os.WriteFile(filepath.Join(workspace, frameworkID, assessmentPlanLocation), []byte("{}"), 0600))
+	}
+	workspaces, err = frameworkWorkspaces(opts)
+	require.NoError(t, err)
+	require.Equal(t, []*option.ComplyTime{
+		{UserWorkspace: filepath.Join(workspace, "example"), FrameworkID: "example"},
+		{UserWorkspace: filepath.Join(workspace, "other"), FrameworkID: "other"},
+	}, workspaces)
+
+	_, _, err = loadPlan(opts, validation.NoopValidator{})
+	require.ErrorContains(t, err, "Select one with --workspace "+filepath.Join(workspace, "example"))
+
+	// A plan at the root of the workspace takes precedence.
+	require.NoError(t, os.WriteFile(filepath.Join(workspace, assessmentPlanLocation), []byte("{}"), 0600))
+	workspaces, err = frameworkWorkspaces(opts)
+	require.NoError(t, err)
+	require.Equal(t, []*option.ComplyTime{opts}, workspaces)
+}
+
+func TestGroupScopeConfigs(t *testing.T) {
+	dir := t.TempDir()
+	configs := map[string]string{
+		"example.yml": "frameworkId: example\n",
+		"prod.yml":    "extends: example.yml\n",
+		"other.yml":   "frameworkId: other\n",
+		"none.yml":    "includeControls: []\n",
+	}
+	for name, config := range configs {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(config), 0600))
+	}
+
+	grouped, err := groupScopeConfigs([]string{filepath.Join(dir, "example.yml"), filepath.Join(dir, "other.yml"), filepath.Join(dir, "prod.yml")})
+	require.NoError(t, err)
+	require.Equal(t, map[string][]string{
+		"example": {filepath.Join(dir, "example.yml"), filepath.Join(dir, "prod.yml")},
+		"other":   {filepath.Join(dir, "other.yml")},
+	}, grouped)
+
+	_, err = groupScopeConfigs([]string{filepath.Join(dir, "none.yml")})
+	require.EqualError(t, err, "scope config "+filepath.Join(dir, "none.yml")+" does not set a frameworkId")
+}
+
+func TestRecordScope(t *testing.T) {
+	opts := &option.ComplyTime{UserWorkspace: t.TempDir(), FrameworkID: "example"}
+
+	scope, err := recordedScope(opts)
+	require.NoError(t, err)
+	require.Nil(t, scope)
+
+	recorded := complytime.AssessmentScope{
+		Extends:            "baseline.yml",
+		IncludeControls:    []complytime.ControlEntry{{ControlID: "AC-*", IncludeRules: []string{"sshd_*"}}},
+		GlobalExcludeRules: []string{"package_aide_installed"},
+	}
+	require.NoError(t, recordScope(opts, &recorded))
+	scope, err = recordedScope(opts)
+	require.NoError(t, err)
+	require.Equal(t, &complytime.AssessmentScope{
+		FrameworkID:        "example",
+		IncludeControls:    []complytime.ControlEntry{{ControlID: "AC-*", IncludeRules: []string{"sshd_*"}}},
+		GlobalExcludeRules: []string{"package_aide_installed"},
+	}, scope)
+
+	// A plan with the default scope removes the recorded scope.
+	require.NoError(t, recordScope(opts, nil))
+	require.NoFileExists(t, filepath.Join(opts.UserWorkspace, assessmentScopeLocation))
+}
+
+func TestWritePlanDiff(t *testing.T) {
+	var out bytes.Buffer
+	writePlanDiff(&out, "example", complytime.PlanDiff{})
+	require.Equal(t, "No changes to the assessment plan of framework example.\n", out.String())
+
+	out.Reset()
+	writePlanDiff(&out, "example", complytime.PlanDiff{
+		AddedControls: []string{"ac-3"},
+		RemovedRules:  []string{"sshd_set_idle_timeout"},
+		ChangedParameters: []complytime.ParameterChange{
+			{Activity: "sshd_set_idle_timeout", Parameter: "var_sshd_timeout", OldValue: "300", NewValue: "600"},
+		},
+	})
+	require.Contains(t, out.String(), "Changes to the assessment plan of framework example:")
+	require.Regexp(t, `added +control +ac-3`, out.String())
+	require.Regexp(t, `removed +rule +sshd_set_idle_timeout`, out.String())
+	require.Regexp(t, `changed +parameter +var_sshd_timeout \(sshd_set_idle_timeout\) +300 +600`, out.String())
+}
+
+// testAppDir returns a development application directory with the test component definition of
+// framework "example" installed.
+func testAppDir(t *testing.T) complytime.ApplicationDirectory {
+	appDir := complytimetest.NewApplicationDirectory(t)
+	testDataDir := filepath.Join("..", "..", "..", "internal", "complytime", "testdata", "complytime")
+	copyFile := func(from, to string) {
+		data, err := os.ReadFile(from)
+		require.NoError(t, err)
+		require.NoError(t, os.WriteFile(to, data, 0600))
+	}
+	copyFile(filepath.Join(testDataDir, "bundles", "example-component-definition.json"), filepath.Join(appDir.BundleDir(), "example-component-definition.json"))
+	for _, name := range []string{"sample-profile.json", "sample-catalog.json"} {
+		copyFile(filepath.Join(testDataDir, "controls", name), filepath.Join(appDir.ControlDir(), name))
+	}
+	return appDir
+}
+
+// runPlanCmd runs the plan command with the arguments in the workspace.
+func runPlanCmd(t *testing.T, workspace string, args ...string) {
+	cmd := planCmd(&option.Common{Output: option.Output{Out: io.Discard}})
+	cmd.SetArgs(append(args, "--workspace", workspace, "--insecure-skip-verify"))
+	cmd.SetContext(context.Background())
+	require.NoError(t, cmd.Execute())
+}
+
+func TestPlanFollowUpKeepsRecordedScope(t *testing.T) {
+	testAppDir(t)
+	workspace := t.TempDir()
+	scopeConfig := filepath.Join(t.TempDir(), "config.yml")
+	require.NoError(t, os.WriteFile(scopeConfig, []byte(`frameworkId: example
+includeControls:
+- controlId: example-1
+  includeRules:
+  - "*"
+globalWaiveRules:
+- rule-1
+`), 0600))
+	scopePath := filepath.Join(workspace, assessmentScopeLocation)
+
+	runPlanCmd(t, workspace, "example", "--scope-config", scopeConfig)
+	recorded, err := os.ReadFile(scopePath)
+	require.NoError(t, err)
+
+	runPlanCmd(t, workspace, "example", "--from-results", filepath.Join("testdata", "assessment-results.json"))
+	afterFollowUp, err := os.ReadFile(scopePath)
+	require.NoError(t, err)
+	require.Equal(t, string(recorded), string(afterFollowUp))
+
+	// The refresh restores the recorded scope, where rule-1 is waived.
+	runPlanCmd(t, workspace, "--refresh")
+	plan, err := complytime.ReadPlan(filepath.Join(workspace, assessmentPlanLocation), validation.NoopValidator{})
+	require.NoError(t, err)
+	require.NotNil(t, plan.LocalDefinitions)
+	require.NotNil(t, plan.LocalDefinitions.Activities)
+	activities := *plan.LocalDefinitions.Activities
+	require.Len(t, activities, 1)
+	require.Equal(t, "rule-1", activities[0].Title)
+	require.NotNil(t, activities[0].Props)
+	waived, found := extensions.GetTrestleProp(extensions.WaivedRulesProperty, *activities[0].Props)
+	require.True(t, found)
+	require.Equal(t, "true", waived.Value)
+}
@@ -0,0 +1,291 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/table"
+	"github.com/spf13/cobra"
+
+	"github.com/complytime/complyctl/cmd/complyctl/option"
+	"github.com/complytime/complyctl/internal/complytime"
+	"github.com/complytime/complyctl/internal/terminal"
+)
+
+// Checksum statuses of a discovered plugin
+const (
+	checksumVerified = "verified"
+	checksumMismatch = "mismatch"
+	checksumError    = "error"
+	invalidManifest  = "invalid manifest"
+)
+
+// errPluginVerification is returned when a plugin executable does not match its manifest.
+var errPluginVerification = errors.New("plugin verification failed")
+
+// pluginOptions defines options for the "plugin" subcommands
+type pluginOptions struct {
+	*option.Common
+	complyTimeOpts *option.ComplyTime
+	pluginID       string
+}
+
+var pluginExample = `
+# List the discovered plugins
+complyctl plugin list
+
+# Show the configuration options of the openscap plugin
+complyctl plugin inspect openscap
+
+# Verify the checksum of every plugin executable
+complyctl plugin verify
+`
+
+// pluginCmd creates a new cobra.Command for the "plugin" subcommand
+func pluginCmd(common *option.Common) *cobra.Command {
+	pluginOpts := &pluginOptions{
+		Common:         common,
+		complyTimeOpts: &option.ComplyTime{},
+	}
+	cmd := &cobra.Command{
+		Use:     "plugin",
+		Short:   "List, inspect, and verify plugins.",
+		Example: pluginExample,
+		Args:    cobra.NoArgs,
+	}
+	cmd.AddCommand(
+		&cobra.Command{
+			Use:          "list",
+			Short:        "List the discovered plugins.",
+			SilenceUsage: true,
+			Args:         cobra.NoArgs,
+			RunE:         func(_ *cobra.Command, _ []string) error { return runPluginList(pluginOpts) },
+		},
+		pluginInspectCmd(pluginOpts),
+		&cobra.Command{
+			Use:          "verify",
+			Short:        "Verify the sha256 checksum of every plugin executable against its manifest.",
+			SilenceUsage: true,
+			Args:         cobra.NoArgs,
+			RunE:         func(_ *cobra.Command, _ []string) error { return runPluginVerify(pluginOpts) },
+		},
+	)
+	return cmd
+}
+
+func pluginInspectCmd(opts *pluginOptions) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:          "inspect [flags] id",
+		Short:        "Show the configuration options of a plugin.",
+		SilenceUsage: true,
+		Args:         cobra.ExactArgs(1),
+		PreRun: func(_ *cobra.Command, args []string) {
+			opts.pluginID = args[0]
+		},
+		RunE: func(_ *cobra.Command, _ []string) error { return runPluginInspect(opts) },
+	}
+	opts.complyTimeOpts.BindFlags(cmd.Flags())
+	return cmd
+}
+
+func discoverPlugins() ([]complytime.DiscoveredPlugin, error) {
+	appDir, err := complytime.NewApplicationDirectory(true, logger)
+	if err != nil {
+		return nil, err
+	}
+	logger.Debug(fmt.Sprintf("Using plugin manifest directory: %s", appDir.PluginManifestDir()))
+	return complytime.DiscoverPlugins(appDir)
+}
+
+func runPluginList(opts *pluginOptions) error {
+	plugins, err := discoverPlugins()
+	if err != nil {
+		return err
+	}
+	if len(plugins) == 0 {
+		_, _ = fmt.Fprintln(opts.Out, "No plugins found.")
+		return nil
+	}
+	for _, discovered := range plugins {
+		if discovered.Err != nil {
+			logger.Warn(fmt.Sprintf("Invalid manifest for plugin %s: %v", discovered.ID, discovered.Err))
+		}
+	}
+	columns, rows := getPluginColumnsAndRows(plugins)
+	writePlainTable(opts.Out, columns, rows)
+	return nil
+}
+
+func runPluginInspect(opts *pluginOptions) error {
+	plugins, err := discoverPlugins()
+	if err != nil {
+		return err
+	}
+	var discovered *complytime.DiscoveredPlugin
+	for i := range plugins {
+		if plugins[i].ID == opts.pluginID {
+			discovered = &plugins[i]
+			break
+		}
+	}
+	if discovered == nil {
+		return fmt.Errorf("plugin %q not found", opts.pluginID)
+	}
+	if discovered.Err != nil {
+		return fmt.Errorf("invalid manifest for plugin %s: %w", discovered.ID, discovered.Err)
+	}
+
+	pluginOptions := opts.complyTimeOpts.ToPluginOptions()
+	// The profile is set from the framework of the assessment plan at runtime.
+	pluginOptions.Profile = "<framework-id>"
+	selections, err := complytime.PluginSelections(discovered.ID, pluginOptions, logger)
+	if err != nil {
+		return err
+	}
+
+	manifest := discovered.Manifest
+	_, _ = fmt.Fprintf(opts.Out, "ID:          %s\n", manifest.ID)
+	_, _ = fmt.Fprintf(opts.Out, "Description: %s\n", manifest.Description)
+	_, _ = fmt.Fprintf(opts.Out, "Version:     %s\n", manifest.Version)
+	_, _ = fmt.Fprintf(opts.Out, "Types:       %s\n", strings.Join(manifest.Types, ", "))
+	_, _ = fmt.Fprintf(opts.Out, "Manifest:    %s\n", discovered.ManifestPath)
+	_, _ = fmt.Fprintf(opts.Out, "Executable:  %s\n", manifest.ExecutablePath)
+	_, _ = fmt.Fprintf(opts.Out, "Checksum:    %s\n\n", checksumStatus(*discovered))
+
+	if len(manifest.Configuration) == 0 {
+		_, _ = fmt.Fprintln(opts.Out, "No configuration options.")
+		return nil
+	}
+	columns, rows := getPluginOptionColumnsAndRows(*discovered, selections)
+	writePlainTable(opts.Out, columns, rows)
+	return nil
+}
+
+func runPluginVerify(opts *pluginOptions) error {
+	plugins, err := discoverPlugins()
+	if err != nil {
+		return err
+	}
+	var rows []table.Row
+	var failed int
+	for _, discovered := range plugins {
+		status, err := verifyPlugin(discovered)
+		detail := "sha256 matches the manifest"
+		if err != nil {
+			detail = err.Error()
+			failed++
+		}
+		rows = append(rows, table.Row{discovered.ID, status, detail})
+	}
+	columns := []table.Column{
+		{Title: "Plugin ID", Width: 10},
+		{Title: "Status", Width: 10},
+		{Title: "Detail", Width: 10},
+	}
+	writePlainTable(opts.Out, columns, rows)
+	if failed > 0 {
+		return fmt.Errorf("%w: %d of %d plugins", errPluginVerification, failed, len(plugins))
+	}
+	return nil
+}
+
+// checksumStatus returns whether the plugin executable matches the checksum of its manifest.
+func checksumStatus(discovered complytime.DiscoveredPlugin) string {
+	status, _ := verifyPlugin(discovered)
+	return status
+}
+
+// verifyPlugin verifies the plugin executable against the checksum of its manifest and
+// returns the checksum status with the verification error, if any.
+func verifyPlugin(discovered complytime.DiscoveredPlugin) (string, error) {
+	if discovered.Err != nil {
+		return invalidManifest, discovered.Err
+	}
+	err := complytime.VerifyChecksum(discovered.Manifest)
+	switch {
+	case err == nil:
+		return checksumVerified, nil
+	case errors.Is(err, complytime.ErrChecksumMismatch):
+		return checksumMismatch, err
+	default:
+		return checksumError, err
+	}
+}
+
+// getPluginColumnsAndRows returns the columns and rows to print the discovered plugins as a table.
+func getPluginColumnsAndRows(plugins []complytime.DiscoveredPlugin) ([]table.Column, []table.Row) {
+	var rows []table.Row
+	for _, discovered := range plugins {
+		if discovered.Err != nil {
+			rows = append(rows, table.Row{discovered.ID, "-", "-", discovered.ManifestPath, invalidManifest})
+			continue
+		}
+		manifest := discovered.Manifest
+		rows = append(rows, table.Row{
+			discovered.ID,
+			valueOrDash(manifest.Version),
+			valueOrDash(strings.Join(manifest.Types, ", ")),
+			manifest.ExecutablePath,
+			checksumStatus(discovered),
+		})
+	}
+	columns := []table.Column{
+		{Title: "Plugin ID", Width: 10},
+		{Title: "Version", Width: 8},
+		{Title: "Types", Width: 6},
+		{Title: "Executable", Width: 11},
+		{Title: "Checksum", Width: 9},
+	}
+	return columns, rows
+}
+
+// getPluginOptionColumnsAndRows returns the columns and rows to print the plugin configuration options
+// and their effective values as a table.
+func getPluginOptionColumnsAndRows(discovered complytime.DiscoveredPlugin, selections map[string]string) ([]table.Column, []table.Row) {
+	var rows []table.Row
+	for _, configOption := range discovered.Manifest.Configuration {
+		defaultValue := "-"
+		if configOption.Default != nil {
+			defaultValue = *configOption.Default
+		}
+		effectiveValue, found := selections[configOption.Name]
+		switch {
+		case !found && configOption.Default != nil:
+			effectiveValue = *configOption.Default
+		case !found && configOption.Required:
+			effectiveValue = "<unset>"
+		case !found:
+			effectiveValue = "-"
+		}
+		rows = append(rows, table.Row{
+			configOption.Name,
+			configOption.Description,
+			defaultValue,
+			strconv.FormatBool(configOption.Required),
+			effectiveValue,
+		})
+	}
+	columns := []table.Column{
+		{Title: "Option", Width: 7},
+		{Title: "Description", Width: 12},
+		{Title: "Default", Width: 8},
+		{Title: "Required", Width: 9},
+		{Title: "Effective Value", Width: 16},
+	}
+	return columns, rows
+}
+
+// writePlainTable prints a plain table sized to its content.
+func writePlainTable(writer io.Writer, columns []table.Column, rows []table.Row) {
+	columns = calculateDynamicColumnWidths(columns, rows)
+	// Leave room between columns for readability
+	for i := range columns {
+		columns[i].Width += 2
+	}
+	terminal.ShowPlainTable(writer, columns, rows)
+}
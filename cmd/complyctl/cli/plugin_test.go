@@ -0,0 +1,109 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package cli
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/charmbracelet/bubbles/table"
+	"github.com/oscal-compass/compliance-to-policy-go/v2/plugin"
+	"github.com/stretchr/testify/require"
+
+	"github.com/complytime/complyctl/internal/complytime"
+)
+
+func newTestDiscoveredPlugin(t *testing.T, checksum string) complytime.DiscoveredPlugin {
+	executablePath := filepath.Join(t.TempDir(), "myplugin")
+	content := []byte("#!/bin/sh\n")
+	require.NoError(t, os.WriteFile(executablePath, content, 0600))
+	if checksum == "" {
+		sum := sha256.Sum256(content)
+		checksum = hex.EncodeToString(sum[:])
+	}
+	defaultResults := "results.xml"
+	return complytime.DiscoveredPlugin{
+		ID:           "myplugin",
+		ManifestPath: "plugins/c2p-myplugin-manifest.json",
+		Manifest: plugin.Manifest{
+			Metadata: plugin.Metadata{
+				ID:      "myplugin",
+				Version: "0.1.0",
+				Types:   []string{"pvp"},
+			},
+			ExecutablePath: executablePath,
+			Checksum:       checksum,
+			Configuration: []plugin.ConfigurationOption{
+				{Name: "workspace", Description: "Plugin workspace", Required: true},
+				{Name: "results", Description: "Results file", Default: &defaultResults},
+				{Name: "datastream", Description: "Datastream path", Required: true},
+				{Name: "arf", Description: "ARF file"},
+			},
+		},
+	}
+}
+
+func TestVerifyPlugin(t *testing.T) {
+	tests := []struct {
+		name       string
+		discovered func(t *testing.T) complytime.DiscoveredPlugin
+		wantStatus string
+		wantErr    bool
+	}{
+		{
+			name:       "Valid/Verified",
+			discovered: func(t *testing.T) complytime.DiscoveredPlugin { return newTestDiscoveredPlugin(t, "") },
+			wantStatus: checksumVerified,
+		},
+		{
+			name:       "Invalid/Mismatch",
+			discovered: func(t *testing.T) complytime.DiscoveredPlugin { return newTestDiscoveredPlugin(t, "0000") },
+			wantStatus: checksumMismatch,
+			wantErr:    true,
+		},
+		{
+			name: "Invalid/Manifest",
+			discovered: func(t *testing.T) complytime.DiscoveredPlugin {
+				return complytime.DiscoveredPlugin{ID: "broken", Err: errors.New("failed to parse plugin manifest")}
+			},
+			wantStatus: invalidManifest,
+			wantErr:    true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status, err := verifyPlugin(tt.discovered(t))
+			require.Equal(t, tt.wantStatus, status)
+			require.Equal(t, tt.wantErr, err != nil)
+		})
+	}
+}
+
+func TestGetPluginColumnsAndRows(t *testing.T) {
+	discovered := newTestDiscoveredPlugin(t, "")
+	broken := complytime.DiscoveredPlugin{ID: "broken", ManifestPath: "plugins/c2p-broken-manifest.json", Err: errors.New("invalid")}
+	_, rows := getPluginColumnsAndRows([]complytime.DiscoveredPlugin{broken, discovered})
+	require.Equal(t, []table.Row{
+		{"broken", "-", "-", "plugins/c2p-broken-manifest.json", invalidManifest},
+		{"myplugin", "0.1.0", "pvp", discovered.Manifest.ExecutablePath, checksumVerified},
+	}, rows)
+}
+
+func TestGetPluginOptionColumnsAndRows(t *testing.T) {
+	discovered := newTestDiscoveredPlugin(t, "")
+	selections := map[string]string{
+		"workspace": "complytime",
+		"results":   "custom.xml",
+	}
+	_, rows := getPluginOptionColumnsAndRows(discovered, selections)
+	require.Equal(t, []table.Row{
+		{"workspace", "Plugin workspace", "-", "true", "complytime"},
+		{"results", "Results file", "results.xml", "false", "custom.xml"},
+		{"datastream", "Datastream path", "-", "true", "<unset>"},
+		{"arf", "ARF file", "-", "false", "-"},
+	}, rows)
+}
@@ -0,0 +1,368 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package cli
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/table"
+	oscalTypes "github.com/defenseunicorns/go-oscal/src/types/oscal-1-1-3"
+	"github.com/oscal-compass/oscal-sdk-go/validation"
+	"github.com/spf13/cobra"
+
+	"github.com/complytime/complyctl/cmd/complyctl/option"
+	"github.com/complytime/complyctl/internal/complytime"
+)
+
+// remediationLogDir is the workspace directory where applied remediations and their logs are written.
+const remediationLogDir = "remediations"
+
+// errRemediationAborted is returned when the user does not confirm the remediation.
+var errRemediationAborted = errors.New("remediation aborted")
+
+// remediateOptions defines options for the "remediate" subcommand
+type remediateOptions struct {
+	*option.Common
+	complyTimeOpts *option.ComplyTime
+	rules          []string
+	failed         bool
+	// remediationType is the type of the remediation to apply
+	remediationType string
+	dryRun          bool
+	yes             bool
+	in              io.Reader
+}
+
+var remediateExample = `
+# List the remediations available for each rule of the assessment plan
+complyctl remediate
+
+# Print the bash script that would remediate the rules that failed in the latest assessment results
+complyctl remediate --failed --dry-run
+
+# Show the changes the ansible remediation of a rule would make
+complyctl remediate --rule sshd_disable_root_login --type ansible --dry-run
+
+# Apply the ansible remediation of the failed rules without confirmation
+complyctl remediate --failed --type ansible --yes
+`
+
+// remediateCmd creates a new cobra.Command for the "remediate" subcommand
+func remediateCmd(common *option.Common) *cobra.Command {
+	remediateOpts := &remediateOptions{
+		Common:         common,
+		complyTimeOpts: &option.ComplyTime{},
+	}
+	cmd := &cobra.Command{
+		Use:   "remediate [flags]",
+		Short: "List and apply the remediations generated by plugins.",
+		Long: "List and apply the remediations generated by plugins in the workspace. Without selected rules, " +
+			"the available remediations of each rule in the assessment plan are listed. Applied remediations " +
+			"are logged in the workspace and recorded as risk responses in the assessment results.",
+		Example:      remediateExample,
+		SilenceUsage: true,
+		Args:         cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			remediateOpts.in = cmd.InOrStdin()
+			if err := validateRemediate(remediateOpts); err != nil {
+				return err
+			}
+			return runRemediate(cmd.Context(), remediateOpts)
+		},
+	}
+	cmd.Flags().StringSliceVar(&remediateOpts.rules, "rule", nil, "rules to remediate")
+	cmd.Flags().BoolVar(&remediateOpts.failed, "failed", false, "remediate the rules that failed in the latest assessment results")
+	cmd.Flags().StringVarP(&remediateOpts.remediationType, "type", "t", complytime.RemediationBash, "remediation type to apply, one of: bash, ansible")
+	cmd.Flags().BoolVar(&remediateOpts.dryRun, "dry-run", false, "show the changes of ansible remediations in check mode, or only print the script of bash remediations, without applying them")
+	cmd.Flags().BoolVarP(&remediateOpts.yes, "yes", "y", false, "apply the remediation without confirmation")
+	remediateOpts.complyTimeOpts.BindFlags(cmd.Flags())
+	return cmd
+}
+
+func validateRemediate(opts *remediateOptions) error {
+	switch opts.remediationType {
+	case complytime.RemediationBash, complytime.RemediationAnsible:
+		return nil
+	case complytime.RemediationBlueprint:
+		return fmt.Errorf("blueprint remediations are applied when building an image and cannot be run by complyctl")
+	default:
+		return fmt.Errorf("invalid remediation type %q: must be one of bash, ansible", opts.remediationType)
+	}
+}
+
+func runRemediate(ctx context.Context, opts *remediateOptions) error {
+	validator := validation.NewSchemaValidator()
+	plan, _, err := loadPlan(opts.complyTimeOpts, validator)
+	if err != nil {
+		return err
+	}
+	planRules := complytime.PlanRules(plan)
+
+	remediations, err := complytime.FindRemediations(opts.complyTimeOpts.UserWorkspace)
+	if err != nil {
+		return err
+	}
+	if len(remediations) == 0 {
+		return fmt.Errorf("%w in workspace %s\n\nDid you run the generate command?", complytime.ErrNoRemediation, opts.complyTimeOpts.UserWorkspace)
+	}
+
+	// The assessment results are optional unless the failed rules are selected.
+	assessmentResults, err := loadResults(opts.complyTimeOpts, validator)
+	if err != nil {
+		if opts.failed || !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+		logger.Debug(err.Error())
+	}
+	index := complytime.NewResultsIndex(assessmentResults)
+
+	if len(opts.rules) == 0 && !opts.failed {
+		writeRemediations(opts.Out, planRules, remediations, index)
+		return nil
+	}
+
+	selected, err := selectRemediationRules(opts, planRules, index)
+	if err != nil {
+		return err
+	}
+	if len(selected) == 0 {
+		logger.Info("No rules to remediate.")
+		return nil
+	}
+	return applyRemediations(ctx, opts, selected, remediations, assessmentResults)
+}
+
+// selectRemediationRules returns the check IDs of the selected rules by rule ID.
+func selectRemediationRules(opts *remediateOptions, planRules map[string][]string, index complytime.ResultsIndex) (map[string][]string, error) {
+	selected := make(map[string][]string)
+	for _, ruleID := range opts.rules {
+		checkIDs, found := planRules[ruleID]
+		if !found {
+			return nil, fmt.Errorf("rule %s is not in the assessment plan", ruleID)
+		}
+		selected[ruleID] = checkIDs
+	}
+	if opts.failed {
+		for _, ruleID := range index.RuleIDs() {
+			ruleResult := index.Rules[ruleID]
+			if !complytime.IsFailingResult(ruleResult.Status()) {
+				continue
+			}
+			checkIDs, found := planRules[ruleID]
+			if !found {
+				checkIDs = ruleResult.CheckIDs
+			}
+			selected[ruleID] = checkIDs
+		}
+	}
+	return selected, nil
+}
+
+// applyRemediations renders the remediations of the selected type for the selected rules and
+// either shows them or applies them after confirmation.
+func applyRemediations(ctx context.Context, opts *remediateOptions, selected map[string][]string, remediations []complytime.RemediationFile, assessmentResults *oscalTypes.AssessmentResults) error {
+	type rendered struct {
+		remediation complytime.RemediationFile
+		content     []byte
+		ruleIDs     []string
+	}
+	var renders []rendered
+	covered := make(map[string]struct{})
+	for _, remediation := range remediations {
+		if remediation.Type != opts.remediationType {
+			continue
+		}
+		content, err := remediation.Render(selected)
+		if errors.Is(err, complytime.ErrNoRemediation) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		ruleIDs := remediation.Rules(selected)
+		for _, ruleID := range ruleIDs {
+			covered[ruleID] = struct{}{}
+		}
+		renders = append(renders, rendered{remediation: remediation, content: content, ruleIDs: ruleIDs})
+	}
+	for _, ruleID := range complytime.SortedKeys(selected) {
+		if _, found := covered[ruleID]; !found {
+			logger.Warn(fmt.Sprintf("No %s remediation available for rule %s.", opts.remediationType, ruleID))
+		}
+	}
+	if len(renders) == 0 {
+		return fmt.Errorf("%w: no %s remediation for the selected rules", complytime.ErrNoRemediation, opts.remediationType)
+	}
+
+	if !opts.yes && !opts.dryRun {
+		confirmed, err := confirmRemediation(opts, len(covered))
+		if err != nil {
+			return err
+		}
+		if !confirmed {
+			return errRemediationAborted
+		}
+	}
+
+	logDir := filepath.Join(opts.complyTimeOpts.UserWorkspace, remediationLogDir)
+	if err := os.MkdirAll(logDir, 0700); err != nil {
+		return err
+	}
+	var runErrs []error
+	recorded := false
+	for _, render := range renders {
+		timestamp := time.Now().UTC().Format("20060102T150405Z")
+		baseName := fmt.Sprintf("%s-%s-%s", render.remediation.Plugin, render.remediation.Type, timestamp)
+		if opts.dryRun {
+			baseName += "-dry-run"
+		}
+		// Bash remediations have no check mode, so the script that would run is shown instead.
+		if opts.dryRun && render.remediation.Type == complytime.RemediationBash {
+			logger.Warn("Bash remediations have no check mode, the dry run only prints the script that would run and does not preview its changes.")
+			_, _ = fmt.Fprintf(opts.Out, "# %s remediation of %s for rule(s) %s\n", render.remediation.Type,
+				render.remediation.Plugin, strings.Join(render.ruleIDs, ", "))
+			_, _ = fmt.Fprintln(opts.Out, "# Dry run: this script was not run, its changes are not previewed.")
+			_, _ = opts.Out.Write(render.content)
+			continue
+		}
+
+		scriptPath := filepath.Join(logDir, baseName+filepath.Ext(render.remediation.Path))
+		if err := os.WriteFile(scriptPath, render.content, 0600); err != nil {
+			return err
+		}
+
+		logPath := filepath.Join(logDir, baseName+".log")
+		applied := complytime.AppliedRemediation{
+			Type:    render.remediation.Type,
+			Plugin:  render.remediation.Plugin,
+			RuleIDs: render.ruleIDs,
+			Script:  scriptPath,
+			Log:     logPath,
+			Start:   time.Now(),
+		}
+		logger.Info(fmt.Sprintf("Running the %s remediation of %s for %d rule(s).", applied.Type, applied.Plugin, len(applied.RuleIDs)), "log", logPath)
+		applied.Err = runRemediationCommand(ctx, opts, applied.Type, scriptPath, logPath)
+		applied.End = time.Now()
+		if opts.dryRun {
+			if applied.Err != nil {
+				runErrs = append(runErrs, applied.Err)
+			}
+			continue
+		}
+
+		if applied.Err != nil {
+			logger.Error(fmt.Sprintf("The %s remediation of %s failed: %v", applied.Type, applied.Plugin, applied.Err))
+			runErrs = append(runErrs, applied.Err)
+		} else {
+			logger.Info(fmt.Sprintf("The %s remediation of %s was applied.", applied.Type, applied.Plugin))
+		}
+		if assessmentResults == nil {
+			logger.Warn("No assessment results in the workspace, the remediation is not recorded.")
+			continue
+		}
+		unrecorded := complytime.AddRemediation(assessmentResults, applied)
+		if len(unrecorded) > 0 {
+			logger.Warn(fmt.Sprintf("No observations in the assessment results for rule(s) %s, the remediation is not recorded for them.", strings.Join(unrecorded, ", ")))
+		}
+		recorded = true
+	}
+
+	if recorded {
+		arPath := filepath.Join(opts.complyTimeOpts.UserWorkspace, assessmentResultsLocationJson)
+		if err := complytime.WriteAssessmentResults(assessmentResults, arPath); err != nil {
+			return err
+		}
+		logger.Info(fmt.Sprintf("The applied remediations were recorded in %s.", arPath))
+	}
+	return errors.Join(runErrs...)
+}
+
+// confirmRemediation asks the user to confirm the remediation of the rules.
+func confirmRemediation(opts *remediateOptions, rules int) (bool, error) {
+	_, _ = fmt.Fprintf(opts.Out, "Apply the %s remediation of %d rule(s)? [y/N] ", opts.remediationType, rules)
+	answer, err := bufio.NewReader(opts.in).ReadString('\n')
+	if err != nil && !errors.Is(err, io.EOF) {
+		return false, err
+	}
+	answer = strings.ToLower(strings.TrimSpace(answer))
+	return answer == "y" || answer == "yes", nil
+}
+
+// runRemediationCommand runs the remediation script or playbook. The output is written to the log file
+// and to the command output. Ansible playbooks run in check mode with a diff of the changes on dry runs.
+func runRemediationCommand(ctx context.Context, opts *remediateOptions, remediationType, scriptPath, logPath string) error {
+	var cmd *exec.Cmd
+	switch remediationType {
+	case complytime.RemediationBash:
+		cmd = exec.CommandContext(ctx, "bash", scriptPath) /* #nosec G204 */
+	case complytime.RemediationAnsible:
+		args := []string{"--inventory", "localhost,", "--connection", "local"}
+		if opts.dryRun {
+			args = append(args, "--check", "--diff")
+		}
+		cmd = exec.CommandContext(ctx, "ansible-playbook", append(args, scriptPath)...) /* #nosec G204 */
+	default:
+		return fmt.Errorf("%s remediations cannot be run", remediationType)
+	}
+
+	logFile, err := os.OpenFile(filepath.Clean(logPath), os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0600)
+	if err != nil {
+		return err
+	}
+	defer logFile.Close()
+	output := io.MultiWriter(logFile, opts.Out)
+	cmd.Stdout = output
+	cmd.Stderr = output
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("%s %s: %w", cmd.Path, scriptPath, err)
+	}
+	return nil
+}
+
+// writeRemediations prints the remediation types available for each rule of the assessment plan.
+func writeRemediations(w io.Writer, planRules map[string][]string, remediations []complytime.RemediationFile, index complytime.ResultsIndex) {
+	columns, rows := getRemediationColumnsAndRows(planRules, remediations, index)
+	if len(rows) == 0 {
+		_, _ = fmt.Fprintln(w, "No rules in the assessment plan.")
+	} else {
+		writePlainTable(w, columns, rows)
+	}
+	for _, remediation := range remediations {
+		if remediation.Type == complytime.RemediationBlueprint {
+			_, _ = fmt.Fprintf(w, "\nThe %s blueprint %s applies to the whole profile when building an image.\n", remediation.Plugin, remediation.Path)
+		}
+	}
+}
+
+// getRemediationColumnsAndRows returns the columns and rows to print the remediations of each rule as a table.
+func getRemediationColumnsAndRows(planRules map[string][]string, remediations []complytime.RemediationFile, index complytime.ResultsIndex) ([]table.Column, []table.Row) {
+	types := make(map[string][]string)
+	for _, remediation := range remediations {
+		for _, ruleID := range remediation.Rules(planRules) {
+			types[ruleID] = complytime.AppendUnique(types[ruleID], remediation.Type)
+		}
+	}
+	var rows []table.Row
+	for _, ruleID := range complytime.SortedKeys(planRules) {
+		status := ""
+		if ruleResult, found := index.Rules[ruleID]; found {
+			status = ruleResult.Status()
+		}
+		rows = append(rows, table.Row{ruleID, valueOrDash(status), valueOrDash(strings.Join(types[ruleID], ", "))})
+	}
+	columns := []table.Column{
+		{Title: "Rule", Width: 5},
+		{Title: "Status", Width: 7},
+		{Title: "Remediations", Width: 13},
+	}
+	return columns, rows
+}
@@ -0,0 +1,197 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package cli
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/bubbles/table"
+	oscalTypes "github.com/defenseunicorns/go-oscal/src/types/oscal-1-1-3"
+	"github.com/oscal-compass/oscal-sdk-go/validation"
+	"github.com/stretchr/testify/require"
+
+	"github.com/complytime/complyctl/cmd/complyctl/option"
+	"github.com/complytime/complyctl/internal/complytime"
+)
+
+// testRemediationScript fixes the rule by creating a file named after the rule in the workspace.
+const testRemediationScript = `#!/usr/bin/env bash
+###############################################################################
+# BEGIN fix (1 / 1) for 'xccdf_org.ssgproject.content_rule_sshd_disable_root_login'
+###############################################################################
+touch "$(dirname "$0")/sshd_disable_root_login.fixed"
+# END fix for 'xccdf_org.ssgproject.content_rule_sshd_disable_root_login'
+`
+
+// newTestRemediationWorkspace returns a workspace with the test assessment results and a bash remediation.
+func newTestRemediationWorkspace(t *testing.T) string {
+	workspace := t.TempDir()
+	results, err := os.ReadFile(filepath.Join("testdata", assessmentResultsLocationJson))
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(filepath.Join(workspace, assessmentResultsLocationJson), results, 0600))
+	remediationDir := filepath.Join(workspace, "openscap", "remediations")
+	require.NoError(t, os.MkdirAll(remediationDir, 0700))
+	require.NoError(t, os.WriteFile(filepath.Join(remediationDir, "remediation-script.sh"), []byte(testRemediationScript), 0600))
+	return workspace
+}
+
+func TestSelectRemediationRules(t *testing.T) {
+	ar, err := complytime.ReadAssessmentResults(filepath.Join("testdata", assessmentResultsLocationJson), validation.NoopValidator{})
+	require.NoError(t, err)
+	index := complytime.NewResultsIndex(ar)
+	planRules := map[string][]string{
+		"rule_ssh_disable_root": {"sshd_disable_root_login"},
+		"rule_audit_enabled":    {"service_auditd_enabled"},
+	}
+
+	tests := []struct {
+		name    string
+		opts    remediateOptions
+		want    map[string][]string
+		wantErr string
+	}{
+		{
+			name: "Valid/Rules",
+			opts: remediateOptions{rules: []string{"rule_audit_enabled"}},
+			want: map[string][]string{"rule_audit_enabled": {"service_auditd_enabled"}},
+		},
+		{
+			name: "Valid/Failed",
+			opts: remediateOptions{failed: true},
+			want: map[string][]string{"rule_ssh_disable_root": {"sshd_disable_root_login"}},
+		},
+		{
+			name:    "Invalid/UnknownRule",
+			opts:    remediateOptions{rules: []string{"rule_unknown"}},
+			wantErr: "rule rule_unknown is not in the assessment plan",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			selected, err := selectRemediationRules(&tt.opts, planRules, index)
+			if tt.wantErr != "" {
+				require.EqualError(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.want, selected)
+		})
+	}
+}
+
+func TestGetRemediationColumnsAndRows(t *testing.T) {
+	workspace := newTestRemediationWorkspace(t)
+	remediations, err := complytime.FindRemediations(workspace)
+	require.NoError(t, err)
+	ar, err := complytime.ReadAssessmentResults(filepath.Join(workspace, assessmentResultsLocationJson), validation.NoopValidator{})
+	require.NoError(t, err)
+	planRules := map[string][]string{
+		"rule_ssh_disable_root": {"xccdf_org.ssgproject.content_rule_sshd_disable_root_login"},
+		"rule_audit_enabled":    {"xccdf_org.ssgproject.content_rule_service_auditd_enabled"},
+	}
+
+	columns, rows := getRemediationColumnsAndRows(planRules, remediations, complytime.NewResultsIndex(ar))
+	require.Len(t, columns, 3)
+	require.Equal(t, []table.Row{
+		{"rule_audit_enabled", "pass", "-"},
+		{"rule_ssh_disable_root", "fail", "bash"},
+	}, rows)
+}
+
+func TestApplyRemediations(t *testing.T) {
+	selected := map[string][]string{
+		"rule_ssh_disable_root": {"xccdf_org.ssgproject.content_rule_sshd_disable_root_login"},
+	}
+
+	tests := []struct {
+		name        string
+		dryRun      bool
+		yes         bool
+		input       string
+		wantErr     string
+		wantFixed   bool
+		wantRecords int
+	}{
+		{
+			name:        "Valid/Confirmed",
+			input:       "y\n",
+			wantFixed:   true,
+			wantRecords: 1,
+		},
+		{
+			name:        "Valid/Yes",
+			yes:         true,
+			wantFixed:   true,
+			wantRecords: 1,
+		},
+		{
+			name:   "Valid/DryRun",
+			dryRun: true,
+		},
+		{
+			name:    "Invalid/NotConfirmed",
+			input:   "n\n",
+			wantErr: "remediation aborted",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			workspace := newTestRemediationWorkspace(t)
+			remediations, err := complytime.FindRemediations(workspace)
+			require.NoError(t, err)
+			arPath := filepath.Join(workspace, assessmentResultsLocationJson)
+			ar, err := complytime.ReadAssessmentResults(arPath, validation.NoopValidator{})
+			require.NoError(t, err)
+
+			out := &bytes.Buffer{}
+			opts := &remediateOptions{
+				Common:          &option.Common{Output: option.Output{Out: out}},
+				complyTimeOpts:  &option.ComplyTime{UserWorkspace: workspace},
+				remediationType: complytime.RemediationBash,
+				dryRun:          tt.dryRun,
+				yes:             tt.yes,
+				in:              strings.NewReader(tt.input),
+			}
+			err = applyRemediations(context.Background(), opts, selected, remediations, ar)
+			if tt.wantErr != "" {
+				require.EqualError(t, err, tt.wantErr)
+			} else {
+				require.NoError(t, err)
+			}
+
+			fixedFiles, err := filepath.Glob(filepath.Join(workspace, remediationLogDir, "*.fixed"))
+			require.NoError(t, err)
+			require.Equal(t, tt.wantFixed, len(fixedFiles) == 1)
+			if tt.dryRun {
+				require.Contains(t, out.String(), "# bash remediation of openscap for rule(s) rule_ssh_disable_root")
+			}
+
+			written, err := complytime.ReadAssessmentResults(arPath, validation.NewSchemaValidator())
+			require.NoError(t, err)
+			var risks []oscalTypes.Risk
+			if written.Results[0].Risks != nil {
+				risks = *written.Results[0].Risks
+			}
+			require.Len(t, risks, tt.wantRecords)
+			if tt.wantRecords > 0 {
+				response := (*risks[0].Remediations)[0]
+				require.Equal(t, "completed", response.Lifecycle)
+				logPath := strings.TrimPrefix((*response.Links)[1].Href, "file://")
+				require.FileExists(t, logPath)
+			}
+		})
+	}
+}
+
+func TestValidateRemediate(t *testing.T) {
+	require.NoError(t, validateRemediate(&remediateOptions{remediationType: complytime.RemediationAnsible}))
+	require.EqualError(t, validateRemediate(&remediateOptions{remediationType: complytime.RemediationBlueprint}),
+		"blueprint remediations are applied when building an image and cannot be run by complyctl")
+	require.EqualError(t, validateRemediate(&remediateOptions{remediationType: "puppet"}),
+		"invalid remediation type \"puppet\": must be one of bash, ansible")
+}
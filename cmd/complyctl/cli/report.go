@@ -0,0 +1,290 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	oscalTypes "github.com/defenseunicorns/go-oscal/src/types/oscal-1-1-3"
+	"github.com/oscal-compass/compliance-to-policy-go/v2/framework"
+	"github.com/oscal-compass/oscal-sdk-go/extensions"
+	"github.com/oscal-compass/oscal-sdk-go/validation"
+	"github.com/spf13/cobra"
+
+	"github.com/complytime/complyctl/cmd/complyctl/option"
+	"github.com/complytime/complyctl/internal/complytime"
+	"github.com/complytime/complyctl/internal/report"
+)
+
+// Supported formats for the "report" subcommand
+const (
+	reportFormatMarkdown = "markdown"
+	reportFormatHTML     = "html"
+	reportFormatJUnit    = "junit"
+	reportFormatSARIF    = "sarif"
+	reportFormatCSV      = report.FormatCSV
+	reportFormatTSV      = report.FormatTSV
+)
+
+// reportInput holds the OSCAL artifacts used to render reports.
+type reportInput struct {
+	assessmentPlan    *oscalTypes.AssessmentPlan
+	assessmentResults *oscalTypes.AssessmentResults
+	// catalog is only loaded when a selected format requires it.
+	catalog *oscalTypes.Catalog
+	// ruleDefinitions are only loaded when a selected format requires them.
+	ruleDefinitions map[string]complytime.RuleDefinition
+}
+
+// reportRenderer renders the report input in a specific format.
+type reportRenderer struct {
+	// fileName is the name of the report written to the output directory.
+	fileName string
+	// needsCatalog is true when the catalog is required to render the report.
+	needsCatalog bool
+	// needsRuleDefinitions is true when the rule definitions from the component definitions
+	// are required to render the report.
+	needsRuleDefinitions bool
+	render               func(input reportInput, path string) ([]byte, error)
+}
+
+// reportRenderers are the supported report renderers by format.
+var reportRenderers = map[string]reportRenderer{
+	reportFormatMarkdown: {fileName: assessmentResultsLocationMd, needsCatalog: true, render: renderMarkdownReport},
+	reportFormatHTML:     {fileName: assessmentResultsLocationHtml, needsCatalog: true, render: renderHTMLReport},
+	reportFormatJUnit:    {fileName: assessmentResultsLocationJUnit, render: renderJUnitReport},
+	reportFormatSARIF:    {fileName: assessmentResultsLocationSARIF, needsRuleDefinitions: true, render: renderSARIFReport},
+	reportFormatCSV:      {fileName: assessmentResultsLocationCSV, needsRuleDefinitions: true, render: renderDelimitedReport(reportFormatCSV)},
+	reportFormatTSV:      {fileName: assessmentResultsLocationTSV, needsRuleDefinitions: true, render: renderDelimitedReport(reportFormatTSV)},
+}
+
+// reportOptions defines options for the "report" subcommand
+type reportOptions struct {
+	*option.Common
+	complyTimeOpts *option.ComplyTime
+	// formats are the report formats to write
+	formats []string
+	// outputDir is the directory where reports are written, defaults to the workspace
+	outputDir string
+}
+
+var reportExample = `
+# Render the assessment results in the workspace as markdown without scanning again.
+complyctl report
+
+# Render a self-contained HTML report in addition to markdown.
+complyctl report --format markdown,html
+
+# Render JUnit XML for CI systems and SARIF for code-scanning tools.
+complyctl report --format junit,sarif
+
+# Render a flat table with one row per control, rule, and subject for spreadsheets.
+complyctl report --format csv
+
+# Render reports from results collected on another host.
+complyctl report --workspace ./collected --output-dir ./reports
+
+# Render reports of a workspace with several frameworks to a subdirectory per framework.
+complyctl report --workspace ./multi --output-dir ./reports
+`
+
+// reportCmd creates a new cobra.Command for the "report" subcommand
+func reportCmd(common *option.Common) *cobra.Command {
+	reportOpts := &reportOptions{
+		Common:         common,
+		complyTimeOpts: &option.ComplyTime{},
+	}
+	cmd := &cobra.Command{
+		Use:          "report [flags]",
+		Short:        "Render reports from existing assessment results",
+		Example:      reportExample,
+		SilenceUsage: true,
+		Args:         cobra.NoArgs,
+		RunE: func(_ *cobra.Command, _ []string) error {
+			if err := validateReport(reportOpts); err != nil {
+				return err
+			}
+			return runReport(reportOpts)
+		},
+	}
+	cmd.Flags().StringSliceVarP(&reportOpts.formats, "format", "f", []string{reportFormatMarkdown},
+		fmt.Sprintf("report formats to write, any of: %s", strings.Join(supportedReportFormats(), ", ")))
+	cmd.Flags().StringVarP(&reportOpts.outputDir, "output-dir", "o", "", "directory where reports are written, defaults to the workspace")
+	reportOpts.complyTimeOpts.BindFlags(cmd.Flags())
+	return cmd
+}
+
+func validateReport(opts *reportOptions) error {
+	if len(opts.formats) == 0 {
+		return errors.New("at least one report format must be specified")
+	}
+	return validateReportFormats(opts.formats)
+}
+
+// validateReportFormats returns an error if any of the given formats is not supported.
+func validateReportFormats(formats []string) error {
+	for _, format := range formats {
+		if _, ok := reportRenderers[format]; !ok {
+			return fmt.Errorf("invalid report format %q: must be one of %s", format, strings.Join(supportedReportFormats(), ", "))
+		}
+	}
+	return nil
+}
+
+func runReport(opts *reportOptions) error {
+	workspaces, err := frameworkWorkspaces(opts.complyTimeOpts)
+	if err != nil {
+		return err
+	}
+	for _, workspace := range workspaces {
+		frameworkOpts := *opts
+		frameworkOpts.complyTimeOpts = workspace
+		if len(workspaces) > 1 && opts.outputDir != "" {
+			frameworkOpts.outputDir = filepath.Join(opts.outputDir, workspace.FrameworkID)
+		}
+		if err := reportFramework(&frameworkOpts); err != nil {
+			return fmt.Errorf("error reporting framework %s: %w", workspace.FrameworkID, err)
+		}
+	}
+	return nil
+}
+
+// reportFramework renders the reports of the assessment results in the workspace.
+func reportFramework(opts *reportOptions) error {
+	validator := validation.NewSchemaValidator()
+	ap, _, err := loadPlan(opts.complyTimeOpts, validator)
+	if err != nil {
+		return err
+	}
+	ar, err := loadResults(opts.complyTimeOpts, validator)
+	if err != nil {
+		return err
+	}
+	if complytime.IsPartialAssessment(ar) {
+		logger.Warn("The assessment results are partial, only the selected rules and controls were assessed by the last scan.")
+	}
+	if complytime.IsAbortedAssessment(ar) {
+		logger.Warn("The assessment results are incomplete, the last scan was canceled before all plugins returned results.")
+	}
+
+	appDir, err := complytime.NewApplicationDirectory(true, logger)
+	if err != nil {
+		return err
+	}
+	logger.Debug(fmt.Sprintf("Using application directory: %s", appDir.AppDir()))
+
+	outputDir := opts.outputDir
+	if outputDir == "" {
+		outputDir = opts.complyTimeOpts.UserWorkspace
+	}
+	if err := os.MkdirAll(outputDir, 0700); err != nil {
+		return fmt.Errorf("error creating output directory %s: %w", outputDir, err)
+	}
+	input := reportInput{assessmentPlan: ap, assessmentResults: ar}
+	return writeReports(appDir, validator, input, outputDir, opts.formats)
+}
+
+// loadResults returns the loaded assessment results from the workspace.
+func loadResults(opts *option.ComplyTime, validator validation.Validator) (*oscalTypes.AssessmentResults, error) {
+	arPath := filepath.Clean(filepath.Join(opts.UserWorkspace, assessmentResultsLocationJson))
+	assessmentResults, err := complytime.ReadAssessmentResults(arPath, validator)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, fmt.Errorf("error: assessment results do not exist in workspace %s: %w\n\nDid you run the scan command?",
+				opts.UserWorkspace,
+				err)
+		}
+		return nil, err
+	}
+	return assessmentResults, nil
+}
+
+// writeReports renders the report input in each of the given formats and writes the reports to the output directory.
+func writeReports(appDir complytime.ApplicationDirectory, validator validation.Validator, input reportInput, outputDir string, formats []string) error {
+	for _, format := range formats {
+		renderer, ok := reportRenderers[format]
+		if !ok {
+			return fmt.Errorf("invalid report format %q", format)
+		}
+		if renderer.needsCatalog && input.catalog == nil {
+			catalog, err := loadPlanCatalog(appDir, input.assessmentPlan, validator)
+			if err != nil {
+				return err
+			}
+			input.catalog = catalog
+		}
+		if renderer.needsRuleDefinitions && input.ruleDefinitions == nil {
+			compDefs, err := complytime.FindComponentDefinitions(appDir.BundleDir(), validator)
+			if err != nil {
+				return err
+			}
+			input.ruleDefinitions = complytime.RuleDefinitions(compDefs)
+		}
+
+		reportPath := filepath.Join(outputDir, renderer.fileName)
+		content, err := renderer.render(input, reportPath)
+		if err != nil {
+			return fmt.Errorf("error rendering %s report: %w", format, err)
+		}
+		if err := os.WriteFile(reportPath, content, 0600); err != nil {
+			return err
+		}
+		logger.Info(fmt.Sprintf("The assessment results in %s were successfully written to %v.", format, reportPath))
+	}
+	return nil
+}
+
+// loadPlanCatalog returns the catalog for the framework captured in the assessment plan.
+func loadPlanCatalog(appDir complytime.ApplicationDirectory, ap *oscalTypes.AssessmentPlan, validator validation.Validator) (*oscalTypes.Catalog, error) {
+	if ap.Metadata.Props == nil {
+		return nil, fmt.Errorf("error reading framework property from assessment plan")
+	}
+	frameworkProp, valid := extensions.GetTrestleProp(extensions.FrameworkProp, *ap.Metadata.Props)
+	if !valid {
+		return nil, fmt.Errorf("error reading framework property from assessment plan")
+	}
+	return complytime.LoadFrameworkCatalog(appDir, frameworkProp.Value, validator)
+}
+
+// renderMarkdownReport renders the assessment results posture as markdown.
+func renderMarkdownReport(input reportInput, path string) ([]byte, error) {
+	posture := framework.NewPosture(input.assessmentResults, input.catalog, input.assessmentPlan, logger)
+	return posture.Generate(path)
+}
+
+// renderHTMLReport renders the assessment results as a self-contained HTML document.
+func renderHTMLReport(input reportInput, _ string) ([]byte, error) {
+	return report.RenderHTML(report.New(input.assessmentPlan, input.assessmentResults, input.catalog))
+}
+
+// renderJUnitReport renders the assessment results as JUnit XML.
+func renderJUnitReport(input reportInput, _ string) ([]byte, error) {
+	return report.RenderJUnit(report.New(input.assessmentPlan, input.assessmentResults, input.catalog))
+}
+
+// renderSARIFReport renders the assessment results as a SARIF log.
+func renderSARIFReport(input reportInput, _ string) ([]byte, error) {
+	return report.RenderSARIF(report.New(input.assessmentPlan, input.assessmentResults, input.catalog), input.ruleDefinitions)
+}
+
+// renderDelimitedReport returns a renderer of the assessment results as a flat CSV or TSV table.
+func renderDelimitedReport(format string) func(input reportInput, _ string) ([]byte, error) {
+	return func(input reportInput, _ string) ([]byte, error) {
+		return report.RenderDelimited(report.New(input.assessmentPlan, input.assessmentResults, input.catalog), input.ruleDefinitions, format)
+	}
+}
+
+// supportedReportFormats returns the sorted names of the supported report formats.
+func supportedReportFormats() []string {
+	formats := make([]string, 0, len(reportRenderers))
+	for format := range reportRenderers {
+		formats = append(formats, format)
+	}
+	sort.Strings(formats)
+	return formats
+}
@@ -0,0 +1,59 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package cli
+
+import (
+	"testing"
+
+	"github.com/oscal-compass/oscal-sdk-go/validation"
+	"github.com/stretchr/testify/require"
+
+	"github.com/complytime/complyctl/cmd/complyctl/option"
+)
+
+func TestResultsInWorkspace(t *testing.T) {
+	testOpts := &option.ComplyTime{
+		UserWorkspace: "doesnotexist",
+	}
+	wantErr := "error: assessment results do not exist in workspace doesnotexist: o" +
+		"pen doesnotexist/assessment-results.json: no such file or directory\n\nDid you run the scan command?"
+	_, gotErr := loadResults(testOpts, validation.NoopValidator{})
+	require.EqualError(t, gotErr, wantErr)
+
+	testOpts.UserWorkspace = "testdata"
+	ar, err := loadResults(testOpts, validation.NoopValidator{})
+	require.NoError(t, err)
+	require.Len(t, ar.Results, 1)
+}
+
+func TestValidateReport(t *testing.T) {
+	tests := []struct {
+		name    string
+		formats []string
+		wantErr string
+	}{
+		{
+			name:    "Valid/DefaultFormat",
+			formats: []string{"markdown"},
+		},
+		{
+			name:    "Invalid/NoFormat",
+			wantErr: "at least one report format must be specified",
+		},
+		{
+			name:    "Invalid/UnknownFormat",
+			formats: []string{"markdown", "pdf"},
+			wantErr: "invalid report format \"pdf\": must be one of csv, html, junit, markdown, sarif, tsv",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateReport(&reportOptions{formats: tt.formats})
+			if tt.wantErr != "" {
+				require.EqualError(t, err, tt.wantErr)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
@@ -3,6 +3,7 @@
 package cli
 
 import (
+	"errors"
 	"os"
 
 	"github.com/hashicorp/go-hclog"
@@ -14,6 +15,15 @@ import (
 
 var logger hclog.Logger
 
+// Exit codes of complyctl.
+const (
+	// ExitCodeError is the exit code of a failed command.
+	ExitCodeError = 1
+	// ExitCodePartialResults is the exit code of a scan that wrote the assessment results
+	// without the results of the plugins that failed.
+	ExitCodePartialResults = 3
+)
+
 func init() {
 	logger = log.NewLogger(os.Stdout)
 }
@@ -22,6 +32,14 @@ func Error(msg string) {
 	logger.Error(msg)
 }
 
+// ExitCode returns the exit code of complyctl for the error of a command.
+func ExitCode(err error) int {
+	if errors.Is(err, errPartialResults) {
+		return ExitCodePartialResults
+	}
+	return ExitCodeError
+}
+
 func enableDebug(opts *option.Common) {
 	if opts.Debug {
 		logger.SetLevel(hclog.Debug)
@@ -53,6 +71,14 @@ func New() *cobra.Command {
 		planCmd(&opts),
 		listCmd(&opts),
 		infoCmd(&opts),
+		diffCmd(&opts),
+		reportCmd(&opts),
+		validateCmd(&opts),
+		doctorCmd(&opts),
+		pluginCmd(&opts),
+		bundleCmd(&opts),
+		remediateCmd(&opts),
+		scopeCmd(&opts),
 	)
 	cmd.PersistentPreRun = func(_ *cobra.Command, _ []string) { enableDebug(&opts) }
 
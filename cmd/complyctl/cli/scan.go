@@ -3,15 +3,19 @@
 package cli
 
 import (
+	"context"
 	"errors"
 	"fmt"
-	"os"
 	"path/filepath"
+	"slices"
+	"strings"
+	"time"
 
+	oscalTypes "github.com/defenseunicorns/go-oscal/src/types/oscal-1-1-3"
 	"github.com/oscal-compass/compliance-to-policy-go/v2/framework"
 	"github.com/oscal-compass/compliance-to-policy-go/v2/framework/actions"
+	"github.com/oscal-compass/compliance-to-policy-go/v2/policy"
 	"github.com/oscal-compass/oscal-sdk-go/extensions"
-	"github.com/oscal-compass/oscal-sdk-go/settings"
 	"github.com/oscal-compass/oscal-sdk-go/validation"
 	"github.com/spf13/cobra"
 
@@ -21,45 +25,91 @@ import (
 
 const assessmentResultsLocationJson = "assessment-results.json"
 const assessmentResultsLocationMd = "assessment-results.md"
+const assessmentResultsLocationHtml = "assessment-results.html"
+const assessmentResultsLocationJUnit = "assessment-results.junit.xml"
+const assessmentResultsLocationSARIF = "assessment-results.sarif"
+const assessmentResultsLocationCSV = "assessment-results.csv"
+const assessmentResultsLocationTSV = "assessment-results.tsv"
+
+// partialWorkspaceLocation is the directory of the workspace where plugins generate and evaluate the
+// policy of selected rules, so that the policy of the assessment plan is kept.
+const partialWorkspaceLocation = "partial"
+
+// errPartialResults is returned when the assessment results were written without the results of some plugins.
+var errPartialResults = errors.New("some plugins failed, the assessment results are partial")
 
 // scanOptions defined options for the scan subcommand.
 type scanOptions struct {
 	*option.Common
 	complyTimeOpts   *option.ComplyTime
+	verifyOpts       *option.Verification
+	selectionOpts    *option.Selection
 	withPluginConfig string
+	// reportFormats are additional report formats written after the scan
+	reportFormats []string
+	// pluginTimeout is the timeout of plugins whose manifest does not set one
+	pluginTimeout time.Duration
+	// failFast fails the scan without writing the results when a plugin fails
+	failFast bool
 }
 
+var scanExample = `
+# Scan the environment with the assessment plan and the generated policy.
+complyctl scan
+
+# Only evaluate a failing rule while debugging it. The results are marked as partial.
+complyctl scan --rule sshd_set_idle_timeout
+
+# Only evaluate the rules of the selected controls.
+complyctl scan --control "ac-*" --control cm-6
+
+# Report the plugins that do not return within 20 minutes as failed, their rules get error results.
+complyctl scan --plugin-timeout 20m
+
+# Fail without writing the results as soon as a plugin fails.
+complyctl scan --fail-fast
+`
+
 // scanCmd creates a new cobra.Command for the version subcommand.
 func scanCmd(common *option.Common) *cobra.Command {
 	scanOpts := &scanOptions{
 		Common:         common,
 		complyTimeOpts: &option.ComplyTime{},
+		verifyOpts:     &option.Verification{},
+		selectionOpts:  &option.Selection{},
 	}
 	cmd := &cobra.Command{
 		Use:          "scan [flags]",
 		Short:        "Scan environment with assessment plan",
-		Example:      "complyctl scan",
+		Example:      scanExample,
 		SilenceUsage: true,
 		Args:         cobra.NoArgs,
 		RunE: func(cmd *cobra.Command, _ []string) error {
+			if err := validateReportFormats(scanOpts.reportFormats); err != nil {
+				return err
+			}
 			return runScan(cmd, scanOpts)
 		},
 	}
 	cmd.Flags().StringVarP(&scanOpts.withPluginConfig, "plugin-config", "c", "", "Directory where user customized plugin manifests are located")
 	cmd.Flags().BoolP("with-md", "m", false, "If true, assessement-result markdown will be generated")
+	cmd.Flags().Bool("with-html", false, "If true, a self-contained assessement-result HTML report will be generated")
+	_ = cmd.Flags().MarkDeprecated("with-md", "use --report-format markdown instead")
+	_ = cmd.Flags().MarkDeprecated("with-html", "use --report-format html instead")
+	cmd.Flags().StringSliceVar(&scanOpts.reportFormats, "report-format", nil,
+		fmt.Sprintf("additional report formats to write to the workspace, any of: %s", strings.Join(supportedReportFormats(), ", ")))
+	cmd.Flags().DurationVar(&scanOpts.pluginTimeout, "plugin-timeout", 0,
+		"timeout of the plugins whose manifest does not set one, 0 waits until the plugins return")
+	cmd.Flags().BoolVar(&scanOpts.failFast, "fail-fast", false,
+		fmt.Sprintf("fail without writing the results when a plugin fails, instead of writing the results of the other plugins and exiting with code %d", ExitCodePartialResults))
 	scanOpts.complyTimeOpts.BindFlags(cmd.Flags())
+	scanOpts.verifyOpts.BindFlags(cmd.Flags())
+	scanOpts.selectionOpts.BindFlags(cmd.Flags())
 	return cmd
 }
 
 func runScan(cmd *cobra.Command, opts *scanOptions) error {
-	validator := validation.NewSchemaValidator()
-	// Load settings from assessment plan
-	ap, apCleanedPath, err := loadPlan(opts.complyTimeOpts, validator)
-	if err != nil {
-		return err
-	}
-
-	inputContext, err := complytime.ActionsContextFromPlan(ap)
+	workspaces, err := frameworkWorkspaces(opts.complyTimeOpts)
 	if err != nil {
 		return err
 	}
@@ -70,10 +120,82 @@ func runScan(cmd *cobra.Command, opts *scanOptions) error {
 		return err
 	}
 	logger.Debug(fmt.Sprintf("Using application directory: %s", appDir.AppDir()))
+	if _, err := verifyContent(appDir, opts.verifyOpts); err != nil {
+		return err
+	}
+
+	if len(workspaces) == 1 {
+		_, err := scanFramework(cmd, opts, appDir)
+		return err
+	}
+	summaries := make([]string, 0, len(workspaces))
+	var partialErr error
+	for _, workspace := range workspaces {
+		logger.Info(fmt.Sprintf("Scanning framework %s in %s.", workspace.FrameworkID, workspace.UserWorkspace))
+		frameworkOpts := *opts
+		frameworkOpts.complyTimeOpts = workspace
+		assessmentResults, err := scanFramework(cmd, &frameworkOpts, appDir)
+		if errors.Is(err, errPartialResults) {
+			// The results of the other plugins were written, so the other frameworks are still scanned
+			partialErr = err
+		} else if err != nil {
+			return fmt.Errorf("error scanning framework %s: %w", workspace.FrameworkID, err)
+		}
+		summaries = append(summaries, summarizeResults(workspace, assessmentResults))
+	}
+	for _, summary := range summaries {
+		logger.Info(summary)
+	}
+	return partialErr
+}
+
+// summarizeResults returns a one-line summary of the rule results of a framework.
+func summarizeResults(workspace *option.ComplyTime, assessmentResults *oscalTypes.AssessmentResults) string {
+	index := complytime.NewResultsIndex(assessmentResults)
+	counts := make(map[string]int)
+	for _, ruleID := range index.RuleIDs() {
+		status := index.Rules[ruleID].Status()
+		switch {
+		case status == policy.ResultPass.String(), status == complytime.ResultWaived:
+		case complytime.IsFailingResult(status):
+			status = policy.ResultFail.String()
+		default:
+			status = "other"
+		}
+		counts[status]++
+	}
+	return fmt.Sprintf("Framework %s: %d passed, %d failed, %d waived, %d other. Results are in %s.",
+		workspace.FrameworkID,
+		counts[policy.ResultPass.String()],
+		counts[policy.ResultFail.String()],
+		counts[complytime.ResultWaived],
+		counts["other"],
+		filepath.Join(workspace.UserWorkspace, assessmentResultsLocationJson))
+}
+
+// scanFramework scans the environment with the assessment plan in the workspace and writes the results to it.
+func scanFramework(cmd *cobra.Command, opts *scanOptions, appDir complytime.ApplicationDirectory) (*oscalTypes.AssessmentResults, error) {
+	validator := validation.NewSchemaValidator()
+	// Load settings from assessment plan
+	ap, apCleanedPath, err := loadPlan(opts.complyTimeOpts, validator)
+	if err != nil {
+		return nil, err
+	}
+	selection := opts.selectionOpts.ToSelection()
+	if !selection.IsEmpty() {
+		if err := selection.NarrowPlan(ap); err != nil {
+			return nil, fmt.Errorf("error selecting rules and controls: %w", err)
+		}
+	}
+
+	inputContext, err := complytime.ActionsContextFromPlan(ap)
+	if err != nil {
+		return nil, err
+	}
 
 	cfg, err := complytime.Config(appDir)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	// set config logger to CLI charm logger
@@ -81,103 +203,146 @@ func runScan(cmd *cobra.Command, opts *scanOptions) error {
 
 	manager, err := framework.NewPluginManager(cfg)
 	if err != nil {
-		return fmt.Errorf("error initializing plugin manager: %w", err)
+		return nil, fmt.Errorf("error initializing plugin manager: %w", err)
 	}
 
 	// Determine what profile to load from framework information captured
 	// from state (assessment plan). This is required to populate complyTime required plugin options.
 	frameworkProp, valid := extensions.GetTrestleProp(extensions.FrameworkProp, *ap.Metadata.Props)
 	if !valid {
-		return fmt.Errorf("error reading framework property from assessment plan")
+		return nil, fmt.Errorf("error reading framework property from assessment plan")
 	}
 	opts.complyTimeOpts.FrameworkID = frameworkProp.Value
 	logger.Debug(fmt.Sprintf("Framework property was successfully read from the assessment plan: %v.", frameworkProp))
 
 	pluginOptions := opts.complyTimeOpts.ToPluginOptions()
 	pluginOptions.UserConfigRoot = opts.withPluginConfig
-	plugins, cleanup, err := complytime.Plugins(manager, inputContext, pluginOptions, logger)
+	if !selection.IsEmpty() {
+		// The policy of the selected rules does not replace the policy of the assessment plan
+		pluginOptions.Workspace = filepath.Join(pluginOptions.Workspace, partialWorkspaceLocation)
+	}
+	plugins, cleanup, err := complytime.Plugins(cmd.Context(), manager, inputContext, pluginOptions, logger)
 	if cleanup != nil {
 		defer cleanup()
 	}
+	planHref := fmt.Sprintf("file://%s", apCleanedPath)
 	if err != nil {
-		return fmt.Errorf("errors launching plugins: %w", err)
+		if cmd.Context().Err() != nil {
+			return nil, abortScan(cmd.Context(), opts, inputContext, planHref, ap, selection, nil, nil)
+		}
+		return nil, fmt.Errorf("errors launching plugins: %w", err)
 	}
 	logger.Info(fmt.Sprintf("Successfully loaded %v plugin(s).", len(plugins)))
 
-	allResults, err := actions.AggregateResults(cmd.Context(), inputContext, plugins)
-	if err != nil {
-		return err
+	if !selection.IsEmpty() {
+		// Plugins only evaluate the policy they generated, so the policy of the selected rules is generated first
+		if err := actions.GeneratePolicy(cmd.Context(), inputContext, plugins); err != nil {
+			if cmd.Context().Err() != nil {
+				return nil, abortScan(cmd.Context(), opts, inputContext, planHref, ap, selection, nil, nil)
+			}
+			return nil, fmt.Errorf("error generating the policy of the selected rules: %w", err)
+		}
+		logger.Debug(fmt.Sprintf("Generated the policy of the selected rules in %s.", pluginOptions.Workspace))
 	}
 
-	// Collect results in a single report
-	planHref := fmt.Sprintf("file://%s", apCleanedPath)
-	assessmentResults, err := actions.Report(cmd.Context(), inputContext, planHref, *ap, allResults)
+	timeouts, err := complytime.PluginTimeouts(appDir, opts.withPluginConfig, inputContext.RequestedProviders(), opts.pluginTimeout)
 	if err != nil {
-		return err
+		return nil, err
+	}
+	allResults, failures, err := complytime.AggregateResults(cmd.Context(), inputContext, plugins, timeouts)
+	if cmd.Context().Err() != nil {
+		return nil, abortScan(cmd.Context(), opts, inputContext, planHref, ap, selection, allResults, failures)
 	}
-	arJsonPath := filepath.Join(opts.complyTimeOpts.UserWorkspace, assessmentResultsLocationJson)
-	err = complytime.WriteAssessmentResults(assessmentResults, arJsonPath)
 	if err != nil {
-		return err
+		return nil, err
 	}
-	logger.Info(fmt.Sprintf("The assessment results in JSON were successfully written to %v.", arJsonPath))
-
-	outputFlag, _ := cmd.Flags().GetBool("with-md")
-	if outputFlag {
-		var profileHref string
-		compDefs, err := complytime.FindComponentDefinitions(appDir.BundleDir(), validator)
-		if err != nil {
-			return err
-		}
-		for _, compDef := range compDefs {
-			if compDef.Components == nil {
-				continue
-			}
-			for _, component := range *compDef.Components {
-				if component.ControlImplementations == nil {
-					continue
-				}
-				for _, implementation := range *component.ControlImplementations {
-					frameworkShortName, found := settings.GetFrameworkShortName(implementation)
-					// If the framework property value match the assessment plan framework property values
-					// this is the correct control source.
-					if found && frameworkShortName == frameworkProp.Value {
-						profileHref = implementation.Source
-						break
-					}
-				}
-				if profileHref != "" {
-					break
-				}
-			}
+	for _, failure := range failures {
+		if opts.failFast {
+			return nil, fmt.Errorf("plugin %s failed: %w", failure.PluginID, failure.Err)
 		}
+		logger.Error(fmt.Sprintf("Plugin %s failed, its rules are reported with error results: %v", failure.PluginID, failure.Err))
+	}
 
-		profile, err := complytime.LoadProfile(appDir, profileHref, validator)
-		if err != nil {
-			return err
-		}
+	assessmentResults, err := newAssessmentResults(cmd.Context(), inputContext, planHref, ap, selection, allResults, failures)
+	if err != nil {
+		return nil, err
+	}
+	if err := writeAssessmentResults(opts, assessmentResults); err != nil {
+		return nil, err
+	}
+	var scanErr error
+	if len(failures) > 0 {
+		scanErr = errPartialResults
+	}
 
-		if len(profile.Imports) != 1 {
-			return errors.New("profile imports must be one")
-		}
-		catalog, err := complytime.LoadCatalogSource(appDir, profile.Imports[0].Href, validator)
-		if err != nil {
-			return err
-		}
-		arMarkdownPath := filepath.Join(opts.complyTimeOpts.UserWorkspace, assessmentResultsLocationMd)
+	reportFormats := opts.reportFormats
+	if withMd, _ := cmd.Flags().GetBool("with-md"); withMd {
+		reportFormats = complytime.AppendUnique(reportFormats, reportFormatMarkdown)
+	}
+	if withHtml, _ := cmd.Flags().GetBool("with-html"); withHtml {
+		reportFormats = complytime.AppendUnique(reportFormats, reportFormatHTML)
+	}
+	if len(reportFormats) == 0 {
+		logger.Info("No assessment result reports will be generated.")
+		return assessmentResults, scanErr
+	}
+	input := reportInput{assessmentPlan: ap, assessmentResults: assessmentResults}
+	if err := writeReports(appDir, validator, input, opts.complyTimeOpts.UserWorkspace, reportFormats); err != nil {
+		return nil, err
+	}
+	return assessmentResults, scanErr
+}
 
-		posture := framework.NewPosture(assessmentResults, catalog, ap, logger)
-		assessmentResultsMd, err := posture.Generate(arMarkdownPath)
-		if err != nil {
-			return err
-		}
-		err = os.WriteFile(arMarkdownPath, assessmentResultsMd, 0600)
-		if err != nil {
-			return err
-		}
-		logger.Info(fmt.Sprintf("The assessment results in markdown were successfully written to %v.", arMarkdownPath))
-	} else {
-		logger.Info("No assessment result in markdown will be generated.")
+// abortScan writes the results collected before the scan was canceled, marked as aborted, so the results
+// of a previous scan are not mistaken for the results of this one. The error of the canceled scan is returned.
+func abortScan(ctx context.Context, opts *scanOptions, inputContext *actions.InputContext, planHref string, ap *oscalTypes.AssessmentPlan, selection complytime.Selection, results []policy.PVPResult, failures []complytime.PluginFailure) error {
+	cause := ctx.Err()
+	logger.Warn("The scan was canceled, writing the results collected so far as an aborted assessment.")
+	for _, failure := range failures {
+		logger.Warn(fmt.Sprintf("Plugin %s did not return results: %v", failure.PluginID, failure.Err))
+	}
+	assessmentResults, err := newAssessmentResults(context.WithoutCancel(ctx), inputContext, planHref, ap, selection, results, failures)
+	if err != nil {
+		return fmt.Errorf("scan canceled, the aborted assessment results cannot be written: %w", err)
+	}
+	complytime.AddAbortedProps(assessmentResults, cause)
+	if err := writeAssessmentResults(opts, assessmentResults); err != nil {
+		return fmt.Errorf("scan canceled, the aborted assessment results cannot be written: %w", err)
+	}
+	return fmt.Errorf("scan canceled before all plugins returned results: %w", cause)
+}
+
+// newAssessmentResults collects the plugin results in a single report with the waiver, plugin failure,
+// and partial assessment properties.
+func newAssessmentResults(ctx context.Context, inputContext *actions.InputContext, planHref string, ap *oscalTypes.AssessmentPlan, selection complytime.Selection, results []policy.PVPResult, failures []complytime.PluginFailure) (*oscalTypes.AssessmentResults, error) {
+	// Rules with an expired waiver are reported as evaluated
+	complytime.ExpireWaivers(ap, time.Now(), logger)
+
+	assessmentResults, err := actions.Report(ctx, inputContext, planHref, *ap, results)
+	if err != nil {
+		return nil, err
 	}
+	complytime.AddWaiverProps(assessmentResults, ap)
+	complytime.AddPluginFailureProps(assessmentResults, failures)
+	if followUp, isFollowUp := complytime.FollowUpSelection(ap); isFollowUp {
+		logger.Warn("The assessment plan only re-checks the rules that failed or errored, the assessment results are partial.")
+		selection.Rules = append(slices.Clone(selection.Rules), followUp.Rules...)
+		selection.Controls = append(slices.Clone(selection.Controls), followUp.Controls...)
+	} else if !selection.IsEmpty() {
+		logger.Warn("Only the selected rules and controls were assessed, the assessment results are partial.")
+	}
+	if !selection.IsEmpty() {
+		complytime.AddPartialProps(assessmentResults, selection)
+	}
+	return assessmentResults, nil
+}
+
+// writeAssessmentResults writes the assessment results in JSON to the workspace.
+func writeAssessmentResults(opts *scanOptions, assessmentResults *oscalTypes.AssessmentResults) error {
+	arJsonPath := filepath.Join(opts.complyTimeOpts.UserWorkspace, assessmentResultsLocationJson)
+	if err := complytime.WriteAssessmentResults(assessmentResults, arJsonPath); err != nil {
+		return err
+	}
+	logger.Info(fmt.Sprintf("The assessment results in JSON were successfully written to %v.", arJsonPath))
 	return nil
 }
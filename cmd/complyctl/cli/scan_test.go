@@ -0,0 +1,52 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package cli
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/oscal-compass/oscal-sdk-go/validation"
+	"github.com/stretchr/testify/require"
+
+	"github.com/complytime/complyctl/cmd/complyctl/option"
+	"github.com/complytime/complyctl/internal/complytime"
+)
+
+func TestAbortScan(t *testing.T) {
+	testAppDir(t)
+	workspace := t.TempDir()
+	runPlanCmd(t, workspace, "example")
+	ap, err := complytime.ReadPlan(filepath.Join(workspace, assessmentPlanLocation), validation.NoopValidator{})
+	require.NoError(t, err)
+	inputContext, err := complytime.ActionsContextFromPlan(ap)
+	require.NoError(t, err)
+	opts := &scanOptions{complyTimeOpts: &option.ComplyTime{UserWorkspace: workspace}}
+
+	// A scan canceled before the plugins returned still replaces the previous results.
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	err = abortScan(ctx, opts, inputContext, "file://assessment-plan.json", ap, complytime.Selection{}, nil, nil)
+	require.EqualError(t, err, "scan canceled before all plugins returned results: context canceled")
+	require.ErrorIs(t, err, context.Canceled)
+
+	assessmentResults, err := complytime.ReadAssessmentResults(filepath.Join(workspace, assessmentResultsLocationJson), validation.NoopValidator{})
+	require.NoError(t, err)
+	require.True(t, complytime.IsAbortedAssessment(assessmentResults))
+}
+
+func TestFollowUpScanIsPartial(t *testing.T) {
+	testAppDir(t)
+	workspace := t.TempDir()
+	runPlanCmd(t, workspace, "example", "--from-results", filepath.Join("testdata", "assessment-results.json"))
+	ap, err := complytime.ReadPlan(filepath.Join(workspace, assessmentPlanLocation), validation.NoopValidator{})
+	require.NoError(t, err)
+	inputContext, err := complytime.ActionsContextFromPlan(ap)
+	require.NoError(t, err)
+
+	// The results of a re-check are partial even without a selection of rules and controls.
+	assessmentResults, err := newAssessmentResults(context.Background(), inputContext, "file://assessment-plan.json", ap, complytime.Selection{}, nil, nil)
+	require.NoError(t, err)
+	require.True(t, complytime.IsPartialAssessment(assessmentResults))
+}
@@ -0,0 +1,116 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"io"
+
+	oscalTypes "github.com/defenseunicorns/go-oscal/src/types/oscal-1-1-3"
+	"github.com/oscal-compass/oscal-sdk-go/validation"
+	"github.com/spf13/cobra"
+
+	"github.com/complytime/complyctl/cmd/complyctl/option"
+	"github.com/complytime/complyctl/internal/complytime"
+)
+
+// errScopeProblems is returned when mistakes are found in a scope config.
+var errScopeProblems = errors.New("scope config problems found")
+
+// scopeOptions defines options for the "scope" subcommands
+type scopeOptions struct {
+	*option.Common
+	scopeConfigs []string
+}
+
+var scopeExample = `
+# Check the controls, rules, and parameters of a scope config against the bundle.
+complyctl scope lint config.yml
+
+# Check an environment overlay merged after the baseline scope config.
+complyctl scope lint baseline.yml prod.yml
+`
+
+// scopeCmd creates a new cobra.Command for the "scope" subcommand
+func scopeCmd(common *option.Common) *cobra.Command {
+	scopeOpts := &scopeOptions{
+		Common: common,
+	}
+	cmd := &cobra.Command{
+		Use:     "scope",
+		Short:   "Check assessment scope configs.",
+		Example: scopeExample,
+		Args:    cobra.NoArgs,
+	}
+	cmd.AddCommand(
+		&cobra.Command{
+			Use:   "lint config-file...",
+			Short: "Report unknown controls, rules, and parameters in a scope config.",
+			Long: "Cross-check every controlId, includeRules, excludeRules, waiveRules, globalExcludeRules, " +
+				"globalWaiveRules, and selectParameters entry of a scope config against the component definitions " +
+				"of its framework. Each mistake is reported with its line and the closest valid ID. Control selectors " +
+				"and rule patterns are reported when they are invalid or match nothing. Several scope configs are " +
+				"checked as layers merged in order, like the scope configs of the plan command.",
+			SilenceUsage: true,
+			Args:         cobra.MinimumNArgs(1),
+			PreRun: func(_ *cobra.Command, args []string) {
+				scopeOpts.scopeConfigs = args
+			},
+			RunE: func(_ *cobra.Command, _ []string) error { return runScopeLint(scopeOpts) },
+		},
+	)
+	return cmd
+}
+
+func runScopeLint(opts *scopeOptions) error {
+	appDir, err := complytime.NewApplicationDirectory(true, logger)
+	if err != nil {
+		return err
+	}
+	logger.Debug(fmt.Sprintf("Using application directory: %s", appDir.AppDir()))
+
+	componentDefs, err := complytime.FindComponentDefinitions(appDir.BundleDir(), validation.NewSchemaValidator())
+	if err != nil {
+		return err
+	}
+	issues, err := lintScopeConfig(opts.scopeConfigs, appDir, componentDefs)
+	if err != nil {
+		return err
+	}
+	writeScopeIssues(opts.Out, issues)
+	if len(issues) > 0 {
+		return fmt.Errorf("%w: %d", errScopeProblems, len(issues))
+	}
+	logger.Info("The scope config is valid.")
+	return nil
+}
+
+// lintScopeConfig merges the scope config layers and checks each of them against the component
+// definitions and the catalog groups of their framework.
+func lintScopeConfig(paths []string, appDir complytime.ApplicationDirectory, componentDefs []oscalTypes.ComponentDefinition) ([]complytime.ScopeIssue, error) {
+	layered, err := complytime.LoadLayeredScope(paths...)
+	if err != nil {
+		return nil, err
+	}
+	controlGroups := complytime.ControlGroups(layered.Scope.FrameworkID, appDir, validation.NewSchemaValidator(), componentDefs...)
+	return complytime.LintLayeredScope(layered, componentDefs, controlGroups)
+}
+
+// formatScopeIssue returns the issue prefixed with its scope config file and line.
+func formatScopeIssue(issue complytime.ScopeIssue) string {
+	if issue.Line == 0 {
+		return fmt.Sprintf("%s: %s", issue.File, issue)
+	}
+	return fmt.Sprintf("%s:%d: %s", issue.File, issue.Line, issue)
+}
+
+// writeScopeIssues writes one issue per line followed by a summary.
+func writeScopeIssues(w io.Writer, issues []complytime.ScopeIssue) {
+	for _, issue := range issues {
+		fmt.Fprintln(w, formatScopeIssue(issue))
+	}
+	if len(issues) > 0 {
+		fmt.Fprintf(w, "\n%d problem(s) found\n", len(issues))
+	}
+}
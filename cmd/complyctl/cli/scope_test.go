@@ -0,0 +1,30 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package cli
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/complytime/complyctl/internal/complytime"
+)
+
+func TestWriteScopeIssues(t *testing.T) {
+	var buf bytes.Buffer
+	writeScopeIssues(&buf, []complytime.ScopeIssue{
+		{File: "config.yml", Line: 7, Message: `rule "sshd_disable_rot_login" is not defined in the component definitions`, Suggestion: "sshd_disable_root_login"},
+		{File: "prod.yml", Message: "frameworkId must be set"},
+	})
+	expected := `config.yml:7: rule "sshd_disable_rot_login" is not defined in the component definitions (did you mean "sshd_disable_root_login"?)
+prod.yml: frameworkId must be set
+
+2 problem(s) found
+`
+	require.Equal(t, expected, buf.String())
+
+	buf.Reset()
+	writeScopeIssues(&buf, nil)
+	require.Empty(t, buf.String())
+}
@@ -0,0 +1,40 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package cli
+
+import (
+	"fmt"
+
+	"github.com/complytime/complyctl/cmd/complyctl/option"
+	"github.com/complytime/complyctl/internal/complytime"
+)
+
+// verifyContent verifies the signatures of the compliance content in the application directory.
+// Unverified content is refused unless the verification is skipped, in which case failures are
+// logged as warnings and the results are returned for the record.
+func verifyContent(appDir complytime.ApplicationDirectory, opts *option.Verification) ([]complytime.SignatureResult, error) {
+	verifier, err := complytime.NewSignatureVerifier(opts.TrustPolicy, complytime.DefaultTrustedKeysDir)
+	if err != nil {
+		if !opts.InsecureSkipVerify {
+			return nil, fmt.Errorf("%w: %v\n\nUse --insecure-skip-verify to use unsigned content.", complytime.ErrUnverifiedContent, err)
+		}
+		logger.Warn(fmt.Sprintf("Skipping signature verification of compliance content: %v", err))
+		return nil, nil
+	}
+	results, err := verifier.VerifyContent(appDir)
+	if err != nil {
+		return nil, err
+	}
+	for _, result := range results {
+		logger.Debug(fmt.Sprintf("Signature of %s: %s", result.File, result.Status), "digest", result.Digest, "key", result.Key)
+	}
+	if err := complytime.CheckSignatures(results); err != nil {
+		if !opts.InsecureSkipVerify {
+			return nil, fmt.Errorf("%w\n\nUse --insecure-skip-verify to use unsigned content.", err)
+		}
+		logger.Warn(fmt.Sprintf("Skipping signature verification of compliance content: %v", err))
+		return results, nil
+	}
+	logger.Debug(fmt.Sprintf("Verified the signatures of %d content file(s).", len(results)))
+	return results, nil
+}
@@ -0,0 +1,72 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package cli
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/oscal-compass/oscal-sdk-go/extensions"
+	"github.com/oscal-compass/oscal-sdk-go/validation"
+	"github.com/stretchr/testify/require"
+
+	"github.com/complytime/complyctl/cmd/complyctl/option"
+	"github.com/complytime/complyctl/internal/bundle"
+	"github.com/complytime/complyctl/internal/complytime"
+	"github.com/complytime/complyctl/internal/complytime/complytimetest"
+)
+
+func TestPlanInstalledSignedBundle(t *testing.T) {
+	appDir := complytimetest.NewApplicationDirectory(t)
+	publicKey, privateKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	// Trust the public key of the signing key in a trust policy
+	keysDir := t.TempDir()
+	der, err := x509.MarshalPKIXPublicKey(publicKey)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(filepath.Join(keysDir, "content-team.pub"), pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}), 0600))
+	trustPolicy := filepath.Join(keysDir, "trust-policy.yaml")
+	require.NoError(t, os.WriteFile(trustPolicy, []byte("keys:\n- name: content-team\n  path: content-team.pub\n"), 0600))
+
+	// Sign the files of a bundle laid out like the application directory
+	testDataDir := filepath.Join("..", "..", "..", "internal", "complytime", "testdata", "complytime")
+	bundleDir := t.TempDir()
+	for _, file := range []string{
+		filepath.Join("bundles", "example-component-definition.json"),
+		filepath.Join("controls", "sample-profile.json"),
+		filepath.Join("controls", "sample-catalog.json"),
+	} {
+		data, err := os.ReadFile(filepath.Join(testDataDir, file))
+		require.NoError(t, err)
+		bundleFile := filepath.Join(bundleDir, file)
+		require.NoError(t, os.MkdirAll(filepath.Dir(bundleFile), 0700))
+		require.NoError(t, os.WriteFile(bundleFile, data, 0600))
+		signature := base64.StdEncoding.EncodeToString(ed25519.Sign(privateKey, data))
+		require.NoError(t, os.WriteFile(bundleFile+complytime.SignatureSuffix, []byte(signature), 0600))
+	}
+	_, err = bundle.Install(appDir, bundleDir, "example", hclog.NewNullLogger())
+	require.NoError(t, err)
+
+	// The installed content is verified without skipping the verification
+	workspace := t.TempDir()
+	cmd := planCmd(&option.Common{Output: option.Output{Out: io.Discard}})
+	cmd.SetArgs([]string{"example", "--workspace", workspace, "--trust-policy", trustPolicy})
+	cmd.SetContext(context.Background())
+	require.NoError(t, cmd.Execute())
+
+	plan, err := complytime.ReadPlan(filepath.Join(workspace, assessmentPlanLocation), validation.NoopValidator{})
+	require.NoError(t, err)
+	verification, found := extensions.GetTrestleProp("Content_Verification", *plan.Metadata.Props)
+	require.True(t, found)
+	require.Equal(t, "enforced", verification.Value)
+}
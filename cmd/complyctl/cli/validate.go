@@ -0,0 +1,108 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+
+	"github.com/complytime/complyctl/cmd/complyctl/option"
+	"github.com/complytime/complyctl/internal/complytime"
+)
+
+// errValidationProblems is returned when problems are found in the validated artifacts.
+var errValidationProblems = errors.New("validation problems found")
+
+// validateOptions defines options for the "validate" subcommand
+type validateOptions struct {
+	*option.Common
+	complyTimeOpts *option.ComplyTime
+	scopeConfig    string
+}
+
+var validateExample = `
+# Validate the bundle and the artifacts in the default workspace.
+complyctl validate
+
+# Validate the artifacts in a custom workspace and a scope config.
+complyctl validate --workspace ./my-workspace --scope-config config.yml
+`
+
+// validateCmd creates a new cobra.Command for the "validate" subcommand
+func validateCmd(common *option.Common) *cobra.Command {
+	validateOpts := &validateOptions{
+		Common:         common,
+		complyTimeOpts: &option.ComplyTime{},
+	}
+	cmd := &cobra.Command{
+		Use:   "validate [flags]",
+		Short: "Validate the bundle and workspace artifacts.",
+		Long: "Validate the component definitions in the bundle, the profiles and catalogs they reference, " +
+			"and the assessment plan, assessment results, and scope config in the workspace. " +
+			"All problems are reported at once with their file and JSON path locations.",
+		Example:      validateExample,
+		SilenceUsage: true,
+		Args:         cobra.NoArgs,
+		RunE: func(_ *cobra.Command, _ []string) error {
+			return runValidate(validateOpts)
+		},
+	}
+	cmd.Flags().StringVarP(&validateOpts.scopeConfig, "scope-config", "s", "", "path to a config.yml to validate")
+	validateOpts.complyTimeOpts.BindFlags(cmd.Flags())
+	return cmd
+}
+
+func runValidate(opts *validateOptions) error {
+	appDir, err := complytime.NewApplicationDirectory(true, logger)
+	if err != nil {
+		return err
+	}
+	logger.Debug(fmt.Sprintf("Using application directory: %s", appDir.AppDir()))
+
+	validator := complytime.NewArtifactValidator(appDir)
+	validator.ValidateBundle()
+	validateWorkspace(validator, opts)
+
+	problems := validator.Problems()
+	writeProblems(opts.Out, problems)
+	if len(problems) > 0 {
+		return fmt.Errorf("%w: %d", errValidationProblems, len(problems))
+	}
+	logger.Info("All artifacts are valid.")
+	return nil
+}
+
+// validateWorkspace validates the artifacts found in the workspace and the scope config, if set.
+// Missing workspace artifacts are skipped since they are only generated by the plan and scan commands.
+func validateWorkspace(validator *complytime.ArtifactValidator, opts *validateOptions) {
+	apPath := filepath.Clean(filepath.Join(opts.complyTimeOpts.UserWorkspace, assessmentPlanLocation))
+	if _, err := os.Stat(apPath); err == nil {
+		validator.ValidatePlan(apPath)
+	} else {
+		logger.Debug(fmt.Sprintf("Skipping assessment plan validation: %v", err))
+	}
+	arPath := filepath.Clean(filepath.Join(opts.complyTimeOpts.UserWorkspace, assessmentResultsLocationJson))
+	if _, err := os.Stat(arPath); err == nil {
+		validator.ValidateResults(arPath)
+	} else {
+		logger.Debug(fmt.Sprintf("Skipping assessment results validation: %v", err))
+	}
+	if opts.scopeConfig != "" {
+		validator.ValidateScopeConfig(filepath.Clean(opts.scopeConfig))
+	}
+}
+
+// writeProblems writes one problem per line followed by a summary.
+func writeProblems(w io.Writer, problems []complytime.Problem) {
+	for _, problem := range problems {
+		fmt.Fprintln(w, problem.String())
+	}
+	if len(problems) > 0 {
+		fmt.Fprintf(w, "\n%d problem(s) found\n", len(problems))
+	}
+}
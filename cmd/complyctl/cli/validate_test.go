@@ -0,0 +1,65 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package cli
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/complytime/complyctl/cmd/complyctl/option"
+	"github.com/complytime/complyctl/internal/complytime"
+)
+
+func TestValidateWorkspace(t *testing.T) {
+	tests := []struct {
+		name      string
+		workspace string
+		want      []complytime.Problem
+	}{
+		{
+			name:      "Valid/EmptyWorkspace",
+			workspace: "doesnotexist",
+		},
+		{
+			name:      "Invalid/PlanWithoutFramework",
+			workspace: "testdata",
+			want: []complytime.Problem{
+				{
+					File:    "testdata/assessment-plan.json",
+					Path:    "/assessment-plan/metadata",
+					Message: "missing Framework_Short_Name property",
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := &validateOptions{
+				complyTimeOpts: &option.ComplyTime{UserWorkspace: tt.workspace},
+			}
+			validator := complytime.NewArtifactValidator(complytime.ApplicationDirectory{})
+			validateWorkspace(validator, opts)
+			require.Equal(t, tt.want, validator.Problems())
+		})
+	}
+}
+
+func TestWriteProblems(t *testing.T) {
+	var buf bytes.Buffer
+	writeProblems(&buf, []complytime.Problem{
+		{File: "config.yml", Message: "invalid assessment scope"},
+		{File: "assessment-plan.json", Path: "/assessment-plan/metadata", Message: "missing Framework_Short_Name property"},
+	})
+	expected := `config.yml: invalid assessment scope
+assessment-plan.json#/assessment-plan/metadata: missing Framework_Short_Name property
+
+2 problem(s) found
+`
+	require.Equal(t, expected, buf.String())
+
+	buf.Reset()
+	writeProblems(&buf, nil)
+	require.Empty(t, buf.String())
+}
@@ -7,16 +7,17 @@ import (
 	"fmt"
 	"os"
 	"os/signal"
+	"syscall"
 
 	"github.com/complytime/complyctl/cmd/complyctl/cli"
 )
 
 func main() {
-	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt)
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
 	defer cancel()
 	complyctl := cli.New()
 	if err := complyctl.ExecuteContext(ctx); err != nil {
 		cli.Error(fmt.Sprintf("error running complyctl: %v", err))
-		os.Exit(1)
+		os.Exit(cli.ExitCode(err))
 	}
 }
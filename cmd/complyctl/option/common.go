@@ -54,3 +54,36 @@ func (o *ComplyTime) ToPluginOptions() complytime.PluginOptions {
 	pluginOptions.Profile = o.FrameworkID
 	return pluginOptions
 }
+
+// Verification options configure the signature verification of compliance content.
+type Verification struct {
+	// TrustPolicy is the path of the trust policy listing the trusted public keys.
+	TrustPolicy string
+	// InsecureSkipVerify allows unsigned or tampered content to be used.
+	InsecureSkipVerify bool
+}
+
+// BindFlags populate Verification options from user-specified flags.
+func (o *Verification) BindFlags(fs *pflag.FlagSet) {
+	fs.StringVar(&o.TrustPolicy, "trust-policy", complytime.DefaultTrustPolicyPath, "trust policy listing the public keys trusted to sign compliance content")
+	fs.BoolVar(&o.InsecureSkipVerify, "insecure-skip-verify", false, "use compliance content without a valid signature")
+}
+
+// Selection options narrow an assessment to some rules and controls of the assessment plan.
+type Selection struct {
+	// Rules are the rule IDs or patterns to assess.
+	Rules []string
+	// Controls are the control IDs or patterns to assess.
+	Controls []string
+}
+
+// BindFlags populate Selection options from user-specified flags.
+func (o *Selection) BindFlags(fs *pflag.FlagSet) {
+	fs.StringSliceVar(&o.Rules, "rule", nil, "only assess the rules with these IDs or patterns")
+	fs.StringSliceVar(&o.Controls, "control", nil, "only assess the rules of the controls with these IDs or patterns")
+}
+
+// ToSelection returns the complytime Selection of the options.
+func (o *Selection) ToSelection() complytime.Selection {
+	return complytime.Selection{Rules: o.Rules, Controls: o.Controls}
+}
@@ -0,0 +1,44 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package config
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+
+	"github.com/complytime/complyctl/pkg/doctor"
+)
+
+// Diagnose returns the checks of the OpenSCAP plugin prerequisites reported by "complyctl doctor".
+func Diagnose() []doctor.Check {
+	return []doctor.Check{
+		oscapCheck(exec.LookPath),
+		datastreamCheck(),
+	}
+}
+
+// oscapCheck checks that the oscap command is installed.
+func oscapCheck(lookPath func(string) (string, error)) doctor.Check {
+	name := "oscap installed"
+	path, err := lookPath("oscap")
+	if err != nil {
+		return doctor.Fail(name, err.Error(), "Install the openscap-scanner package.")
+	}
+	return doctor.Pass(name, fmt.Sprintf("found at %s", path))
+}
+
+// datastreamCheck checks that a datastream matches the system described in SystemInfoFile.
+func datastreamCheck() doctor.Check {
+	name := "datastream available"
+	hint := "Install the scap-security-guide package for this system or set the datastream option " +
+		"in /etc/complytime/config.d/c2p-openscap-manifest.json."
+	if _, err := os.Stat(DatastreamsDir); err != nil {
+		return doctor.Fail(name, err.Error(), hint)
+	}
+	datastream, err := findMatchingDatastream()
+	if err != nil {
+		return doctor.Fail(name, err.Error(), hint)
+	}
+	return doctor.Pass(name, fmt.Sprintf("%s matches %s", datastream, SystemInfoFile))
+}
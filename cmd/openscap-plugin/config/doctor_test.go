@@ -0,0 +1,36 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package config
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/complytime/complyctl/pkg/doctor"
+)
+
+func TestOscapCheck(t *testing.T) {
+	tests := []struct {
+		name     string
+		lookPath func(string) (string, error)
+		want     doctor.Check
+	}{
+		{
+			name:     "Valid/Installed",
+			lookPath: func(string) (string, error) { return "/usr/bin/oscap", nil },
+			want:     doctor.Pass("oscap installed", "found at /usr/bin/oscap"),
+		},
+		{
+			name:     "Invalid/NotInstalled",
+			lookPath: func(string) (string, error) { return "", errors.New("executable file not found in $PATH") },
+			want:     doctor.Fail("oscap installed", "executable file not found in $PATH", "Install the openscap-scanner package."),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, oscapCheck(tt.lookPath))
+		})
+	}
+}
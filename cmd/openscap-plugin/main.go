@@ -7,7 +7,9 @@ import (
 
 	"github.com/hashicorp/go-hclog"
 
+	"github.com/complytime/complyctl/cmd/openscap-plugin/config"
 	"github.com/complytime/complyctl/cmd/openscap-plugin/server"
+	"github.com/complytime/complyctl/pkg/doctor"
 
 	hplugin "github.com/hashicorp/go-plugin"
 	"github.com/oscal-compass/compliance-to-policy-go/v2/plugin"
@@ -26,6 +28,14 @@ func init() {
 }
 
 func main() {
+	// Report the plugin prerequisites to "complyctl doctor" instead of serving the plugin.
+	if len(os.Args) > 1 && os.Args[1] == doctor.Command {
+		if err := doctor.Write(os.Stdout, config.Diagnose()); err != nil {
+			logger.Error("Failed to write checks", "err", err)
+			os.Exit(1)
+		}
+		return
+	}
 	hclog.Default().Info("Starting OpenSCAP plugin")
 	openSCAPPlugin := server.New()
 	pluginByType := map[string]hplugin.Plugin{
@@ -3,24 +3,35 @@
 package oscap
 
 import (
+	"context"
 	"fmt"
 	"os/exec"
 	"path/filepath"
+	"syscall"
 
 	"github.com/complytime/complyctl/cmd/openscap-plugin/config"
 	"github.com/hashicorp/go-hclog"
 )
 
-func executeCommand(command []string) ([]byte, error) {
+func executeCommand(ctx context.Context, command []string) ([]byte, error) {
 	cmdPath, err := exec.LookPath(command[0])
 	if err != nil {
 		return nil, fmt.Errorf("command not found: %s: %w", command[0], err)
 	}
 
 	hclog.Default().Debug("Executing command", "command", command)
-	cmd := exec.Command(cmdPath, command[1:]...)
+	cmd := exec.CommandContext(ctx, cmdPath, command[1:]...)
+	// Run the command in its own process group, so the processes it starts are
+	// also killed when the context is canceled.
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+	cmd.Cancel = func() error {
+		return syscall.Kill(-cmd.Process.Pid, syscall.SIGKILL)
+	}
 
 	output, err := cmd.CombinedOutput()
+	if ctx.Err() != nil {
+		return nil, fmt.Errorf("%s canceled: %w", command[0], ctx.Err())
+	}
 	if err != nil {
 		if err.Error() == "exit status 1" {
 			return output, fmt.Errorf("oscap error during evaluation: %w", err)
@@ -54,10 +65,10 @@ func constructScanCommand(openscapFiles map[string]string, profile string) []str
 	return cmd
 }
 
-func OscapScan(openscapFiles map[string]string, profile string) ([]byte, error) {
+func OscapScan(ctx context.Context, openscapFiles map[string]string, profile string) ([]byte, error) {
 	command := constructScanCommand(openscapFiles, profile)
 
-	return executeCommand(command)
+	return executeCommand(ctx, command)
 }
 
 func constructGenerateFixCommand(fixType, output, profile, tailoringFile, datastream string) []string {
@@ -76,7 +87,7 @@ func constructGenerateFixCommand(fixType, output, profile, tailoringFile, datast
 	return cmd
 }
 
-func OscapGenerateFix(pluginDir, profile, policyFile, datastream string) error {
+func OscapGenerateFix(ctx context.Context, pluginDir, profile, policyFile, datastream string) error {
 	fixTypes := map[string]string{
 		"bash":      "remediation-script.sh",
 		"ansible":   "remediation-playbook.yml",
@@ -87,7 +98,7 @@ func OscapGenerateFix(pluginDir, profile, policyFile, datastream string) error {
 		outputPath := filepath.Join(pluginDir, config.RemediationDir, outputFile)
 		hclog.Default().Debug("Generating remedation file %s", outputPath)
 		command := constructGenerateFixCommand(fixType, outputPath, profile, policyFile, datastream)
-		_, err := executeCommand(command)
+		_, err := executeCommand(ctx, command)
 		if err != nil {
 			return err
 		}
@@ -3,8 +3,11 @@
 package oscap
 
 import (
+	"context"
+	"errors"
 	"reflect"
 	"testing"
+	"time"
 )
 
 func TestConstructScanCommand(t *testing.T) {
@@ -93,3 +96,18 @@ func TestConstructGenerateFixCommand(t *testing.T) {
 		})
 	}
 }
+
+func TestExecuteCommandCanceled(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	// The background sleep keeps the output open until the whole process group is killed.
+	start := time.Now()
+	_, err := executeCommand(ctx, []string{"sh", "-c", "sleep 30 & sleep 30"})
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("executeCommand() error = %v, expected %v", err, context.DeadlineExceeded)
+	}
+	if elapsed := time.Since(start); elapsed > 10*time.Second {
+		t.Errorf("executeCommand() returned after %s, expected the process group to be killed", elapsed)
+	}
+}
@@ -47,7 +47,7 @@ func (s PluginServer) Configure(_ context.Context, configMap map[string]string)
 	return s.Config.LoadSettings(configMap)
 }
 
-func (s PluginServer) Generate(_ context.Context, policy policy.Policy) error {
+func (s PluginServer) Generate(ctx context.Context, policy policy.Policy) error {
 	hclog.Default().Info("Generating a tailoring file")
 	tailoringXML, err := xccdf.PolicyToXML(policy, s.Config)
 	if err != nil {
@@ -67,18 +67,18 @@ func (s PluginServer) Generate(_ context.Context, policy policy.Policy) error {
 	// Generate remedation files
 	hclog.Default().Info(("Generating remediation files"))
 	pluginDir := filepath.Join(s.Config.Files.Workspace, config.PluginDir)
-	err = oscap.OscapGenerateFix(pluginDir, s.Config.Parameters.Profile, s.Config.Files.Policy, s.Config.Files.Datastream)
+	err = oscap.OscapGenerateFix(ctx, pluginDir, s.Config.Parameters.Profile, s.Config.Files.Policy, s.Config.Files.Datastream)
 	if err != nil {
 		return err
 	}
 	return nil
 }
 
-func (s PluginServer) GetResults(_ context.Context, oscalPolicy policy.Policy) (policy.PVPResult, error) {
+func (s PluginServer) GetResults(ctx context.Context, oscalPolicy policy.Policy) (policy.PVPResult, error) {
 	pvpResults := policy.PVPResult{}
 	policyChecks := newChecks()
 
-	_, err := scan.ScanSystem(s.Config, s.Config.Parameters.Profile)
+	_, err := scan.ScanSystem(ctx, s.Config, s.Config.Parameters.Profile)
 	if err != nil {
 		return policy.PVPResult{}, err
 	}
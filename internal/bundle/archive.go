@@ -0,0 +1,125 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package bundle
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// maxArchiveFileSize limits the size of a single file extracted from a bundle archive.
+const maxArchiveFileSize = 100 << 20
+
+// isArchive reports whether the path has a supported archive extension.
+func isArchive(path string) bool {
+	for _, suffix := range []string{".tar", ".tar.gz", ".tgz", ".zip"} {
+		if strings.HasSuffix(path, suffix) {
+			return true
+		}
+	}
+	return false
+}
+
+// extractArchive extracts the tar, gzip compressed tar, or zip archive into the destination directory.
+func extractArchive(archivePath, destination string) error {
+	if strings.HasSuffix(archivePath, ".zip") {
+		return extractZip(archivePath, destination)
+	}
+	file, err := os.Open(filepath.Clean(archivePath))
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	var reader io.Reader = file
+	if strings.HasSuffix(archivePath, ".gz") || strings.HasSuffix(archivePath, ".tgz") {
+		gzipReader, err := gzip.NewReader(file)
+		if err != nil {
+			return fmt.Errorf("failed to read archive %s: %w", archivePath, err)
+		}
+		defer gzipReader.Close()
+		reader = gzipReader
+	}
+
+	return extractTar(reader, archivePath, destination)
+}
+
+// extractTar extracts the regular files of the tar stream into the destination directory.
+func extractTar(reader io.Reader, archiveName, destination string) error {
+	tarReader := tar.NewReader(reader)
+	for {
+		header, err := tarReader.Next()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read archive %s: %w", archiveName, err)
+		}
+		switch header.Typeflag {
+		case tar.TypeDir:
+			continue
+		case tar.TypeReg:
+			if err := extractFile(destination, header.Name, tarReader); err != nil {
+				return err
+			}
+		default:
+			// Links and special files are not part of a bundle
+			continue
+		}
+	}
+}
+
+func extractZip(archivePath, destination string) error {
+	zipReader, err := zip.OpenReader(filepath.Clean(archivePath))
+	if err != nil {
+		return fmt.Errorf("failed to read archive %s: %w", archivePath, err)
+	}
+	defer zipReader.Close()
+	for _, file := range zipReader.File {
+		if !file.Mode().IsRegular() {
+			continue
+		}
+		reader, err := file.Open()
+		if err != nil {
+			return err
+		}
+		err = extractFile(destination, file.Name, reader)
+		reader.Close()
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// extractFile writes the archive entry under the destination directory. Entries
+// escaping the destination directory are rejected.
+func extractFile(destination, name string, reader io.Reader) error {
+	target := filepath.Join(destination, filepath.Clean(name))
+	if !strings.HasPrefix(target, filepath.Clean(destination)+string(os.PathSeparator)) {
+		return fmt.Errorf("archive entry %s is outside of the archive root", name)
+	}
+	if err := os.MkdirAll(filepath.Dir(target), 0700); err != nil {
+		return err
+	}
+	file, err := os.OpenFile(target, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0600)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	written, err := io.Copy(file, io.LimitReader(reader, maxArchiveFileSize+1))
+	if err != nil {
+		return err
+	}
+	if written > maxArchiveFileSize {
+		return fmt.Errorf("archive entry %s exceeds the maximum size of %d bytes", name, maxArchiveFileSize)
+	}
+	return nil
+}
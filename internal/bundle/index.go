@@ -0,0 +1,149 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package bundle
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/complytime/complyctl/internal/complytime"
+)
+
+// indexFileName is the name of the installed bundle index in the application directory.
+const indexFileName = "installed-bundles.json"
+
+// ComponentDefinition describes an installed component definition.
+type ComponentDefinition struct {
+	// File is the path of the component definition relative to the application directory.
+	File    string `json:"file"`
+	Title   string `json:"title"`
+	Version string `json:"version"`
+}
+
+// Entry is an installed bundle recorded in the index.
+type Entry struct {
+	Name string `json:"name"`
+	// Version is the version of the component definitions in the bundle.
+	// It is empty when the component definitions have different versions.
+	Version string `json:"version,omitempty"`
+	// Source is the location the bundle was installed from.
+	Source string `json:"source"`
+	// Digest is the manifest digest of bundles installed from an OCI image layout or registry.
+	Digest      string    `json:"digest,omitempty"`
+	InstalledAt time.Time `json:"installedAt"`
+	// Frameworks are the framework short names provided by the bundle.
+	Frameworks           []string              `json:"frameworks"`
+	ComponentDefinitions []ComponentDefinition `json:"componentDefinitions"`
+	// Files are all installed files relative to the application directory.
+	Files []string `json:"files"`
+}
+
+// Index is the set of bundles installed in an application directory.
+type Index struct {
+	Bundles []Entry `json:"bundles"`
+}
+
+// IndexPath returns the location of the installed bundle index.
+func IndexPath(appDir complytime.ApplicationDirectory) string {
+	return filepath.Join(appDir.AppDir(), indexFileName)
+}
+
+// LoadIndex reads the installed bundle index of the application directory.
+// An empty index is returned when no bundle was installed.
+func LoadIndex(appDir complytime.ApplicationDirectory) (*Index, error) {
+	indexPath := IndexPath(appDir)
+	data, err := os.ReadFile(filepath.Clean(indexPath))
+	if errors.Is(err, os.ErrNotExist) {
+		return &Index{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var index Index
+	if err := json.Unmarshal(data, &index); err != nil {
+		return nil, fmt.Errorf("failed to parse bundle index %s: %w", indexPath, err)
+	}
+	return &index, nil
+}
+
+// Save writes the index to the application directory.
+func (i *Index) Save(appDir complytime.ApplicationDirectory) error {
+	sort.Slice(i.Bundles, func(a, b int) bool {
+		return i.Bundles[a].Name < i.Bundles[b].Name
+	})
+	data, err := json.MarshalIndent(i, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(IndexPath(appDir), data, 0600)
+}
+
+// Find returns the installed bundle with the given name.
+func (i *Index) Find(name string) (Entry, bool) {
+	for _, entry := range i.Bundles {
+		if entry.Name == name {
+			return entry, true
+		}
+	}
+	return Entry{}, false
+}
+
+// put adds the entry to the index, replacing an installed bundle with the same name.
+func (i *Index) put(entry Entry) {
+	i.delete(entry.Name)
+	i.Bundles = append(i.Bundles, entry)
+}
+
+func (i *Index) delete(name string) {
+	var bundles []Entry
+	for _, entry := range i.Bundles {
+		if entry.Name != name {
+			bundles = append(bundles, entry)
+		}
+	}
+	i.Bundles = bundles
+}
+
+// owner returns the name of the bundle other than the excluded one that installed the given file.
+func (i *Index) owner(file, exclude string) (string, bool) {
+	for _, entry := range i.Bundles {
+		if entry.Name == exclude {
+			continue
+		}
+		for _, installed := range entry.Files {
+			if installed == file {
+				return entry.Name, true
+			}
+		}
+	}
+	return "", false
+}
+
+// OrphanedFrameworks returns the frameworks of the named bundle that no other installed bundle provides.
+func (i *Index) OrphanedFrameworks(name string) []string {
+	entry, found := i.Find(name)
+	if !found {
+		return nil
+	}
+	provided := make(map[string]struct{})
+	for _, other := range i.Bundles {
+		if other.Name == name {
+			continue
+		}
+		for _, framework := range other.Frameworks {
+			provided[framework] = struct{}{}
+		}
+	}
+	var orphaned []string
+	for _, framework := range entry.Frameworks {
+		if _, ok := provided[framework]; !ok {
+			orphaned = append(orphaned, framework)
+		}
+	}
+	return orphaned
+}
@@ -0,0 +1,455 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package bundle
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	oscalTypes "github.com/defenseunicorns/go-oscal/src/types/oscal-1-1-3"
+	"github.com/hashicorp/go-hclog"
+	"github.com/oscal-compass/oscal-sdk-go/extensions"
+	"github.com/oscal-compass/oscal-sdk-go/validation"
+
+	"github.com/complytime/complyctl/internal/complytime"
+)
+
+// ErrNotInstalled is returned when a bundle is not found in the index.
+var ErrNotInstalled = errors.New("bundle is not installed")
+
+// Install installs the bundle in the source directory or archive into the application directory.
+//
+// The component definitions are validated, and the profiles and catalogs they reference are
+// resolved relative to the bundle root or the referencing file and copied to the control directory.
+// The hrefs are rewritten to the file://controls/ locations read by complytime. Files whose hrefs
+// already are these locations are installed as is with their signatures. When the name is empty,
+// the bundle is named after the source. Installing a bundle with the name of an installed bundle
+// replaces it.
+func Install(appDir complytime.ApplicationDirectory, source, name string, logger hclog.Logger) (Entry, error) {
+	info, err := os.Stat(source)
+	if err != nil {
+		return Entry{}, err
+	}
+	root := source
+	if !info.IsDir() {
+		if !isArchive(source) {
+			return Entry{}, fmt.Errorf("unsupported bundle source %s: must be a directory or a .tar, .tar.gz, .tgz, or .zip archive", source)
+		}
+		tmpDir, err := os.MkdirTemp("", "complytime-bundle-")
+		if err != nil {
+			return Entry{}, err
+		}
+		defer os.RemoveAll(tmpDir)
+		logger.Debug(fmt.Sprintf("Extracting bundle archive %s to %s", source, tmpDir))
+		if err := extractArchive(source, tmpDir); err != nil {
+			return Entry{}, err
+		}
+		root = tmpDir
+	}
+	if name == "" {
+		name = bundleName(source)
+	}
+	return installDir(appDir, root, Entry{Name: name, Source: source}, logger)
+}
+
+// installDir installs the bundle in the root directory and records it in the index
+// with the name, source, and digest of the given entry.
+func installDir(appDir complytime.ApplicationDirectory, root string, entry Entry, logger hclog.Logger) (Entry, error) {
+	index, err := LoadIndex(appDir)
+	if err != nil {
+		return Entry{}, err
+	}
+
+	compDefPaths, err := findComponentDefinitionFiles(root)
+	if err != nil {
+		return Entry{}, err
+	}
+	if len(compDefPaths) == 0 {
+		return Entry{}, fmt.Errorf("bundle %s: %w", entry.Source, complytime.ErrNoComponentDefinitionsFound)
+	}
+
+	inst := &installer{
+		root:    root,
+		files:   make(map[string][]byte),
+		sources: make(map[string]string),
+		logger:  logger,
+	}
+	entry.InstalledAt = time.Now().UTC()
+	frameworks := make(map[string]struct{})
+	versions := make(map[string]struct{})
+	for _, compDefPath := range compDefPaths {
+		compDef, err := complytime.ReadComponentDefinition(compDefPath, validation.NewSchemaValidator())
+		if err != nil {
+			return Entry{}, fmt.Errorf("invalid component definition %s: %w", compDefPath, err)
+		}
+		rewritten := false
+		if compDef.Components != nil {
+			for _, component := range *compDef.Components {
+				if component.ControlImplementations == nil {
+					continue
+				}
+				implementations := *component.ControlImplementations
+				for i := range implementations {
+					href, err := inst.addControlSource(implementations[i].Source, filepath.Dir(compDefPath))
+					if err != nil {
+						return Entry{}, fmt.Errorf("component definition %s: %w", compDefPath, err)
+					}
+					rewritten = rewritten || href != implementations[i].Source
+					implementations[i].Source = href
+					if implementations[i].Props == nil {
+						continue
+					}
+					if frameworkProp, found := extensions.GetTrestleProp(extensions.FrameworkProp, *implementations[i].Props); found {
+						frameworks[frameworkProp.Value] = struct{}{}
+					}
+				}
+			}
+		}
+		destination := filepath.Join(complytime.BundlesDir, filepath.Base(compDefPath))
+		if err := inst.addFile(compDefPath, destination, rewritten, oscalTypes.OscalModels{ComponentDefinition: compDef}); err != nil {
+			return Entry{}, err
+		}
+		entry.ComponentDefinitions = append(entry.ComponentDefinitions, ComponentDefinition{
+			File:    destination,
+			Title:   compDef.Metadata.Title,
+			Version: compDef.Metadata.Version,
+		})
+		versions[compDef.Metadata.Version] = struct{}{}
+	}
+	if len(versions) == 1 {
+		entry.Version = entry.ComponentDefinitions[0].Version
+	}
+	for framework := range frameworks {
+		entry.Frameworks = append(entry.Frameworks, framework)
+	}
+	sort.Strings(entry.Frameworks)
+
+	previous, _ := index.Find(entry.Name)
+	if err := inst.checkConflicts(appDir, index, previous); err != nil {
+		return Entry{}, err
+	}
+	for file, data := range inst.files {
+		destination := filepath.Join(appDir.AppDir(), file)
+		if err := os.MkdirAll(filepath.Dir(destination), 0700); err != nil {
+			return Entry{}, err
+		}
+		logger.Debug(fmt.Sprintf("Installing %s", destination))
+		if err := os.WriteFile(destination, data, 0600); err != nil {
+			return Entry{}, err
+		}
+		entry.Files = append(entry.Files, file)
+	}
+	sort.Strings(entry.Files)
+
+	// Remove the files of the replaced bundle that are no longer part of it
+	for _, file := range previous.Files {
+		if _, ok := inst.files[file]; ok {
+			continue
+		}
+		if err := removeFile(appDir, index, entry.Name, file); err != nil {
+			return Entry{}, err
+		}
+	}
+
+	index.put(entry)
+	if err := index.Save(appDir); err != nil {
+		return Entry{}, err
+	}
+	return entry, nil
+}
+
+// Remove deletes the files installed by the named bundle and removes it from the index.
+// Files also installed by other bundles are kept.
+func Remove(appDir complytime.ApplicationDirectory, name string) (Entry, error) {
+	index, err := LoadIndex(appDir)
+	if err != nil {
+		return Entry{}, err
+	}
+	entry, found := index.Find(name)
+	if !found {
+		return Entry{}, fmt.Errorf("%w: %s", ErrNotInstalled, name)
+	}
+	for _, file := range entry.Files {
+		if err := removeFile(appDir, index, name, file); err != nil {
+			return Entry{}, err
+		}
+	}
+	index.delete(name)
+	return entry, index.Save(appDir)
+}
+
+// removeFile deletes the installed file unless another bundle installed it.
+func removeFile(appDir complytime.ApplicationDirectory, index *Index, name, file string) error {
+	if _, shared := index.owner(file, name); shared {
+		return nil
+	}
+	err := os.Remove(filepath.Join(appDir.AppDir(), file))
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+// installer collects the files of a bundle before they are written to the application directory.
+type installer struct {
+	root string
+	// files are the contents to install by path relative to the application directory.
+	files map[string][]byte
+	// sources are the rewritten hrefs of the resolved control sources by source path.
+	sources map[string]string
+	logger  hclog.Logger
+}
+
+func (i *installer) add(file string, data []byte) error {
+	if existing, ok := i.files[file]; ok && !bytes.Equal(existing, data) {
+		return fmt.Errorf("bundle contains different files installed as %s", file)
+	}
+	i.files[file] = data
+	return nil
+}
+
+// addFile adds the bundle file at the source path to the installed files. A file whose hrefs were
+// rewritten is installed as the given model, otherwise it is installed as is with its signatures.
+func (i *installer) addFile(sourcePath, destination string, rewritten bool, model oscalTypes.OscalModels) error {
+	if rewritten {
+		i.warnUnsignedInstall(sourcePath)
+		data, err := json.MarshalIndent(model, "", " ")
+		if err != nil {
+			return err
+		}
+		return i.add(destination, data)
+	}
+	data, err := os.ReadFile(filepath.Clean(sourcePath))
+	if err != nil {
+		return err
+	}
+	if err := i.add(destination, data); err != nil {
+		return err
+	}
+	return i.addSignatures(sourcePath, destination)
+}
+
+// addSignatures adds the detached signature and sigstore bundle of the bundle file, if any, next to the
+// installed file.
+func (i *installer) addSignatures(sourcePath, destination string) error {
+	root, err := filepath.Abs(i.root)
+	if err != nil {
+		return err
+	}
+	for _, suffix := range []string{complytime.SignatureSuffix, complytime.SigstoreBundleSuffix} {
+		signaturePath, err := filepath.Abs(sourcePath + suffix)
+		if err != nil {
+			return err
+		}
+		resolved, err := resolveInRoot(root, signaturePath)
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		if errors.Is(err, errOutsideRoot) {
+			return fmt.Errorf("signature %s is outside of the bundle root", signaturePath)
+		}
+		if err != nil {
+			return err
+		}
+		data, err := os.ReadFile(filepath.Clean(resolved))
+		if err != nil {
+			return err
+		}
+		if err := i.add(destination+suffix, data); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// warnUnsignedInstall logs that the signatures of the bundle file are not installed because its hrefs
+// were rewritten, so the installed file no longer matches them.
+func (i *installer) warnUnsignedInstall(sourcePath string) {
+	for _, suffix := range []string{complytime.SignatureSuffix, complytime.SigstoreBundleSuffix} {
+		if _, err := os.Stat(sourcePath + suffix); err == nil {
+			i.logger.Warn(fmt.Sprintf("The signature %s is not installed because the hrefs of %s are rewritten to file://%s/ locations. "+
+				"Sign the file with these hrefs to install it with its signature.", sourcePath+suffix, sourcePath, complytime.ControlsDir))
+		}
+	}
+}
+
+// addControlSource resolves the profile or catalog at the given href, adds it and the
+// control sources it imports to the installed files, and returns the rewritten href.
+func (i *installer) addControlSource(href, baseDir string) (string, error) {
+	sourcePath, err := i.resolve(href, baseDir)
+	if err != nil {
+		return "", err
+	}
+	if installedHref, ok := i.sources[sourcePath]; ok {
+		return installedHref, nil
+	}
+	destination := filepath.Join(complytime.ControlsDir, filepath.Base(sourcePath))
+	installedHref := "file://" + filepath.ToSlash(destination)
+	i.sources[sourcePath] = installedHref
+
+	data, err := os.ReadFile(filepath.Clean(sourcePath))
+	if err != nil {
+		return "", err
+	}
+	var oscalModels oscalTypes.OscalModels
+	if err := json.Unmarshal(data, &oscalModels); err != nil {
+		return "", fmt.Errorf("failed to parse control source %s: %w", sourcePath, err)
+	}
+	rewritten := false
+	switch {
+	case oscalModels.Profile != nil:
+		profile := oscalModels.Profile
+		for j := range profile.Imports {
+			// Imports of back-matter resources are kept as is
+			if strings.HasPrefix(profile.Imports[j].Href, "#") {
+				continue
+			}
+			importedHref, err := i.addControlSource(profile.Imports[j].Href, filepath.Dir(sourcePath))
+			if err != nil {
+				return "", fmt.Errorf("profile %s: %w", sourcePath, err)
+			}
+			rewritten = rewritten || importedHref != profile.Imports[j].Href
+			profile.Imports[j].Href = importedHref
+		}
+	case oscalModels.Catalog != nil:
+	default:
+		return "", fmt.Errorf("control source %s is not a profile or catalog", sourcePath)
+	}
+	return installedHref, i.addFile(sourcePath, destination, rewritten, oscalModels)
+}
+
+// resolve returns the path of the file referenced by the href. Relative paths are resolved
+// against the bundle root first, then against the directory of the referencing file. Absolute
+// paths and paths outside of the bundle root are rejected, so a bundle cannot install host files.
+func (i *installer) resolve(href, baseDir string) (string, error) {
+	path := href
+	if uri, err := url.ParseRequestURI(href); err == nil {
+		if uri.Scheme != "" && uri.Scheme != "file" {
+			return "", fmt.Errorf("unsupported href %s: only local files are supported", href)
+		}
+		path = uri.Host + uri.Path
+	}
+	if filepath.IsAbs(path) {
+		return "", fmt.Errorf("unsupported href %s: only paths relative to the bundle are supported", href)
+	}
+	root, err := filepath.Abs(i.root)
+	if err != nil {
+		return "", err
+	}
+	for _, candidate := range []string{filepath.Join(i.root, path), filepath.Join(baseDir, path)} {
+		candidate, err := filepath.Abs(candidate)
+		if err != nil {
+			return "", err
+		}
+		if !isInside(root, candidate) {
+			return "", fmt.Errorf("control source %s is outside of the bundle root", href)
+		}
+		// A symbolic link in the bundle must not point outside of it
+		resolved, err := resolveInRoot(root, candidate)
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		if errors.Is(err, errOutsideRoot) {
+			return "", fmt.Errorf("control source %s is outside of the bundle root", href)
+		}
+		if err != nil {
+			return "", err
+		}
+		if info, err := os.Stat(resolved); err == nil && info.Mode().IsRegular() {
+			return resolved, nil
+		}
+	}
+	return "", fmt.Errorf("control source %s not found in bundle", href)
+}
+
+// errOutsideRoot is returned when a path of the bundle resolves outside of the bundle root.
+var errOutsideRoot = errors.New("path is outside of the bundle root")
+
+// resolveInRoot returns the path with its symbolic links evaluated. It returns errOutsideRoot
+// if the evaluated path is not under the evaluated bundle root.
+func resolveInRoot(root, path string) (string, error) {
+	resolvedRoot, err := filepath.EvalSymlinks(root)
+	if err != nil {
+		return "", err
+	}
+	resolved, err := filepath.EvalSymlinks(path)
+	if err != nil {
+		return "", err
+	}
+	if !isInside(resolvedRoot, resolved) {
+		return "", errOutsideRoot
+	}
+	return resolved, nil
+}
+
+// isInside returns true if the absolute path is under the absolute root directory.
+func isInside(root, path string) bool {
+	return strings.HasPrefix(path, root+string(os.PathSeparator))
+}
+
+// checkConflicts ensures that installing the files does not overwrite files installed
+// by other bundles or files that were not installed by a bundle.
+func (i *installer) checkConflicts(appDir complytime.ApplicationDirectory, index *Index, previous Entry) error {
+	previousFiles := make(map[string]struct{})
+	for _, file := range previous.Files {
+		previousFiles[file] = struct{}{}
+	}
+	for file, data := range i.files {
+		existing, err := os.ReadFile(filepath.Join(appDir.AppDir(), file))
+		if errors.Is(err, os.ErrNotExist) || (err == nil && bytes.Equal(existing, data)) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		if owner, found := index.owner(file, previous.Name); found {
+			return fmt.Errorf("%s is already installed by bundle %s", file, owner)
+		}
+		if _, ok := previousFiles[file]; !ok {
+			return fmt.Errorf("%s already exists and was not installed by a bundle", file)
+		}
+	}
+	return nil
+}
+
+// findComponentDefinitionFiles returns the component definitions found under the root directory.
+func findComponentDefinitionFiles(root string) ([]string, error) {
+	var compDefPaths []string
+	err := filepath.WalkDir(root, func(path string, d os.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || !strings.HasSuffix(d.Name(), complytime.ComponentDefinitionSuffix) {
+			return nil
+		}
+		if d.Type()&os.ModeSymlink != 0 {
+			if _, err := resolveInRoot(root, path); errors.Is(err, errOutsideRoot) {
+				return fmt.Errorf("component definition %s is outside of the bundle root", path)
+			} else if err != nil {
+				return err
+			}
+		}
+		compDefPaths = append(compDefPaths, path)
+		return nil
+	})
+	return compDefPaths, err
+}
+
+// bundleName returns the default bundle name for the source.
+func bundleName(source string) string {
+	name := filepath.Base(filepath.Clean(source))
+	for _, suffix := range []string{".tar.gz", ".tgz", ".tar", ".zip"} {
+		if strings.HasSuffix(name, suffix) {
+			return strings.TrimSuffix(name, suffix)
+		}
+	}
+	return name
+}
@@ -0,0 +1,267 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package bundle
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/oscal-compass/oscal-sdk-go/validation"
+	"github.com/stretchr/testify/require"
+
+	"github.com/complytime/complyctl/internal/complytime"
+	"github.com/complytime/complyctl/internal/complytime/complytimetest"
+)
+
+var testDataDir = filepath.Join("..", "complytime", "testdata", "complytime")
+
+// writeTestBundle writes a bundle with the test component definition at its root and the
+// control sources in a nested directory.
+func writeTestBundle(t *testing.T, source string) string {
+	bundleDir := t.TempDir()
+	copyTestFile(t, filepath.Join(testDataDir, "bundles", "example-component-definition.json"), filepath.Join(bundleDir, "example-component-definition.json"), source)
+	for _, name := range []string{"sample-profile.json", "sample-catalog.json"} {
+		copyTestFile(t, filepath.Join(testDataDir, "controls", name), filepath.Join(bundleDir, "controls", name), "")
+	}
+	return bundleDir
+}
+
+func copyTestFile(t *testing.T, from, to, source string) {
+	data, err := os.ReadFile(from)
+	require.NoError(t, err)
+	if source != "" {
+		data = []byte(strings.ReplaceAll(string(data), "file://controls/sample-profile.json", source))
+	}
+	require.NoError(t, os.MkdirAll(filepath.Dir(to), 0700))
+	require.NoError(t, os.WriteFile(to, data, 0600))
+}
+
+func TestInstall(t *testing.T) {
+	tests := []struct {
+		name   string
+		source string
+	}{
+		{
+			name:   "Valid/ControlsHref",
+			source: "file://controls/sample-profile.json",
+		},
+		{
+			name:   "Valid/RelativePath",
+			source: "controls/sample-profile.json",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			appDir := complytimetest.NewApplicationDirectory(t)
+			bundleDir := writeTestBundle(t, tt.source)
+
+			entry, err := Install(appDir, bundleDir, "example", hclog.NewNullLogger())
+			require.NoError(t, err)
+			require.Equal(t, "example", entry.Name)
+			require.Equal(t, []string{"example"}, entry.Frameworks)
+			require.Equal(t, []string{
+				filepath.Join("bundles", "example-component-definition.json"),
+				filepath.Join("controls", "sample-catalog.json"),
+				filepath.Join("controls", "sample-profile.json"),
+			}, entry.Files)
+
+			compDefs, err := complytime.FindComponentDefinitions(appDir.BundleDir(), validation.NoopValidator{})
+			require.NoError(t, err)
+			require.Len(t, compDefs, 1)
+			implementation := (*(*compDefs[0].Components)[0].ControlImplementations)[0]
+			require.Equal(t, "file://controls/sample-profile.json", implementation.Source)
+
+			index, err := LoadIndex(appDir)
+			require.NoError(t, err)
+			installed, found := index.Find("example")
+			require.True(t, found)
+			require.Equal(t, entry.Files, installed.Files)
+		})
+	}
+}
+
+func TestInstallArchive(t *testing.T) {
+	appDir := complytimetest.NewApplicationDirectory(t)
+	bundleDir := writeTestBundle(t, "")
+
+	archivePath := filepath.Join(t.TempDir(), "example-bundle.tar.gz")
+	archiveFile, err := os.Create(archivePath)
+	require.NoError(t, err)
+	gzipWriter := gzip.NewWriter(archiveFile)
+	tarWriter := tar.NewWriter(gzipWriter)
+	require.NoError(t, tarWriter.AddFS(os.DirFS(bundleDir)))
+	require.NoError(t, tarWriter.Close())
+	require.NoError(t, gzipWriter.Close())
+	require.NoError(t, archiveFile.Close())
+
+	entry, err := Install(appDir, archivePath, "", hclog.NewNullLogger())
+	require.NoError(t, err)
+	require.Equal(t, "example-bundle", entry.Name)
+	require.Equal(t, archivePath, entry.Source)
+	require.FileExists(t, filepath.Join(appDir.ControlDir(), "sample-catalog.json"))
+}
+
+func TestInstallSignatures(t *testing.T) {
+	appDir := complytimetest.NewApplicationDirectory(t)
+	// The href of the component definition is rewritten, the hrefs of the profile are not
+	bundleDir := writeTestBundle(t, "controls/sample-profile.json")
+	for _, file := range []string{
+		"example-component-definition.json.sig",
+		filepath.Join("controls", "sample-profile.json.sig"),
+		filepath.Join("controls", "sample-catalog.json.sigstore.json"),
+	} {
+		require.NoError(t, os.WriteFile(filepath.Join(bundleDir, file), []byte("signature of "+file), 0600))
+	}
+
+	entry, err := Install(appDir, bundleDir, "example", hclog.NewNullLogger())
+	require.NoError(t, err)
+	require.Equal(t, []string{
+		filepath.Join("bundles", "example-component-definition.json"),
+		filepath.Join("controls", "sample-catalog.json"),
+		filepath.Join("controls", "sample-catalog.json.sigstore.json"),
+		filepath.Join("controls", "sample-profile.json"),
+		filepath.Join("controls", "sample-profile.json.sig"),
+	}, entry.Files)
+
+	// Files installed with their signatures are kept byte for byte
+	for _, file := range []string{"sample-profile.json", "sample-catalog.json"} {
+		want, err := os.ReadFile(filepath.Join(bundleDir, "controls", file))
+		require.NoError(t, err)
+		got, err := os.ReadFile(filepath.Join(appDir.ControlDir(), file))
+		require.NoError(t, err)
+		require.Equal(t, want, got)
+	}
+	data, err := os.ReadFile(filepath.Join(appDir.ControlDir(), "sample-profile.json.sig"))
+	require.NoError(t, err)
+	require.Equal(t, "signature of "+filepath.Join("controls", "sample-profile.json.sig"), string(data))
+	require.NoFileExists(t, filepath.Join(appDir.BundleDir(), "example-component-definition.json.sig"))
+}
+
+func TestInstallErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		prepare func(t *testing.T, appDir complytime.ApplicationDirectory) string
+		wantErr string
+	}{
+		{
+			name: "Invalid/MissingControlSource",
+			prepare: func(t *testing.T, _ complytime.ApplicationDirectory) string {
+				return writeTestBundle(t, "file://controls/missing-profile.json")
+			},
+			wantErr: "control source file://controls/missing-profile.json not found in bundle",
+		},
+		{
+			name: "Invalid/RemoteControlSource",
+			prepare: func(t *testing.T, _ complytime.ApplicationDirectory) string {
+				return writeTestBundle(t, "https://example.com/profile.json")
+			},
+			wantErr: "unsupported href https://example.com/profile.json: only local files are supported",
+		},
+		{
+			name: "Invalid/AbsoluteControlSource",
+			prepare: func(t *testing.T, _ complytime.ApplicationDirectory) string {
+				return writeTestBundle(t, "file:///etc/passwd")
+			},
+			wantErr: "unsupported href file:///etc/passwd: only paths relative to the bundle are supported",
+		},
+		{
+			name: "Invalid/ControlSourceOutsideBundle",
+			prepare: func(t *testing.T, _ complytime.ApplicationDirectory) string {
+				bundleDir := writeTestBundle(t, "file://../outside-profile.json")
+				outside := filepath.Join(filepath.Dir(bundleDir), "outside-profile.json")
+				copyTestFile(t, filepath.Join(testDataDir, "controls", "sample-profile.json"), outside, "")
+				return bundleDir
+			},
+			wantErr: "control source file://../outside-profile.json is outside of the bundle root",
+		},
+		{
+			name: "Invalid/SymlinkOutsideBundle",
+			prepare: func(t *testing.T, _ complytime.ApplicationDirectory) string {
+				bundleDir := writeTestBundle(t, "")
+				outside := filepath.Join(t.TempDir(), "sample-profile.json")
+				copyTestFile(t, filepath.Join(testDataDir, "controls", "sample-profile.json"), outside, "")
+				link := filepath.Join(bundleDir, "controls", "sample-profile.json")
+				require.NoError(t, os.Remove(link))
+				require.NoError(t, os.Symlink(outside, link))
+				return bundleDir
+			},
+			wantErr: "control source file://controls/sample-profile.json is outside of the bundle root",
+		},
+		{
+			name: "Invalid/SymlinkedComponentDefinition",
+			prepare: func(t *testing.T, _ complytime.ApplicationDirectory) string {
+				bundleDir := writeTestBundle(t, "")
+				outside := filepath.Join(t.TempDir(), "example-component-definition.json")
+				link := filepath.Join(bundleDir, "example-component-definition.json")
+				require.NoError(t, os.Rename(link, outside))
+				require.NoError(t, os.Symlink(outside, link))
+				return bundleDir
+			},
+			wantErr: "is outside of the bundle root",
+		},
+		{
+			name: "Invalid/NoComponentDefinitions",
+			prepare: func(t *testing.T, _ complytime.ApplicationDirectory) string {
+				return t.TempDir()
+			},
+			wantErr: "no component definitions found",
+		},
+		{
+			name: "Invalid/UnmanagedFile",
+			prepare: func(t *testing.T, appDir complytime.ApplicationDirectory) string {
+				existing := filepath.Join(appDir.ControlDir(), "sample-catalog.json")
+				require.NoError(t, os.WriteFile(existing, []byte("{}"), 0600))
+				return writeTestBundle(t, "")
+			},
+			wantErr: "controls/sample-catalog.json already exists and was not installed by a bundle",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			appDir := complytimetest.NewApplicationDirectory(t)
+			_, err := Install(appDir, tt.prepare(t, appDir), "example", hclog.NewNullLogger())
+			require.ErrorContains(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestRemove(t *testing.T) {
+	appDir := complytimetest.NewApplicationDirectory(t)
+	logger := hclog.NewNullLogger()
+	_, err := Install(appDir, writeTestBundle(t, ""), "first", logger)
+	require.NoError(t, err)
+	_, err = Install(appDir, writeTestBundle(t, ""), "second", logger)
+	require.NoError(t, err)
+
+	index, err := LoadIndex(appDir)
+	require.NoError(t, err)
+	require.Empty(t, index.OrphanedFrameworks("first"))
+
+	// The files are shared with the second bundle and are kept.
+	_, err = Remove(appDir, "first")
+	require.NoError(t, err)
+	require.FileExists(t, filepath.Join(appDir.ControlDir(), "sample-profile.json"))
+
+	index, err = LoadIndex(appDir)
+	require.NoError(t, err)
+	require.Equal(t, []string{"example"}, index.OrphanedFrameworks("second"))
+
+	entry, err := Remove(appDir, "second")
+	require.NoError(t, err)
+	for _, file := range entry.Files {
+		require.NoFileExists(t, filepath.Join(appDir.AppDir(), file))
+	}
+
+	_, err = Remove(appDir, "second")
+	require.ErrorIs(t, err, ErrNotInstalled)
+}
+
+func TestExtractFile(t *testing.T) {
+	err := extractFile(t.TempDir(), "../outside.json", strings.NewReader("{}"))
+	require.EqualError(t, err, "archive entry ../outside.json is outside of the archive root")
+}
@@ -0,0 +1,341 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package bundle
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/hashicorp/go-hclog"
+
+	"github.com/complytime/complyctl/internal/complytime"
+)
+
+// OCI media types and annotations of bundle artifacts
+const (
+	mediaTypeImageManifest      = "application/vnd.oci.image.manifest.v1+json"
+	mediaTypeImageIndex         = "application/vnd.oci.image.index.v1+json"
+	mediaTypeDockerManifest     = "application/vnd.docker.distribution.manifest.v2+json"
+	mediaTypeDockerManifestList = "application/vnd.docker.distribution.manifest.list.v2+json"
+	mediaTypeLayerTar           = "application/vnd.oci.image.layer.v1.tar"
+	mediaTypeLayerTarGzip       = "application/vnd.oci.image.layer.v1.tar+gzip"
+	mediaTypeDockerLayer        = "application/vnd.docker.image.rootfs.diff.tar.gzip"
+	annotationRefName           = "org.opencontainers.image.ref.name"
+	annotationTitle             = "org.opencontainers.image.title"
+)
+
+const (
+	// OCILayoutPrefix is the prefix of bundle sources in an OCI image layout on disk.
+	OCILayoutPrefix = "oci-layout:"
+	// OCIRegistryPrefix is the prefix of bundle sources in an OCI registry.
+	OCIRegistryPrefix = "oci://"
+	defaultTag        = "latest"
+	maxManifestSize   = 4 << 20
+)
+
+// ErrDigestMismatch is returned when a bundle reference does not resolve to the pinned digest
+// or when fetched content does not match its digest.
+var ErrDigestMismatch = errors.New("digest mismatch")
+
+var digestPattern = regexp.MustCompile(`^sha256:[a-f0-9]{64}$`)
+
+// OCIOptions configure the installation of bundles from OCI image layouts and registries.
+type OCIOptions struct {
+	// Name of the installed bundle. Defaults to the last element of the repository or layout path.
+	Name string
+	// Digest pins the manifest digest of the bundle. The installation fails when the
+	// reference resolves to another digest.
+	Digest string
+	// PlainHTTP connects to the registry over HTTP instead of HTTPS.
+	PlainHTTP bool
+}
+
+// descriptor describes OCI content addressed by its digest.
+type descriptor struct {
+	MediaType   string            `json:"mediaType"`
+	Digest      string            `json:"digest"`
+	Size        int64             `json:"size"`
+	Annotations map[string]string `json:"annotations,omitempty"`
+}
+
+// ociManifest holds the fields of OCI image manifests and indexes read by complytime.
+type ociManifest struct {
+	MediaType string       `json:"mediaType"`
+	Manifests []descriptor `json:"manifests"`
+	Layers    []descriptor `json:"layers"`
+}
+
+// ociStore provides the content of an OCI image layout or repository.
+type ociStore interface {
+	// resolve returns the descriptor of the manifest referenced by the tag or digest.
+	resolve(ctx context.Context, reference string) (descriptor, error)
+	// fetch returns the content of the descriptor.
+	fetch(ctx context.Context, desc descriptor) (io.ReadCloser, error)
+}
+
+// ociReference is a parsed OCI bundle source.
+type ociReference struct {
+	layout bool
+	// location is the layout directory or the registry host and repository.
+	location string
+	tag      string
+	digest   string
+}
+
+// IsOCIReference reports whether the source references a bundle in an OCI image layout or registry.
+func IsOCIReference(source string) bool {
+	return strings.HasPrefix(source, OCILayoutPrefix) || strings.HasPrefix(source, OCIRegistryPrefix)
+}
+
+// parseOCIReference parses sources in the oci-layout:<path>[:<tag>][@<digest>] and
+// oci://<registry>/<repository>[:<tag>][@<digest>] forms.
+func parseOCIReference(source string) (ociReference, error) {
+	var ref ociReference
+	var rest string
+	switch {
+	case strings.HasPrefix(source, OCILayoutPrefix):
+		ref.layout = true
+		rest = strings.TrimPrefix(source, OCILayoutPrefix)
+	case strings.HasPrefix(source, OCIRegistryPrefix):
+		rest = strings.TrimPrefix(source, OCIRegistryPrefix)
+	default:
+		return ociReference{}, fmt.Errorf("invalid OCI reference %s: must start with %s or %s", source, OCILayoutPrefix, OCIRegistryPrefix)
+	}
+	if i := strings.LastIndex(rest, "@"); i >= 0 {
+		ref.digest = rest[i+1:]
+		rest = rest[:i]
+		if !digestPattern.MatchString(ref.digest) {
+			return ociReference{}, fmt.Errorf("invalid OCI reference %s: unsupported digest %q", source, ref.digest)
+		}
+	}
+	if i := strings.LastIndex(rest, ":"); i > strings.LastIndex(rest, "/") {
+		ref.tag = rest[i+1:]
+		rest = rest[:i]
+	}
+	if ref.tag == "" && ref.digest == "" {
+		ref.tag = defaultTag
+	}
+	ref.location = rest
+	if !ref.layout {
+		host, repository, _ := strings.Cut(rest, "/")
+		if host == "" || repository == "" {
+			return ociReference{}, fmt.Errorf("invalid OCI reference %s: must include a registry and repository", source)
+		}
+	} else if rest == "" {
+		return ociReference{}, fmt.Errorf("invalid OCI reference %s: must include the layout path", source)
+	}
+	return ref, nil
+}
+
+// reference returns the digest or, when no digest is set, the tag to resolve.
+func (r ociReference) reference() string {
+	if r.digest != "" {
+		return r.digest
+	}
+	return r.tag
+}
+
+// name returns the default bundle name for the reference.
+func (r ociReference) name() string {
+	if r.layout {
+		return filepath.Base(filepath.Clean(r.location))
+	}
+	return path.Base(r.location)
+}
+
+// InstallOCI installs the bundle in an OCI image layout or registry into the application directory.
+//
+// The layers of the referenced manifest are extracted as the bundle root: tar layers are unpacked and
+// other layers are written to the file named by their title annotation, as pushed by ORAS. Every blob is
+// verified against its digest, and the manifest digest is recorded in the index.
+func InstallOCI(ctx context.Context, appDir complytime.ApplicationDirectory, source string, opts OCIOptions, logger hclog.Logger) (Entry, error) {
+	ref, err := parseOCIReference(source)
+	if err != nil {
+		return Entry{}, err
+	}
+	pinned := ref.digest
+	if opts.Digest != "" {
+		if !digestPattern.MatchString(opts.Digest) {
+			return Entry{}, fmt.Errorf("unsupported digest %q: must be a sha256 digest", opts.Digest)
+		}
+		if pinned != "" && pinned != opts.Digest {
+			return Entry{}, fmt.Errorf("%w: %s does not match the pinned digest %s", ErrDigestMismatch, source, opts.Digest)
+		}
+		pinned = opts.Digest
+	}
+
+	var store ociStore
+	if ref.layout {
+		store = layoutStore{dir: ref.location}
+	} else {
+		store = newRegistryStore(ref.location, opts.PlainHTTP)
+	}
+	desc, err := store.resolve(ctx, ref.reference())
+	if err != nil {
+		return Entry{}, err
+	}
+	if pinned != "" && desc.Digest != pinned {
+		return Entry{}, fmt.Errorf("%w: %s resolved to %s, expected %s", ErrDigestMismatch, source, desc.Digest, pinned)
+	}
+	logger.Debug(fmt.Sprintf("Resolved %s to %s", source, desc.Digest))
+
+	tmpDir, err := os.MkdirTemp("", "complytime-bundle-")
+	if err != nil {
+		return Entry{}, err
+	}
+	defer os.RemoveAll(tmpDir)
+	if err := pull(ctx, store, desc, tmpDir, logger); err != nil {
+		return Entry{}, fmt.Errorf("failed to pull %s: %w", source, err)
+	}
+
+	name := opts.Name
+	if name == "" {
+		name = ref.name()
+	}
+	return installDir(appDir, tmpDir, Entry{Name: name, Source: source, Digest: desc.Digest}, logger)
+}
+
+// pull extracts the layers of the manifest into the destination directory.
+func pull(ctx context.Context, store ociStore, desc descriptor, destination string, logger hclog.Logger) error {
+	data, err := fetchVerified(ctx, store, desc, maxManifestSize)
+	if err != nil {
+		return err
+	}
+	var manifest ociManifest
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return fmt.Errorf("failed to parse manifest %s: %w", desc.Digest, err)
+	}
+	mediaType := desc.MediaType
+	if mediaType == "" {
+		mediaType = manifest.MediaType
+	}
+	if mediaType == "" && len(manifest.Manifests) > 0 {
+		mediaType = mediaTypeImageIndex
+	}
+	switch mediaType {
+	case mediaTypeImageIndex, mediaTypeDockerManifestList:
+		if len(manifest.Manifests) != 1 {
+			return fmt.Errorf("image index %s must reference exactly one manifest, found %d", desc.Digest, len(manifest.Manifests))
+		}
+		return pull(ctx, store, manifest.Manifests[0], destination, logger)
+	case "", mediaTypeImageManifest, mediaTypeDockerManifest:
+	default:
+		return fmt.Errorf("unsupported manifest media type %q", mediaType)
+	}
+
+	for _, layer := range manifest.Layers {
+		title := layer.Annotations[annotationTitle]
+		switch {
+		case layer.MediaType == mediaTypeLayerTar, layer.MediaType == mediaTypeLayerTarGzip, layer.MediaType == mediaTypeDockerLayer:
+			blob, err := fetchVerified(ctx, store, layer, maxArchiveFileSize)
+			if err != nil {
+				return err
+			}
+			if err := extractLayer(blob, layer, destination); err != nil {
+				return err
+			}
+		case title != "":
+			blob, err := fetchVerified(ctx, store, layer, maxArchiveFileSize)
+			if err != nil {
+				return err
+			}
+			if err := extractFile(destination, title, bytes.NewReader(blob)); err != nil {
+				return err
+			}
+		default:
+			logger.Debug(fmt.Sprintf("Skipping layer %s with media type %s", layer.Digest, layer.MediaType))
+		}
+	}
+	return nil
+}
+
+// extractLayer extracts the tar or gzip compressed tar layer into the destination directory.
+func extractLayer(blob []byte, layer descriptor, destination string) error {
+	var reader io.Reader = bytes.NewReader(blob)
+	if layer.MediaType != mediaTypeLayerTar {
+		gzipReader, err := gzip.NewReader(reader)
+		if err != nil {
+			return fmt.Errorf("failed to read layer %s: %w", layer.Digest, err)
+		}
+		defer gzipReader.Close()
+		reader = gzipReader
+	}
+	return extractTar(reader, layer.Digest, destination)
+}
+
+// fetchVerified returns the content of the descriptor after verifying its size and digest.
+func fetchVerified(ctx context.Context, store ociStore, desc descriptor, limit int64) ([]byte, error) {
+	if !digestPattern.MatchString(desc.Digest) {
+		return nil, fmt.Errorf("unsupported digest %q: must be a sha256 digest", desc.Digest)
+	}
+	if desc.Size > limit {
+		return nil, fmt.Errorf("content %s exceeds the maximum size of %d bytes", desc.Digest, limit)
+	}
+	reader, err := store.fetch(ctx, desc)
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+	data, err := io.ReadAll(io.LimitReader(reader, limit+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(data)) > limit {
+		return nil, fmt.Errorf("content %s exceeds the maximum size of %d bytes", desc.Digest, limit)
+	}
+	if desc.Size > 0 && int64(len(data)) != desc.Size {
+		return nil, fmt.Errorf("content %s has size %d, expected %d", desc.Digest, len(data), desc.Size)
+	}
+	if actual := sha256Digest(data); actual != desc.Digest {
+		return nil, fmt.Errorf("%w: content %s has digest %s", ErrDigestMismatch, desc.Digest, actual)
+	}
+	return data, nil
+}
+
+func sha256Digest(data []byte) string {
+	sum := sha256.Sum256(data)
+	return "sha256:" + hex.EncodeToString(sum[:])
+}
+
+// layoutStore reads content from an OCI image layout directory.
+type layoutStore struct {
+	dir string
+}
+
+func (l layoutStore) resolve(_ context.Context, reference string) (descriptor, error) {
+	data, err := os.ReadFile(filepath.Join(l.dir, "index.json"))
+	if err != nil {
+		return descriptor{}, fmt.Errorf("failed to read OCI image layout %s: %w", l.dir, err)
+	}
+	var index ociManifest
+	if err := json.Unmarshal(data, &index); err != nil {
+		return descriptor{}, fmt.Errorf("failed to parse OCI image layout index %s: %w", l.dir, err)
+	}
+	for _, desc := range index.Manifests {
+		if desc.Digest == reference || desc.Annotations[annotationRefName] == reference {
+			return desc, nil
+		}
+	}
+	// Manifests nested in an index are not listed in the layout index but can be referenced by digest.
+	if digestPattern.MatchString(reference) {
+		return descriptor{Digest: reference}, nil
+	}
+	return descriptor{}, fmt.Errorf("reference %s not found in OCI image layout %s", reference, l.dir)
+}
+
+func (l layoutStore) fetch(_ context.Context, desc descriptor) (io.ReadCloser, error) {
+	algorithm, encoded, _ := strings.Cut(desc.Digest, ":")
+	return os.Open(filepath.Join(l.dir, "blobs", algorithm, encoded))
+}
@@ -0,0 +1,259 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package bundle
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/stretchr/testify/require"
+
+	"github.com/complytime/complyctl/internal/complytime/complytimetest"
+)
+
+// writeTestLayout writes an OCI image layout with the test bundle tagged as 1.0. The component
+// definition is pushed as a file layer and the control sources as a gzip compressed tar layer.
+// The layout directory and the manifest digest are returned.
+func writeTestLayout(t *testing.T) (string, string) {
+	layoutDir := t.TempDir()
+	bundleDir := writeTestBundle(t, "")
+
+	compDef, err := os.ReadFile(filepath.Join(bundleDir, "example-component-definition.json"))
+	require.NoError(t, err)
+	compDefLayer := writeTestBlob(t, layoutDir, "application/json", compDef)
+	compDefLayer.Annotations = map[string]string{annotationTitle: "example-component-definition.json"}
+
+	var controls bytes.Buffer
+	gzipWriter := gzip.NewWriter(&controls)
+	tarWriter := tar.NewWriter(gzipWriter)
+	for _, name := range []string{"sample-profile.json", "sample-catalog.json"} {
+		data, err := os.ReadFile(filepath.Join(bundleDir, "controls", name))
+		require.NoError(t, err)
+		require.NoError(t, tarWriter.WriteHeader(&tar.Header{Name: "controls/" + name, Mode: 0600, Size: int64(len(data))}))
+		_, err = tarWriter.Write(data)
+		require.NoError(t, err)
+	}
+	require.NoError(t, tarWriter.Close())
+	require.NoError(t, gzipWriter.Close())
+	controlsLayer := writeTestBlob(t, layoutDir, mediaTypeLayerTarGzip, controls.Bytes())
+
+	manifest := map[string]any{
+		"schemaVersion": 2,
+		"mediaType":     mediaTypeImageManifest,
+		"config":        writeTestBlob(t, layoutDir, "application/vnd.oci.empty.v1+json", []byte("{}")),
+		"layers":        []descriptor{compDefLayer, controlsLayer},
+	}
+	manifestData, err := json.Marshal(manifest)
+	require.NoError(t, err)
+	manifestDesc := writeTestBlob(t, layoutDir, mediaTypeImageManifest, manifestData)
+	manifestDesc.Annotations = map[string]string{annotationRefName: "1.0"}
+
+	index, err := json.Marshal(map[string]any{
+		"schemaVersion": 2,
+		"manifests":     []descriptor{manifestDesc},
+	})
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(filepath.Join(layoutDir, "index.json"), index, 0600))
+	require.NoError(t, os.WriteFile(filepath.Join(layoutDir, "oci-layout"), []byte(`{"imageLayoutVersion":"1.0.0"}`), 0600))
+	return layoutDir, manifestDesc.Digest
+}
+
+func writeTestBlob(t *testing.T, layoutDir, mediaType string, data []byte) descriptor {
+	desc := descriptor{MediaType: mediaType, Digest: sha256Digest(data), Size: int64(len(data))}
+	blobPath := filepath.Join(layoutDir, "blobs", "sha256", strings.TrimPrefix(desc.Digest, "sha256:"))
+	require.NoError(t, os.MkdirAll(filepath.Dir(blobPath), 0700))
+	require.NoError(t, os.WriteFile(blobPath, data, 0600))
+	return desc
+}
+
+// newTestRegistry serves the OCI image layout as the bundles/example repository and
+// requires an anonymous bearer token.
+func newTestRegistry(t *testing.T, layoutDir string) *httptest.Server {
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/token" {
+			_, _ = fmt.Fprint(w, `{"token":"anonymous"}`)
+			return
+		}
+		if r.Header.Get("Authorization") != "Bearer anonymous" {
+			w.Header().Set("WWW-Authenticate", fmt.Sprintf(`Bearer realm="%s/token",service="test",scope="repository:bundles/example:pull"`, server.URL))
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		endpoint, reference, _ := strings.Cut(strings.TrimPrefix(r.URL.Path, "/v2/bundles/example/"), "/")
+		store := layoutStore{dir: layoutDir}
+		desc, err := store.resolve(r.Context(), reference)
+		if err != nil {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		reader, err := store.fetch(r.Context(), desc)
+		if err != nil {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		defer reader.Close()
+		if endpoint == "manifests" {
+			w.Header().Set("Content-Type", mediaTypeImageManifest)
+		}
+		_, _ = io.Copy(w, reader)
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestParseOCIReference(t *testing.T) {
+	digest := "sha256:" + strings.Repeat("a", 64)
+	tests := []struct {
+		name    string
+		source  string
+		want    ociReference
+		wantErr string
+	}{
+		{
+			name:   "Valid/LayoutWithTag",
+			source: "oci-layout:./bundles/cis:1.0",
+			want:   ociReference{layout: true, location: "./bundles/cis", tag: "1.0"},
+		},
+		{
+			name:   "Valid/LayoutDefaultTag",
+			source: "oci-layout:/tmp/cis",
+			want:   ociReference{layout: true, location: "/tmp/cis", tag: "latest"},
+		},
+		{
+			name:   "Valid/RegistryWithPortAndDigest",
+			source: "oci://localhost:5000/bundles/cis@" + digest,
+			want:   ociReference{location: "localhost:5000/bundles/cis", digest: digest},
+		},
+		{
+			name:   "Valid/RegistryWithTagAndDigest",
+			source: "oci://quay.io/complytime/cis:1.0@" + digest,
+			want:   ociReference{location: "quay.io/complytime/cis", tag: "1.0", digest: digest},
+		},
+		{
+			name:    "Invalid/MissingRepository",
+			source:  "oci://localhost:5000",
+			wantErr: "invalid OCI reference oci://localhost:5000: must include a registry and repository",
+		},
+		{
+			name:    "Invalid/Digest",
+			source:  "oci://quay.io/complytime/cis@md5:1234",
+			wantErr: `invalid OCI reference oci://quay.io/complytime/cis@md5:1234: unsupported digest "md5:1234"`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ref, err := parseOCIReference(tt.source)
+			if tt.wantErr != "" {
+				require.EqualError(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.want, ref)
+		})
+	}
+}
+
+func TestInstallOCI(t *testing.T) {
+	layoutDir, manifestDigest := writeTestLayout(t)
+	registry := newTestRegistry(t, layoutDir)
+	registrySource := "oci://" + strings.TrimPrefix(registry.URL, "http://") + "/bundles/example"
+	otherDigest := "sha256:" + strings.Repeat("0", 64)
+
+	tests := []struct {
+		name     string
+		source   string
+		opts     OCIOptions
+		wantName string
+		wantErr  string
+	}{
+		{
+			name:     "Valid/LayoutTag",
+			source:   "oci-layout:" + layoutDir + ":1.0",
+			wantName: filepath.Base(layoutDir),
+		},
+		{
+			name:     "Valid/LayoutPinnedDigest",
+			source:   "oci-layout:" + layoutDir + ":1.0",
+			opts:     OCIOptions{Name: "pinned", Digest: manifestDigest},
+			wantName: "pinned",
+		},
+		{
+			name:     "Valid/RegistryDigest",
+			source:   registrySource + "@" + manifestDigest,
+			opts:     OCIOptions{PlainHTTP: true},
+			wantName: "example",
+		},
+		{
+			name:     "Valid/RegistryTag",
+			source:   registrySource + ":1.0",
+			opts:     OCIOptions{PlainHTTP: true, Digest: manifestDigest},
+			wantName: "example",
+		},
+		{
+			name:    "Invalid/PinnedDigestMismatch",
+			source:  "oci-layout:" + layoutDir + ":1.0",
+			opts:    OCIOptions{Digest: otherDigest},
+			wantErr: "digest mismatch",
+		},
+		{
+			name:    "Invalid/UnknownTag",
+			source:  "oci-layout:" + layoutDir + ":2.0",
+			wantErr: "reference 2.0 not found in OCI image layout",
+		},
+		{
+			name:    "Invalid/RegistryUnknownTag",
+			source:  registrySource + ":2.0",
+			opts:    OCIOptions{PlainHTTP: true},
+			wantErr: "registry returned 404 Not Found",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			appDir := complytimetest.NewApplicationDirectory(t)
+			entry, err := InstallOCI(context.Background(), appDir, tt.source, tt.opts, hclog.NewNullLogger())
+			if tt.wantErr != "" {
+				require.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.wantName, entry.Name)
+			require.Equal(t, manifestDigest, entry.Digest)
+			require.Equal(t, []string{"example"}, entry.Frameworks)
+			require.FileExists(t, filepath.Join(appDir.BundleDir(), "example-component-definition.json"))
+			require.FileExists(t, filepath.Join(appDir.ControlDir(), "sample-profile.json"))
+			require.FileExists(t, filepath.Join(appDir.ControlDir(), "sample-catalog.json"))
+		})
+	}
+}
+
+func TestInstallOCITamperedBlob(t *testing.T) {
+	layoutDir, _ := writeTestLayout(t)
+	blobs, err := os.ReadDir(filepath.Join(layoutDir, "blobs", "sha256"))
+	require.NoError(t, err)
+	// Tamper with every blob but the manifest so that the first layer fails verification.
+	for _, blob := range blobs {
+		blobPath := filepath.Join(layoutDir, "blobs", "sha256", blob.Name())
+		data, err := os.ReadFile(blobPath)
+		require.NoError(t, err)
+		if bytes.Contains(data, []byte(mediaTypeImageManifest)) {
+			continue
+		}
+		data[0] ^= 0xff
+		require.NoError(t, os.WriteFile(blobPath, data, 0600))
+	}
+	_, err = InstallOCI(context.Background(), complytimetest.NewApplicationDirectory(t), "oci-layout:"+layoutDir+":1.0", OCIOptions{}, hclog.NewNullLogger())
+	require.ErrorIs(t, err, ErrDigestMismatch)
+}
@@ -0,0 +1,177 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package bundle
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// registryTimeout is the maximum time of a single request to a registry.
+const registryTimeout = 5 * time.Minute
+
+// manifestAccept lists the manifest media types accepted from registries.
+var manifestAccept = strings.Join([]string{
+	mediaTypeImageManifest,
+	mediaTypeImageIndex,
+	mediaTypeDockerManifest,
+	mediaTypeDockerManifestList,
+}, ", ")
+
+var challengeParamPattern = regexp.MustCompile(`(\w+)="([^"]*)"`)
+
+// registryStore reads content from a repository of an OCI distribution registry.
+type registryStore struct {
+	client *http.Client
+	// baseURL is the API endpoint of the repository.
+	baseURL string
+	token   string
+	// manifests caches the manifests fetched while resolving references by digest.
+	manifests map[string][]byte
+}
+
+func newRegistryStore(location string, plainHTTP bool) *registryStore {
+	host, repository, _ := strings.Cut(location, "/")
+	scheme := "https"
+	if plainHTTP {
+		scheme = "http"
+	}
+	return &registryStore{
+		client:    &http.Client{Timeout: registryTimeout},
+		baseURL:   fmt.Sprintf("%s://%s/v2/%s", scheme, host, repository),
+		manifests: make(map[string][]byte),
+	}
+}
+
+func (r *registryStore) resolve(ctx context.Context, reference string) (descriptor, error) {
+	resp, err := r.get(ctx, "manifests/"+reference, manifestAccept)
+	if err != nil {
+		return descriptor{}, err
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxManifestSize+1))
+	if err != nil {
+		return descriptor{}, err
+	}
+	if len(data) > maxManifestSize {
+		return descriptor{}, fmt.Errorf("manifest %s exceeds the maximum size of %d bytes", reference, maxManifestSize)
+	}
+	// The digest is computed from the content rather than trusted from the response headers.
+	digest := sha256Digest(data)
+	r.manifests[digest] = data
+	mediaType, _, _ := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+	return descriptor{MediaType: mediaType, Digest: digest, Size: int64(len(data))}, nil
+}
+
+func (r *registryStore) fetch(ctx context.Context, desc descriptor) (io.ReadCloser, error) {
+	if data, ok := r.manifests[desc.Digest]; ok {
+		return io.NopCloser(bytes.NewReader(data)), nil
+	}
+	endpoint := "blobs/" + desc.Digest
+	accept := desc.MediaType
+	switch desc.MediaType {
+	case mediaTypeImageManifest, mediaTypeImageIndex, mediaTypeDockerManifest, mediaTypeDockerManifestList:
+		endpoint = "manifests/" + desc.Digest
+		accept = manifestAccept
+	}
+	resp, err := r.get(ctx, endpoint, accept)
+	if err != nil {
+		return nil, err
+	}
+	return resp.Body, nil
+}
+
+// get requests the repository endpoint. Anonymous bearer tokens are requested when
+// the registry challenges the request.
+func (r *registryStore) get(ctx context.Context, endpoint, accept string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, r.baseURL+"/"+endpoint, nil)
+	if err != nil {
+		return nil, err
+	}
+	if accept != "" {
+		req.Header.Set("Accept", accept)
+	}
+	if r.token != "" {
+		req.Header.Set("Authorization", "Bearer "+r.token)
+	}
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode == http.StatusUnauthorized && r.token == "" {
+		challenge := resp.Header.Get("WWW-Authenticate")
+		resp.Body.Close()
+		if err := r.authenticate(ctx, challenge); err != nil {
+			return nil, err
+		}
+		return r.get(ctx, endpoint, accept)
+	}
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		return nil, fmt.Errorf("registry returned %s for %s", resp.Status, req.URL)
+	}
+	return resp, nil
+}
+
+// authenticate requests an anonymous token from the realm of the bearer challenge.
+func (r *registryStore) authenticate(ctx context.Context, challenge string) error {
+	scheme, params, _ := strings.Cut(challenge, " ")
+	if !strings.EqualFold(scheme, "Bearer") {
+		return fmt.Errorf("unsupported registry authentication %q", challenge)
+	}
+	values := url.Values{}
+	var realm string
+	for _, match := range challengeParamPattern.FindAllStringSubmatch(params, -1) {
+		switch match[1] {
+		case "realm":
+			realm = match[2]
+		case "service", "scope":
+			values.Set(match[1], match[2])
+		}
+	}
+	if realm == "" {
+		return fmt.Errorf("registry authentication challenge %q has no realm", challenge)
+	}
+	tokenURL, err := url.Parse(realm)
+	if err != nil {
+		return fmt.Errorf("invalid registry authentication realm %q: %w", realm, err)
+	}
+	tokenURL.RawQuery = values.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, tokenURL.String(), nil)
+	if err != nil {
+		return err
+	}
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("registry token request returned %s", resp.Status)
+	}
+	var token struct {
+		Token       string `json:"token"`
+		AccessToken string `json:"access_token"`
+	}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, maxManifestSize)).Decode(&token); err != nil {
+		return fmt.Errorf("failed to parse registry token: %w", err)
+	}
+	r.token = token.Token
+	if r.token == "" {
+		r.token = token.AccessToken
+	}
+	if r.token == "" {
+		return fmt.Errorf("registry token request returned no token")
+	}
+	return nil
+}
@@ -0,0 +1,235 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package complytime
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	oscalTypes "github.com/defenseunicorns/go-oscal/src/types/oscal-1-1-3"
+	"github.com/oscal-compass/compliance-to-policy-go/v2/framework/actions"
+	"github.com/oscal-compass/compliance-to-policy-go/v2/plugin"
+	"github.com/oscal-compass/compliance-to-policy-go/v2/policy"
+	"github.com/oscal-compass/oscal-sdk-go/extensions"
+	"github.com/oscal-compass/oscal-sdk-go/settings"
+)
+
+// Assessment Results metadata properties recording plugins that returned no results and canceled scans.
+const (
+	failedPluginProp      = "Failed_Plugin"
+	abortedAssessmentProp = "Aborted_Assessment"
+)
+
+// PluginFailure is the error of a plugin that did not return results.
+type PluginFailure struct {
+	PluginID plugin.ID
+	Err      error
+}
+
+// timeoutManifest reads the timeout of a plugin manifest. The timeout is a complyctl extension of
+// the C2P manifest format, so it is not part of plugin.Manifest.
+type timeoutManifest struct {
+	// Timeout is the duration, like "10m", after which the results of the plugin are no longer awaited.
+	Timeout string `json:"timeout"`
+}
+
+// PluginTimeouts returns the timeout set in the manifest of each plugin, or the default timeout when the
+// manifest does not set one. The manifest in the user config root takes precedence over the installed
+// manifest. A zero timeout means the plugin is awaited until it returns.
+func PluginTimeouts(appDir ApplicationDirectory, userConfigRoot string, pluginIDs []plugin.ID, defaultTimeout time.Duration) (map[plugin.ID]time.Duration, error) {
+	if userConfigRoot == "" {
+		if _, err := os.Stat(DefaultPluginConfigDir); err == nil {
+			userConfigRoot = DefaultPluginConfigDir
+		}
+	}
+	timeouts := make(map[plugin.ID]time.Duration)
+	for _, pluginID := range pluginIDs {
+		timeouts[pluginID] = defaultTimeout
+		manifestName := pluginManifestPrefix + pluginID.String() + pluginManifestSuffix
+		manifestPaths := []string{filepath.Join(appDir.PluginManifestDir(), manifestName)}
+		if userConfigRoot != "" {
+			manifestPaths = append(manifestPaths, filepath.Join(userConfigRoot, manifestName))
+		}
+		for _, manifestPath := range manifestPaths {
+			timeout, found, err := readPluginTimeout(manifestPath)
+			if err != nil {
+				return nil, err
+			}
+			if found {
+				timeouts[pluginID] = timeout
+			}
+		}
+	}
+	return timeouts, nil
+}
+
+// readPluginTimeout reads the timeout of the plugin manifest. It returns false if the manifest does not
+// exist or does not set a timeout.
+func readPluginTimeout(manifestPath string) (time.Duration, bool, error) {
+	manifestData, err := os.ReadFile(filepath.Clean(manifestPath))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return 0, false, nil
+		}
+		return 0, false, err
+	}
+	var manifest timeoutManifest
+	if err := json.Unmarshal(manifestData, &manifest); err != nil {
+		return 0, false, fmt.Errorf("failed to parse plugin manifest %s: %w", manifestPath, err)
+	}
+	if manifest.Timeout == "" {
+		return 0, false, nil
+	}
+	timeout, err := time.ParseDuration(manifest.Timeout)
+	if err != nil || timeout < 0 {
+		return 0, false, fmt.Errorf("invalid timeout %q in plugin manifest %s", manifest.Timeout, manifestPath)
+	}
+	return timeout, true, nil
+}
+
+// AggregateResults collects the results of each plugin like actions.AggregateResults, but isolates the
+// plugins from each other. Each plugin runs with its own timeout, and a plugin that fails or times out does
+// not cancel the others. The rules of a failed plugin are reported with error results, and its failure is
+// returned with the results. An error is returned if the rules of a plugin cannot be resolved. When the
+// context is canceled, the plugins that did not return are reported as failed, and the results collected
+// so far are returned with the context error.
+func AggregateResults(ctx context.Context, inputContext *actions.InputContext, plugins map[plugin.ID]policy.Provider, timeouts map[plugin.ID]time.Duration) ([]policy.PVPResult, []PluginFailure, error) {
+	var (
+		mu       sync.Mutex
+		wg       sync.WaitGroup
+		results  []policy.PVPResult
+		failures []PluginFailure
+	)
+	limit := make(chan struct{}, max(inputContext.MaxConcurrency, 1))
+	for pluginID, provider := range plugins {
+		componentTitle, err := inputContext.ProviderTitle(pluginID)
+		if err != nil {
+			if errors.Is(err, actions.ErrMissingProvider) {
+				continue
+			}
+			return nil, nil, err
+		}
+		ruleSets, err := settings.ApplyToComponent(ctx, componentTitle, inputContext.Store(), inputContext.Settings)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to get rule sets for component %s: %w", componentTitle, err)
+		}
+
+		wg.Add(1)
+		go func(pluginID plugin.ID, provider policy.Provider, ruleSets []extensions.RuleSet) {
+			defer wg.Done()
+			limit <- struct{}{}
+			defer func() { <-limit }()
+
+			pluginResults, err := getPluginResults(ctx, provider, ruleSets, timeouts[pluginID])
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				failures = append(failures, PluginFailure{PluginID: pluginID, Err: err})
+				results = append(results, pluginErrorResult(pluginID, ruleSets, err))
+				return
+			}
+			results = append(results, pluginResults)
+		}(pluginID, provider, ruleSets)
+	}
+	wg.Wait()
+
+	sort.Slice(failures, func(i, j int) bool {
+		return failures[i].PluginID < failures[j].PluginID
+	})
+	return results, failures, ctx.Err()
+}
+
+// getPluginResults gets the results of the plugin, which are no longer awaited after the timeout if it is set.
+func getPluginResults(ctx context.Context, provider policy.Provider, ruleSets []extensions.RuleSet, timeout time.Duration) (policy.PVPResult, error) {
+	if ctx.Err() != nil {
+		return policy.PVPResult{}, fmt.Errorf("scan canceled: %w", ctx.Err())
+	}
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+	pluginResults, err := provider.GetResults(ctx, ruleSets)
+	if errors.Is(ctx.Err(), context.Canceled) {
+		return pluginResults, fmt.Errorf("scan canceled: %w", ctx.Err())
+	}
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return pluginResults, fmt.Errorf("no results after %s: %w", timeout, ctx.Err())
+	}
+	return pluginResults, err
+}
+
+// pluginErrorResult returns an error observation for each check of the rules of the failed plugin.
+func pluginErrorResult(pluginID plugin.ID, ruleSets []extensions.RuleSet, pluginErr error) policy.PVPResult {
+	var result policy.PVPResult
+	now := time.Now()
+	for _, ruleSet := range ruleSets {
+		for _, check := range ruleSet.Checks {
+			result.ObservationsByCheck = append(result.ObservationsByCheck, policy.ObservationByCheck{
+				Title:     ruleSet.Rule.ID,
+				CheckID:   check.ID,
+				Methods:   []string{"AUTOMATED"},
+				Collected: now,
+				Subjects: []policy.Subject{
+					{
+						Title:       fmt.Sprintf("Plugin %s", pluginID),
+						Type:        "component",
+						ResourceID:  pluginID.String(),
+						EvaluatedOn: now,
+						Result:      policy.ResultError,
+						Reason:      fmt.Sprintf("plugin %s failed: %v", pluginID, pluginErr),
+					},
+				},
+			})
+		}
+	}
+	return result
+}
+
+// AddPluginFailureProps records in the assessment results metadata the plugins that returned no results.
+func AddPluginFailureProps(assessmentResults *oscalTypes.AssessmentResults, failures []PluginFailure) {
+	if len(failures) == 0 {
+		return
+	}
+	if assessmentResults.Metadata.Props == nil {
+		assessmentResults.Metadata.Props = &[]oscalTypes.Property{}
+	}
+	for _, failure := range failures {
+		*assessmentResults.Metadata.Props = append(*assessmentResults.Metadata.Props, oscalTypes.Property{
+			Name:    failedPluginProp,
+			Value:   failure.PluginID.String(),
+			Ns:      extensions.TrestleNameSpace,
+			Remarks: failure.Err.Error(),
+		})
+	}
+}
+
+// AddAbortedProps records in the assessment results metadata that the scan was canceled before all plugins
+// returned results, so the results are not mistaken for a complete assessment.
+func AddAbortedProps(assessmentResults *oscalTypes.AssessmentResults, cause error) {
+	if assessmentResults.Metadata.Props == nil {
+		assessmentResults.Metadata.Props = &[]oscalTypes.Property{}
+	}
+	*assessmentResults.Metadata.Props = append(*assessmentResults.Metadata.Props, oscalTypes.Property{
+		Name:    abortedAssessmentProp,
+		Value:   "true",
+		Ns:      extensions.TrestleNameSpace,
+		Remarks: fmt.Sprintf("The scan was canceled before all plugins returned results: %v", cause),
+	})
+}
+
+// IsAbortedAssessment returns true if the scan of the assessment results was canceled.
+func IsAbortedAssessment(assessmentResults *oscalTypes.AssessmentResults) bool {
+	if assessmentResults == nil || assessmentResults.Metadata.Props == nil {
+		return false
+	}
+	aborted, found := extensions.GetTrestleProp(abortedAssessmentProp, *assessmentResults.Metadata.Props)
+	return found && aborted.Value == "true"
+}
@@ -0,0 +1,175 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package complytime
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	oscalTypes "github.com/defenseunicorns/go-oscal/src/types/oscal-1-1-3"
+	"github.com/oscal-compass/compliance-to-policy-go/v2/framework/actions"
+	"github.com/oscal-compass/compliance-to-policy-go/v2/plugin"
+	"github.com/oscal-compass/compliance-to-policy-go/v2/policy"
+	"github.com/oscal-compass/oscal-sdk-go/extensions"
+	"github.com/oscal-compass/oscal-sdk-go/settings"
+	"github.com/stretchr/testify/require"
+)
+
+// testStore is a rules.Store with one rule set by component.
+type testStore map[string]extensions.RuleSet
+
+func (s testStore) GetByRuleID(_ context.Context, ruleID string) (extensions.RuleSet, error) {
+	for _, ruleSet := range s {
+		if ruleSet.Rule.ID == ruleID {
+			return ruleSet, nil
+		}
+	}
+	return extensions.RuleSet{}, errors.New("rule not found")
+}
+
+func (s testStore) GetByCheckID(_ context.Context, checkID string) (extensions.RuleSet, error) {
+	for _, ruleSet := range s {
+		for _, check := range ruleSet.Checks {
+			if check.ID == checkID {
+				return ruleSet, nil
+			}
+		}
+	}
+	return extensions.RuleSet{}, errors.New("check not found")
+}
+
+func (s testStore) FindByComponent(_ context.Context, componentID string) ([]extensions.RuleSet, error) {
+	return []extensions.RuleSet{s[componentID]}, nil
+}
+
+// testProvider is a policy.Provider returning the given results and error, after the delay or
+// the cancellation of the context.
+type testProvider struct {
+	results policy.PVPResult
+	err     error
+	delay   time.Duration
+}
+
+func (p testProvider) Configure(context.Context, map[string]string) error { return nil }
+
+func (p testProvider) Generate(context.Context, policy.Policy) error { return nil }
+
+func (p testProvider) GetResults(ctx context.Context, _ policy.Policy) (policy.PVPResult, error) {
+	select {
+	case <-time.After(p.delay):
+		return p.results, p.err
+	case <-ctx.Done():
+		return policy.PVPResult{}, ctx.Err()
+	}
+}
+
+func TestAggregateResults(t *testing.T) {
+	store := testStore{
+		"passing": {Rule: extensions.Rule{ID: "rule-a"}, Checks: []extensions.Check{{ID: "check-a"}}},
+		"failing": {Rule: extensions.Rule{ID: "rule-b"}, Checks: []extensions.Check{{ID: "check-b"}}},
+		"hanging": {Rule: extensions.Rule{ID: "rule-c"}, Checks: []extensions.Check{{ID: "check-c"}}},
+	}
+	inputContext := actions.NewContext(map[plugin.ID]string{
+		"passing": "passing",
+		"failing": "failing",
+		"hanging": "hanging",
+	}, store)
+	inputContext.Settings = settings.NewSettings(map[string]struct{}{"rule-a": {}, "rule-b": {}, "rule-c": {}}, nil)
+
+	passingResults := policy.PVPResult{ObservationsByCheck: []policy.ObservationByCheck{{Title: "rule-a", CheckID: "check-a"}}}
+	plugins := map[plugin.ID]policy.Provider{
+		"passing": testProvider{results: passingResults},
+		"failing": testProvider{err: errors.New("oscap not found")},
+		"hanging": testProvider{delay: time.Minute},
+	}
+	timeouts := map[plugin.ID]time.Duration{"hanging": 10 * time.Millisecond}
+
+	results, failures, err := AggregateResults(context.Background(), inputContext, plugins, timeouts)
+	require.NoError(t, err)
+	require.Len(t, results, 3)
+	require.Contains(t, results, passingResults)
+
+	require.Len(t, failures, 2)
+	require.Equal(t, plugin.ID("failing"), failures[0].PluginID)
+	require.EqualError(t, failures[0].Err, "oscap not found")
+	require.Equal(t, plugin.ID("hanging"), failures[1].PluginID)
+	require.ErrorIs(t, failures[1].Err, context.DeadlineExceeded)
+
+	for _, result := range results {
+		observation := result.ObservationsByCheck[0]
+		if observation.CheckID == "check-a" {
+			continue
+		}
+		require.Len(t, observation.Subjects, 1)
+		require.Equal(t, policy.ResultError, observation.Subjects[0].Result)
+		require.Contains(t, observation.Subjects[0].Reason, "failed")
+	}
+
+	// The plugins that did not return when the scan is canceled are reported as failed.
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	results, failures, err = AggregateResults(ctx, inputContext, plugins, nil)
+	require.ErrorIs(t, err, context.Canceled)
+	require.Len(t, results, 3)
+	require.Len(t, failures, 3)
+	for _, failure := range failures {
+		require.ErrorIs(t, failure.Err, context.Canceled)
+	}
+}
+
+func TestPluginTimeouts(t *testing.T) {
+	tmpDir := t.TempDir()
+	appDir, err := newApplicationDirectory(tmpDir, true)
+	require.NoError(t, err)
+	userConfigRoot := filepath.Join(tmpDir, "config.d")
+	require.NoError(t, os.MkdirAll(userConfigRoot, 0750))
+
+	writeManifest := func(dir, pluginID, manifest string) {
+		manifestPath := filepath.Join(dir, pluginManifestPrefix+pluginID+pluginManifestSuffix)
+		require.NoError(t, os.WriteFile(manifestPath, []byte(manifest), 0600))
+	}
+	writeManifest(appDir.PluginManifestDir(), "openscap", `{"metadata": {"id": "openscap"}, "timeout": "30m"}`)
+	writeManifest(appDir.PluginManifestDir(), "ampel", `{"metadata": {"id": "ampel"}, "timeout": "5m"}`)
+	writeManifest(userConfigRoot, "ampel", `{"metadata": {"id": "ampel"}, "timeout": "10m"}`)
+	writeManifest(appDir.PluginManifestDir(), "opa", `{"metadata": {"id": "opa"}}`)
+
+	timeouts, err := PluginTimeouts(appDir, userConfigRoot, []plugin.ID{"openscap", "ampel", "opa"}, time.Hour)
+	require.NoError(t, err)
+	require.Equal(t, map[plugin.ID]time.Duration{
+		"openscap": 30 * time.Minute,
+		"ampel":    10 * time.Minute,
+		"opa":      time.Hour,
+	}, timeouts)
+
+	writeManifest(appDir.PluginManifestDir(), "opa", `{"metadata": {"id": "opa"}, "timeout": "soon"}`)
+	_, err = PluginTimeouts(appDir, userConfigRoot, []plugin.ID{"opa"}, 0)
+	require.ErrorContains(t, err, `invalid timeout "soon"`)
+}
+
+func TestAddPluginFailureProps(t *testing.T) {
+	ar := &oscalTypes.AssessmentResults{}
+	AddPluginFailureProps(ar, nil)
+	require.Nil(t, ar.Metadata.Props)
+
+	AddPluginFailureProps(ar, []PluginFailure{{PluginID: "openscap", Err: errors.New("oscap not found")}})
+	require.Equal(t, []oscalTypes.Property{{
+		Name:    failedPluginProp,
+		Value:   "openscap",
+		Ns:      extensions.TrestleNameSpace,
+		Remarks: "oscap not found",
+	}}, *ar.Metadata.Props)
+}
+
+func TestAddAbortedProps(t *testing.T) {
+	ar := &oscalTypes.AssessmentResults{}
+	require.False(t, IsAbortedAssessment(ar))
+	require.False(t, IsAbortedAssessment(nil))
+
+	AddAbortedProps(ar, context.Canceled)
+	require.True(t, IsAbortedAssessment(ar))
+	require.Contains(t, (*ar.Metadata.Props)[0].Remarks, "context canceled")
+}
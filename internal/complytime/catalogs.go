@@ -3,8 +3,12 @@
 package complytime
 
 import (
+	"errors"
+	"fmt"
+
 	oscalTypes "github.com/defenseunicorns/go-oscal/src/types/oscal-1-1-3"
 	"github.com/oscal-compass/oscal-sdk-go/models"
+	"github.com/oscal-compass/oscal-sdk-go/settings"
 	"github.com/oscal-compass/oscal-sdk-go/validation"
 )
 
@@ -18,3 +22,48 @@ func LoadCatalogSource(appDir ApplicationDirectory, catalogSource string, valida
 	defer sourceFile.Close()
 	return models.NewCatalog(sourceFile, validator)
 }
+
+// LoadFrameworkCatalog returns the OSCAL catalog imported by the profile of the given framework.
+// The profile is found from the control implementations of the component definitions in the application directory.
+func LoadFrameworkCatalog(appDir ApplicationDirectory, frameworkID string, validator validation.Validator) (*oscalTypes.Catalog, error) {
+	compDefs, err := FindComponentDefinitions(appDir.BundleDir(), validator)
+	if err != nil {
+		return nil, err
+	}
+
+	var profileHref string
+	for _, compDef := range compDefs {
+		if compDef.Components == nil {
+			continue
+		}
+		for _, component := range *compDef.Components {
+			if component.ControlImplementations == nil {
+				continue
+			}
+			for _, implementation := range *component.ControlImplementations {
+				frameworkShortName, found := settings.GetFrameworkShortName(implementation)
+				// If the framework property value match the assessment plan framework property values
+				// this is the correct control source.
+				if found && frameworkShortName == frameworkID {
+					profileHref = implementation.Source
+					break
+				}
+			}
+			if profileHref != "" {
+				break
+			}
+		}
+	}
+	if profileHref == "" {
+		return nil, fmt.Errorf("no control source found for framework %s", frameworkID)
+	}
+
+	profile, err := LoadProfile(appDir, profileHref, validator)
+	if err != nil {
+		return nil, err
+	}
+	if len(profile.Imports) != 1 {
+		return nil, errors.New("profile imports must be one")
+	}
+	return LoadCatalogSource(appDir, profile.Imports[0].Href, validator)
+}
@@ -49,3 +49,15 @@ func TestLoadCatalogSource(t *testing.T) {
 		})
 	}
 }
+
+func TestLoadFrameworkCatalog(t *testing.T) {
+	appDir, err := newApplicationDirectory("testdata", false)
+	require.NoError(t, err)
+
+	catalog, err := LoadFrameworkCatalog(appDir, "example", validation.NoopValidator{})
+	require.NoError(t, err)
+	require.NotNil(t, catalog)
+
+	_, err = LoadFrameworkCatalog(appDir, "unknown", validation.NoopValidator{})
+	require.EqualError(t, err, "no control source found for framework unknown")
+}
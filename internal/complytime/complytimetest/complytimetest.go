@@ -0,0 +1,28 @@
+// SPDX-License-Identifier: Apache-2.0
+
+// Package complytimetest provides helpers for the tests of the packages using the
+// complytime application directory.
+package complytimetest
+
+import (
+	"testing"
+
+	"github.com/adrg/xdg"
+	"github.com/hashicorp/go-hclog"
+	"github.com/stretchr/testify/require"
+
+	"github.com/complytime/complyctl/internal/complytime"
+)
+
+// NewApplicationDirectory returns a development application directory under a temporary
+// data home. The data home is restored when the test completes.
+func NewApplicationDirectory(t *testing.T) complytime.ApplicationDirectory {
+	t.Helper()
+	t.Cleanup(xdg.Reload)
+	t.Setenv(complytime.DevModeEnv, "1")
+	t.Setenv("XDG_DATA_HOME", t.TempDir())
+	xdg.Reload()
+	appDir, err := complytime.NewApplicationDirectory(true, hclog.NewNullLogger())
+	require.NoError(t, err)
+	return appDir
+}
@@ -19,8 +19,10 @@ import (
 	"github.com/oscal-compass/oscal-sdk-go/validation"
 )
 
+// ComponentDefinitionSuffix is the naming scheme of the component definitions found in the bundle directory.
+const ComponentDefinitionSuffix = "component-definition.json"
+
 const (
-	compDefSuffix          = "component-definition.json"
 	ApplicationDir         = "complytime"
 	PluginDir              = "plugins"
 	BundlesDir             = "bundles"
@@ -29,6 +31,8 @@ const (
 	PluginBinaryRootDir    = "/usr/libexec/"
 	DefaultPluginConfigDir = "/etc/complytime/config.d/"
 	Placeholder            = "REPLACE_ME"
+	// DevModeEnv enables the development mode application directory under XDG_DATA_HOME when set to "1".
+	DevModeEnv = "COMPLYTIME_DEV_MODE"
 )
 
 // ErrNoComponentDefinitionsFound returns an error indicated the supplied directory
@@ -57,7 +61,7 @@ type ApplicationDirectory struct {
 // existing.
 func NewApplicationDirectory(create bool, logger hclog.Logger) (ApplicationDirectory, error) {
 	// When running local built complytime for development
-	if os.Getenv("COMPLYTIME_DEV_MODE") == "1" {
+	if os.Getenv(DevModeEnv) == "1" {
 		applicationDirectory := filepath.Join(xdg.DataHome, ApplicationDir)
 		if _, err := os.Stat(applicationDirectory); err != nil {
 			if os.IsNotExist(err) {
@@ -151,7 +155,7 @@ func FindComponentDefinitions(bundleDir string, validator validation.Validator)
 
 	var compDefBundles []oscalTypes.ComponentDefinition
 	for _, item := range items {
-		if !strings.HasSuffix(item.Name(), compDefSuffix) {
+		if !strings.HasSuffix(item.Name(), ComponentDefinitionSuffix) {
 			continue
 		}
 		compDefPath := filepath.Join(bundleDir, item.Name())
@@ -0,0 +1,177 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package complytime
+
+import (
+	"sort"
+
+	oscalTypes "github.com/defenseunicorns/go-oscal/src/types/oscal-1-1-3"
+)
+
+// RuleChange describes the status of a rule in two Assessment Results.
+type RuleChange struct {
+	RuleID    string `json:"ruleId"`
+	OldStatus string `json:"oldStatus,omitempty"`
+	NewStatus string `json:"newStatus,omitempty"`
+}
+
+// SubjectStatusChange describes the result of a single subject in two Assessment Results.
+type SubjectStatusChange struct {
+	Subject   string `json:"subject"`
+	OldResult string `json:"oldResult"`
+	NewResult string `json:"newResult"`
+}
+
+// SubjectChange describes the subjects of a rule that changed between two Assessment Results.
+type SubjectChange struct {
+	RuleID  string                `json:"ruleId"`
+	Added   []string              `json:"added"`
+	Removed []string              `json:"removed"`
+	Changed []SubjectStatusChange `json:"changed"`
+}
+
+// ResultsDiff describes the drift between an older and a newer OSCAL Assessment Results.
+type ResultsDiff struct {
+	NewlyFailedRules    []RuleChange    `json:"newlyFailedRules"`
+	FixedRules          []RuleChange    `json:"fixedRules"`
+	AddedRules          []RuleChange    `json:"addedRules"`
+	RemovedRules        []RuleChange    `json:"removedRules"`
+	SubjectChanges      []SubjectChange `json:"subjectChanges"`
+	NewlyFailedControls []string        `json:"newlyFailedControls"`
+	FixedControls       []string        `json:"fixedControls"`
+	AddedControls       []string        `json:"addedControls"`
+	RemovedControls     []string        `json:"removedControls"`
+}
+
+// HasRegressions returns true if a rule or control that was not failing in the older
+// results is failing in the newer results.
+func (d ResultsDiff) HasRegressions() bool {
+	if len(d.NewlyFailedRules) > 0 || len(d.NewlyFailedControls) > 0 {
+		return true
+	}
+	for _, added := range d.AddedRules {
+		if IsFailingResult(added.NewStatus) {
+			return true
+		}
+	}
+	return false
+}
+
+// IsEmpty returns true if no drift was detected.
+func (d ResultsDiff) IsEmpty() bool {
+	return len(d.NewlyFailedRules) == 0 && len(d.FixedRules) == 0 &&
+		len(d.AddedRules) == 0 && len(d.RemovedRules) == 0 &&
+		len(d.SubjectChanges) == 0 && len(d.NewlyFailedControls) == 0 &&
+		len(d.FixedControls) == 0 && len(d.AddedControls) == 0 &&
+		len(d.RemovedControls) == 0
+}
+
+// DiffAssessmentResults compares two OSCAL Assessment Results from runs of the same assessment
+// and returns the rules, controls, and subjects that changed.
+func DiffAssessmentResults(oldResults, newResults *oscalTypes.AssessmentResults) ResultsDiff {
+	oldIndex := NewResultsIndex(oldResults)
+	newIndex := NewResultsIndex(newResults)
+
+	diff := ResultsDiff{
+		NewlyFailedRules:    []RuleChange{},
+		FixedRules:          []RuleChange{},
+		AddedRules:          []RuleChange{},
+		RemovedRules:        []RuleChange{},
+		SubjectChanges:      []SubjectChange{},
+		NewlyFailedControls: []string{},
+		FixedControls:       []string{},
+		AddedControls:       []string{},
+		RemovedControls:     []string{},
+	}
+
+	for _, ruleID := range newIndex.RuleIDs() {
+		newRule := newIndex.Rules[ruleID]
+		oldRule, found := oldIndex.Rules[ruleID]
+		if !found {
+			diff.AddedRules = append(diff.AddedRules, RuleChange{RuleID: ruleID, NewStatus: newRule.Status()})
+			continue
+		}
+		change := RuleChange{RuleID: ruleID, OldStatus: oldRule.Status(), NewStatus: newRule.Status()}
+		switch {
+		case !IsFailingResult(change.OldStatus) && IsFailingResult(change.NewStatus):
+			diff.NewlyFailedRules = append(diff.NewlyFailedRules, change)
+		case IsFailingResult(change.OldStatus) && !IsFailingResult(change.NewStatus):
+			diff.FixedRules = append(diff.FixedRules, change)
+		}
+		if subjectChange, changed := diffSubjects(ruleID, oldRule.Subjects, newRule.Subjects); changed {
+			diff.SubjectChanges = append(diff.SubjectChanges, subjectChange)
+		}
+	}
+	for _, ruleID := range oldIndex.RuleIDs() {
+		if _, found := newIndex.Rules[ruleID]; !found {
+			diff.RemovedRules = append(diff.RemovedRules, RuleChange{RuleID: ruleID, OldStatus: oldIndex.Rules[ruleID].Status()})
+		}
+	}
+
+	for _, controlID := range newIndex.ControlIDs() {
+		if !oldIndex.ReviewedControls.Has(controlID) {
+			diff.AddedControls = append(diff.AddedControls, controlID)
+			continue
+		}
+		_, oldFailed := oldIndex.FailedControls[controlID]
+		_, newFailed := newIndex.FailedControls[controlID]
+		switch {
+		case !oldFailed && newFailed:
+			diff.NewlyFailedControls = append(diff.NewlyFailedControls, controlID)
+		case oldFailed && !newFailed:
+			diff.FixedControls = append(diff.FixedControls, controlID)
+		}
+	}
+	for _, controlID := range oldIndex.ControlIDs() {
+		if !newIndex.ReviewedControls.Has(controlID) {
+			diff.RemovedControls = append(diff.RemovedControls, controlID)
+		}
+	}
+	return diff
+}
+
+// diffSubjects compares the subjects evaluated for a rule and returns the change if any
+// subject was added, removed, or has a different result.
+func diffSubjects(ruleID string, oldSubjects, newSubjects []SubjectResult) (SubjectChange, bool) {
+	change := SubjectChange{
+		RuleID:  ruleID,
+		Added:   []string{},
+		Removed: []string{},
+		Changed: []SubjectStatusChange{},
+	}
+	oldByKey := make(map[string]SubjectResult)
+	for _, subject := range oldSubjects {
+		oldByKey[subject.Key()] = subject
+	}
+	newByKey := make(map[string]SubjectResult)
+	for _, subject := range newSubjects {
+		newByKey[subject.Key()] = subject
+	}
+
+	for key, newSubject := range newByKey {
+		oldSubject, found := oldByKey[key]
+		if !found {
+			change.Added = append(change.Added, key)
+			continue
+		}
+		if oldSubject.Result != newSubject.Result {
+			change.Changed = append(change.Changed, SubjectStatusChange{
+				Subject:   key,
+				OldResult: oldSubject.Result,
+				NewResult: newSubject.Result,
+			})
+		}
+	}
+	for key := range oldByKey {
+		if _, found := newByKey[key]; !found {
+			change.Removed = append(change.Removed, key)
+		}
+	}
+
+	sort.Strings(change.Added)
+	sort.Strings(change.Removed)
+	sort.Slice(change.Changed, func(i, j int) bool { return change.Changed[i].Subject < change.Changed[j].Subject })
+
+	changed := len(change.Added) > 0 || len(change.Removed) > 0 || len(change.Changed) > 0
+	return change, changed
+}
@@ -0,0 +1,86 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package complytime
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiffAssessmentResults(t *testing.T) {
+	oldResults := newTestAssessmentResults(
+		[]string{"control-1", "control-2", "control-3"},
+		[]testObservation{
+			{uuid: "obs-1", ruleID: "rule-1", subjects: map[string]string{"host-a": "pass"}},
+			{uuid: "obs-2", ruleID: "rule-2", subjects: map[string]string{"host-a": "fail"}},
+			{uuid: "obs-3", ruleID: "rule-3", subjects: map[string]string{"host-a": "pass"}},
+			{uuid: "obs-4", ruleID: "rule-4", subjects: map[string]string{"host-a": "pass", "host-b": "pass"}},
+		},
+		map[string][]string{"control-2": {"obs-2"}},
+	)
+	newResults := newTestAssessmentResults(
+		[]string{"control-1", "control-2", "control-4"},
+		[]testObservation{
+			{uuid: "obs-1", ruleID: "rule-1", subjects: map[string]string{"host-a": "fail"}},
+			{uuid: "obs-2", ruleID: "rule-2", subjects: map[string]string{"host-a": "pass"}},
+			{uuid: "obs-4", ruleID: "rule-4", subjects: map[string]string{"host-a": "pass", "host-c": "pass"}},
+			{uuid: "obs-5", ruleID: "rule-5", subjects: map[string]string{"host-a": "error"}},
+		},
+		map[string][]string{"control-1": {"obs-1"}, "control-4": {"obs-5"}},
+	)
+
+	diff := DiffAssessmentResults(oldResults, newResults)
+	require.Equal(t, []RuleChange{{RuleID: "rule-1", OldStatus: "pass", NewStatus: "fail"}}, diff.NewlyFailedRules)
+	require.Equal(t, []RuleChange{{RuleID: "rule-2", OldStatus: "fail", NewStatus: "pass"}}, diff.FixedRules)
+	require.Equal(t, []RuleChange{{RuleID: "rule-5", NewStatus: "error"}}, diff.AddedRules)
+	require.Equal(t, []RuleChange{{RuleID: "rule-3", OldStatus: "pass"}}, diff.RemovedRules)
+	require.Equal(t, []string{"control-1"}, diff.NewlyFailedControls)
+	require.Equal(t, []string{"control-2"}, diff.FixedControls)
+	require.Equal(t, []string{"control-4"}, diff.AddedControls)
+	require.Equal(t, []string{"control-3"}, diff.RemovedControls)
+
+	require.Len(t, diff.SubjectChanges, 3)
+	require.Equal(t, SubjectChange{
+		RuleID:  "rule-1",
+		Added:   []string{},
+		Removed: []string{},
+		Changed: []SubjectStatusChange{{Subject: "host-a", OldResult: "pass", NewResult: "fail"}},
+	}, diff.SubjectChanges[0])
+	require.Equal(t, SubjectChange{
+		RuleID:  "rule-4",
+		Added:   []string{"host-c"},
+		Removed: []string{"host-b"},
+		Changed: []SubjectStatusChange{},
+	}, diff.SubjectChanges[2])
+
+	require.True(t, diff.HasRegressions())
+	require.False(t, diff.IsEmpty())
+}
+
+func TestDiffAssessmentResultsNoRegressions(t *testing.T) {
+	oldResults := newTestAssessmentResults(
+		[]string{"control-1"},
+		[]testObservation{
+			{uuid: "obs-1", ruleID: "rule-1", subjects: map[string]string{"host-a": "fail"}},
+		},
+		map[string][]string{"control-1": {"obs-1"}},
+	)
+
+	diff := DiffAssessmentResults(oldResults, oldResults)
+	require.True(t, diff.IsEmpty())
+	require.False(t, diff.HasRegressions())
+
+	// A waived failure is not a regression
+	newResults := newTestAssessmentResults(
+		[]string{"control-1"},
+		[]testObservation{
+			{uuid: "obs-1", ruleID: "rule-1", subjects: map[string]string{"host-a": "fail"}},
+			{uuid: "obs-2", ruleID: "rule-2", subjects: map[string]string{"host-a": "fail"}, waived: true},
+		},
+		map[string][]string{"control-1": {"obs-1", "obs-2"}},
+	)
+	diff = DiffAssessmentResults(oldResults, newResults)
+	require.Equal(t, []RuleChange{{RuleID: "rule-2", NewStatus: ResultWaived}}, diff.AddedRules)
+	require.False(t, diff.HasRegressions())
+}
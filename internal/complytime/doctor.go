@@ -0,0 +1,202 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package complytime
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/oscal-compass/oscal-sdk-go/validation"
+
+	"github.com/complytime/complyctl/pkg/doctor"
+)
+
+// pluginDoctorTimeout is the maximum time a plugin can take to run its checks.
+const pluginDoctorTimeout = 30 * time.Second
+
+// Diagnose checks the environment complyctl runs in: the development mode setting, the application
+// directories, the bundle, the discovered plugins and their options, and the prerequisites checked by
+// the plugins themselves.
+func Diagnose(ctx context.Context, appDir ApplicationDirectory, logger hclog.Logger) []doctor.Check {
+	return diagnose(ctx, appDir, DefaultPluginConfigDir, logger)
+}
+
+func diagnose(ctx context.Context, appDir ApplicationDirectory, pluginConfigDir string, logger hclog.Logger) []doctor.Check {
+	checks := []doctor.Check{devModeCheck(appDir)}
+	// The plugin and plugin manifest directories are the same outside of system installs.
+	seen := make(map[string]struct{})
+	for _, dir := range appDir.Dirs() {
+		if _, ok := seen[dir]; ok {
+			continue
+		}
+		seen[dir] = struct{}{}
+		checks = append(checks, directoryCheck(dir))
+	}
+	checks = append(checks, bundleCheck(appDir))
+
+	configChecks, malformedConfigs := pluginConfigChecks(pluginConfigDir)
+	checks = append(checks, configChecks...)
+
+	plugins, err := DiscoverPlugins(appDir)
+	if err != nil {
+		return append(checks, doctor.Fail("plugin discovery", err.Error(),
+			fmt.Sprintf("Install at least one plugin manifest in %s.", appDir.PluginManifestDir())))
+	}
+	if len(plugins) == 0 {
+		return append(checks, doctor.Fail("plugin discovery", fmt.Sprintf("no plugin manifests found in %s", appDir.PluginManifestDir()),
+			fmt.Sprintf("Install at least one plugin manifest named %s<plugin-id>%s.", pluginManifestPrefix, pluginManifestSuffix)))
+	}
+	for _, discovered := range plugins {
+		checks = append(checks, pluginChecks(ctx, discovered, pluginConfigDir, malformedConfigs, logger)...)
+	}
+	return checks
+}
+
+// devModeCheck reports whether the development mode changes the application directory.
+func devModeCheck(appDir ApplicationDirectory) doctor.Check {
+	name := "development mode"
+	switch value := os.Getenv(DevModeEnv); value {
+	case "":
+		return doctor.Pass(name, fmt.Sprintf("disabled, using %s", appDir.AppDir()))
+	case "1":
+		return doctor.Warn(name, fmt.Sprintf("enabled, using %s", appDir.AppDir()),
+			fmt.Sprintf("Unset %s to use the system application directory under %s.", DevModeEnv, DataRootDir))
+	default:
+		return doctor.Warn(name, fmt.Sprintf("%s is set to %q and is ignored, using %s", DevModeEnv, value, appDir.AppDir()),
+			fmt.Sprintf("Set %s=1 to enable development mode or unset it.", DevModeEnv))
+	}
+}
+
+func directoryCheck(dir string) doctor.Check {
+	name := fmt.Sprintf("directory %s", dir)
+	info, err := os.Stat(dir)
+	switch {
+	case os.IsNotExist(err):
+		return doctor.Fail(name, "does not exist", fmt.Sprintf("Reinstall complyctl, or set %s=1 to create the development application directories.", DevModeEnv))
+	case err != nil:
+		return doctor.Fail(name, err.Error(), "Check the permissions of the directory.")
+	case !info.IsDir():
+		return doctor.Fail(name, "is not a directory", "Remove the file and create the directory.")
+	}
+	return doctor.Pass(name, "exists")
+}
+
+func bundleCheck(appDir ApplicationDirectory) doctor.Check {
+	name := "component definitions"
+	compDefs, err := FindComponentDefinitions(appDir.BundleDir(), validation.NewSchemaValidator())
+	if err != nil {
+		return doctor.Fail(name, err.Error(),
+			fmt.Sprintf("Install valid component definitions named *-%s in %s. Run \"complyctl validate\" for details.", ComponentDefinitionSuffix, appDir.BundleDir()))
+	}
+	return doctor.Pass(name, fmt.Sprintf("%d found in %s", len(compDefs), appDir.BundleDir()))
+}
+
+// pluginConfigChecks checks that the user plugin configurations can be parsed and returns the IDs
+// of the plugins with a malformed configuration.
+func pluginConfigChecks(configDir string) ([]doctor.Check, map[string]struct{}) {
+	malformed := make(map[string]struct{})
+	if _, err := os.Stat(configDir); os.IsNotExist(err) {
+		return []doctor.Check{doctor.Pass("plugin configuration", fmt.Sprintf("%s does not exist, using manifest defaults", configDir))}, malformed
+	}
+	configFiles, err := findPluginManifests(configDir)
+	if err != nil {
+		return []doctor.Check{doctor.Fail("plugin configuration", err.Error(), "Check the permissions of the directory.")}, malformed
+	}
+	var checks []doctor.Check
+	for id, fileName := range configFiles {
+		configPath := filepath.Join(configDir, fileName)
+		name := fmt.Sprintf("plugin configuration %s", configPath)
+		if _, err := readPluginManifest(configPath); err != nil {
+			malformed[id] = struct{}{}
+			checks = append(checks, doctor.Fail(name, err.Error(), "Fix the JSON syntax of the file or remove it to use the manifest defaults."))
+			continue
+		}
+		checks = append(checks, doctor.Pass(name, "valid"))
+	}
+	sortChecks(checks)
+	return checks, malformed
+}
+
+// pluginChecks checks the manifest, checksum, and options of a discovered plugin and runs the
+// checks contributed by the plugin.
+func pluginChecks(ctx context.Context, discovered DiscoveredPlugin, pluginConfigDir string, malformedConfigs map[string]struct{}, logger hclog.Logger) []doctor.Check {
+	name := fmt.Sprintf("plugin %s", discovered.ID)
+	if discovered.Err != nil {
+		return []doctor.Check{doctor.Fail(name, discovered.Err.Error(), fmt.Sprintf("Fix the manifest %s or reinstall the plugin.", discovered.ManifestPath))}
+	}
+	checks := []doctor.Check{doctor.Pass(name, fmt.Sprintf("version %s found at %s", discovered.Manifest.Version, discovered.Manifest.ExecutablePath))}
+
+	checksumName := fmt.Sprintf("plugin %s checksum", discovered.ID)
+	if err := VerifyChecksum(discovered.Manifest); err != nil {
+		// The plugin cannot be launched, so its own checks are not run.
+		return append(checks, doctor.Fail(checksumName, err.Error(),
+			fmt.Sprintf("Update the sha256 in %s with the output of \"sha256sum %s\" if the executable is trusted.", discovered.ManifestPath, discovered.Manifest.ExecutablePath)))
+	}
+	checks = append(checks, doctor.Pass(checksumName, "matches the manifest"))
+
+	if _, malformed := malformedConfigs[discovered.ID]; !malformed {
+		checks = append(checks, pluginOptionsCheck(discovered, pluginConfigDir, logger))
+	}
+	return append(checks, runPluginDoctor(ctx, discovered, logger)...)
+}
+
+// pluginOptionsCheck checks that the required plugin options have a value after merging the user configuration.
+func pluginOptionsCheck(discovered DiscoveredPlugin, pluginConfigDir string, logger hclog.Logger) doctor.Check {
+	name := fmt.Sprintf("plugin %s options", discovered.ID)
+	options := NewPluginOptions()
+	// The workspace and profile are set by complyctl at runtime.
+	options.Workspace = "workspace"
+	options.Profile = "profile"
+	if _, err := os.Stat(pluginConfigDir); err == nil {
+		options.UserConfigRoot = pluginConfigDir
+	}
+	selections, err := options.ToMap(discovered.ID, logger)
+	if err == nil {
+		_, err = discovered.Manifest.ResolveOptions(selections, logger)
+	}
+	if err != nil {
+		return doctor.Fail(name, err.Error(),
+			fmt.Sprintf("Set a default for the option in %s.", filepath.Join(pluginConfigDir, pluginManifestPrefix+discovered.ID+pluginManifestSuffix)))
+	}
+	return doctor.Pass(name, fmt.Sprintf("%d options resolved", len(discovered.Manifest.Configuration)))
+}
+
+// runPluginDoctor runs the plugin executable with the doctor command and returns the checks it reports.
+// Plugins that do not support the doctor command are skipped.
+func runPluginDoctor(ctx context.Context, discovered DiscoveredPlugin, logger hclog.Logger) []doctor.Check {
+	ctx, cancel := context.WithTimeout(ctx, pluginDoctorTimeout)
+	defer cancel()
+
+	var stdout bytes.Buffer
+	// The #nosec comment is added with justification that the executable path is resolved
+	// under the plugin directory and its checksum is verified before it is run.
+	cmd := exec.CommandContext(ctx, discovered.Manifest.ExecutablePath, doctor.Command) /* #nosec G204 */
+	cmd.Stdout = &stdout
+	runErr := cmd.Run()
+	if ctx.Err() == context.DeadlineExceeded {
+		return []doctor.Check{doctor.Warn(fmt.Sprintf("plugin %s diagnostics", discovered.ID),
+			fmt.Sprintf("timed out after %s", pluginDoctorTimeout), "Run the plugin with the doctor argument to investigate.")}
+	}
+	checks, err := doctor.Read(stdout.Bytes())
+	if err != nil {
+		logger.Debug(fmt.Sprintf("Plugin %s does not provide diagnostics: %v", discovered.ID, err), "exitErr", runErr)
+		return nil
+	}
+	for i := range checks {
+		checks[i].Name = fmt.Sprintf("%s: %s", discovered.ID, checks[i].Name)
+	}
+	return checks
+}
+
+func sortChecks(checks []doctor.Check) {
+	sort.Slice(checks, func(i, j int) bool {
+		return checks[i].Name < checks[j].Name
+	})
+}
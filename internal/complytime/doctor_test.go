@@ -0,0 +1,70 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package complytime
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/stretchr/testify/require"
+
+	"github.com/complytime/complyctl/pkg/doctor"
+)
+
+func TestDiagnose(t *testing.T) {
+	t.Setenv(DevModeEnv, "")
+	appDir, err := newApplicationDirectory(t.TempDir(), true)
+	require.NoError(t, err)
+	compDef, err := os.ReadFile(testCompDefPath)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(filepath.Join(appDir.BundleDir(), "example-component-definition.json"), compDef, 0600))
+	writeTestPlugin(t, appDir, "myplugin", "#!/bin/sh\necho '[{\"name\": \"tool installed\", \"status\": \"fail\", \"hint\": \"Install the tool.\"}]'\n", "")
+	writeTestPlugin(t, appDir, "tampered", "#!/bin/sh\n", "0000")
+
+	configDir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(configDir, "c2p-tampered-manifest.json"), []byte("{"), 0600))
+
+	checks := diagnose(context.Background(), appDir, configDir, hclog.NewNullLogger())
+	statuses := make(map[string]doctor.Status)
+	for _, check := range checks {
+		statuses[check.Name] = check.Status
+	}
+	require.Equal(t, map[string]doctor.Status{
+		"development mode":                 doctor.StatusPass,
+		"directory " + appDir.AppDir():     doctor.StatusPass,
+		"directory " + appDir.PluginDir():  doctor.StatusPass,
+		"directory " + appDir.BundleDir():  doctor.StatusPass,
+		"directory " + appDir.ControlDir(): doctor.StatusPass,
+		"component definitions":            doctor.StatusPass,
+		"plugin configuration " + filepath.Join(configDir, "c2p-tampered-manifest.json"): doctor.StatusFail,
+		"plugin myplugin":          doctor.StatusPass,
+		"plugin myplugin checksum": doctor.StatusPass,
+		"plugin myplugin options":  doctor.StatusPass,
+		"myplugin: tool installed": doctor.StatusFail,
+		"plugin tampered":          doctor.StatusPass,
+		"plugin tampered checksum": doctor.StatusFail,
+	}, statuses)
+}
+
+func TestDevModeCheck(t *testing.T) {
+	appDir := ApplicationDirectory{appDir: "/usr/share/complytime"}
+	tests := []struct {
+		name       string
+		value      string
+		wantStatus doctor.Status
+	}{
+		{name: "Valid/Unset", value: "", wantStatus: doctor.StatusPass},
+		{name: "Valid/Enabled", value: "1", wantStatus: doctor.StatusWarn},
+		{name: "Invalid/UnexpectedValue", value: "/home/user/complytime", wantStatus: doctor.StatusWarn},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv(DevModeEnv, tt.value)
+			check := devModeCheck(appDir)
+			require.Equal(t, tt.wantStatus, check.Status)
+		})
+	}
+}
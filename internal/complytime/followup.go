@@ -0,0 +1,94 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package complytime
+
+import (
+	"slices"
+
+	oscalTypes "github.com/defenseunicorns/go-oscal/src/types/oscal-1-1-3"
+	"github.com/oscal-compass/oscal-sdk-go/extensions"
+)
+
+// followUpAssessmentProp is the Assessment Plan metadata property recording a re-check of the failed
+// and errored rules of earlier assessment results.
+const followUpAssessmentProp = "Follow_Up_Assessment"
+
+// FollowUpScope returns an assessment scope of the framework that includes only the controls with
+// not-satisfied findings in the assessment results, each with the rules that failed or errored for it.
+// The parameter values of the rules are selected from the activities of the assessment plan the results
+// were collected with, which may be nil to keep the defaults of the framework.
+func FollowUpScope(frameworkID string, assessmentResults *oscalTypes.AssessmentResults, assessmentPlan *oscalTypes.AssessmentPlan) AssessmentScope {
+	index := NewResultsIndex(assessmentResults)
+	activities := planActivities(assessmentPlan)
+
+	scope := NewAssessmentScope(frameworkID)
+	for _, controlID := range SortedKeys(index.FailedControls) {
+		entry := ControlEntry{ControlID: controlID}
+		for _, ruleID := range index.FailedControls[controlID] {
+			entry.IncludeRules = AppendUnique(entry.IncludeRules, ruleID)
+			activity, found := activities[ruleID]
+			if !found {
+				continue
+			}
+			parameters := activityParameters(activity)
+			for _, name := range SortedKeys(parameters) {
+				if !slices.ContainsFunc(entry.SelectParameters, func(selected ParameterEntry) bool { return selected.Name == name }) {
+					entry.SelectParameters = append(entry.SelectParameters, ParameterEntry{Name: name, Value: parameters[name]})
+				}
+			}
+		}
+		scope.IncludeControls = append(scope.IncludeControls, entry)
+	}
+	return scope
+}
+
+// AddFollowUpProps records in the assessment plan metadata that it re-checks only the controls and
+// rules of the follow-up scope.
+func AddFollowUpProps(assessmentPlan *oscalTypes.AssessmentPlan, scope AssessmentScope) {
+	if assessmentPlan.Metadata.Props == nil {
+		assessmentPlan.Metadata.Props = &[]oscalTypes.Property{}
+	}
+	props := []oscalTypes.Property{{
+		Name:    followUpAssessmentProp,
+		Value:   "true",
+		Ns:      extensions.TrestleNameSpace,
+		Remarks: "Only the rules that failed or errored in earlier assessment results are re-checked.",
+	}}
+	var rules []string
+	for _, entry := range scope.IncludeControls {
+		props = append(props, oscalTypes.Property{Name: selectedControlProp, Value: entry.ControlID, Ns: extensions.TrestleNameSpace})
+		for _, rule := range entry.IncludeRules {
+			rules = AppendUnique(rules, rule)
+		}
+	}
+	for _, rule := range rules {
+		props = append(props, oscalTypes.Property{Name: selectedRuleProp, Value: rule, Ns: extensions.TrestleNameSpace})
+	}
+	*assessmentPlan.Metadata.Props = append(*assessmentPlan.Metadata.Props, props...)
+}
+
+// FollowUpSelection returns the controls and rules re-checked by the assessment plan. It returns false
+// if the assessment plan is not a follow-up.
+func FollowUpSelection(assessmentPlan *oscalTypes.AssessmentPlan) (Selection, bool) {
+	if assessmentPlan == nil || assessmentPlan.Metadata.Props == nil {
+		return Selection{}, false
+	}
+	props := *assessmentPlan.Metadata.Props
+	followUp, found := extensions.GetTrestleProp(followUpAssessmentProp, props)
+	if !found || followUp.Value != "true" {
+		return Selection{}, false
+	}
+	var selection Selection
+	for _, prop := range props {
+		if prop.Ns != extensions.TrestleNameSpace {
+			continue
+		}
+		switch prop.Name {
+		case selectedRuleProp:
+			selection.Rules = append(selection.Rules, prop.Value)
+		case selectedControlProp:
+			selection.Controls = append(selection.Controls, prop.Value)
+		}
+	}
+	return selection, true
+}
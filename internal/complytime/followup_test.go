@@ -0,0 +1,77 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package complytime
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFollowUpScope(t *testing.T) {
+	ar := newTestAssessmentResults(
+		[]string{"ac-1", "ac-2", "ac-3"},
+		[]testObservation{
+			{uuid: "obs-1", ruleID: "sshd_disable_root_login", subjects: map[string]string{"host-a": "pass"}},
+			{uuid: "obs-2", ruleID: "sshd_set_idle_timeout", subjects: map[string]string{"host-a": "fail"}},
+			{uuid: "obs-3", ruleID: "service_auditd_enabled", subjects: map[string]string{"host-a": "error"}},
+			{uuid: "obs-4", ruleID: "package_aide_installed", subjects: map[string]string{"host-a": "fail"}, waived: true},
+		},
+		map[string][]string{
+			"ac-1": {"obs-1", "obs-2"},
+			"ac-2": {"obs-2", "obs-3"},
+			"ac-3": {"obs-4"},
+		},
+	)
+	plan := newTestDiffPlan([]string{"ac-1", "ac-2", "ac-3"}, map[string][]string{
+		"sshd_disable_root_login": {"ac-1"},
+		"sshd_set_idle_timeout":   {"ac-1", "ac-2"},
+		"service_auditd_enabled":  {"ac-2"},
+	}, "600")
+
+	require.Equal(t, AssessmentScope{
+		FrameworkID: "example",
+		IncludeControls: []ControlEntry{
+			{
+				ControlID:        "ac-1",
+				IncludeRules:     []string{"sshd_set_idle_timeout"},
+				SelectParameters: []ParameterEntry{{Name: "sshd_set_idle_timeout_var", Value: "600"}},
+			},
+			{
+				ControlID:    "ac-2",
+				IncludeRules: []string{"sshd_set_idle_timeout", "service_auditd_enabled"},
+				SelectParameters: []ParameterEntry{
+					{Name: "sshd_set_idle_timeout_var", Value: "600"},
+					{Name: "service_auditd_enabled_var", Value: "600"},
+				},
+			},
+		},
+	}, FollowUpScope("example", ar, plan))
+
+	// Without an assessment plan, the parameters keep the defaults of the framework.
+	scope := FollowUpScope("example", ar, nil)
+	require.Len(t, scope.IncludeControls, 2)
+	require.Empty(t, scope.IncludeControls[0].SelectParameters)
+}
+
+func TestAddFollowUpProps(t *testing.T) {
+	plan := newTestDiffPlan([]string{"ac-1", "ac-2"}, map[string][]string{}, "600")
+	_, isFollowUp := FollowUpSelection(plan)
+	require.False(t, isFollowUp)
+	_, isFollowUp = FollowUpSelection(nil)
+	require.False(t, isFollowUp)
+
+	AddFollowUpProps(plan, AssessmentScope{
+		FrameworkID: "cis",
+		IncludeControls: []ControlEntry{
+			{ControlID: "ac-1", IncludeRules: []string{"sshd_set_idle_timeout"}},
+			{ControlID: "ac-2", IncludeRules: []string{"sshd_set_idle_timeout", "service_auditd_enabled"}},
+		},
+	})
+	selection, isFollowUp := FollowUpSelection(plan)
+	require.True(t, isFollowUp)
+	require.Equal(t, Selection{
+		Rules:    []string{"sshd_set_idle_timeout", "service_auditd_enabled"},
+		Controls: []string{"ac-1", "ac-2"},
+	}, selection)
+}
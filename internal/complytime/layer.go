@@ -0,0 +1,264 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package complytime
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/goccy/go-yaml"
+)
+
+// scopeRemovePrefix removes a control, rule, parameter selection, or waiver set by the
+// layers before a scope config layer, such as "!ac-2".
+const scopeRemovePrefix = "!"
+
+// ScopeLayer is a scope config file merged into a LayeredScope.
+type ScopeLayer struct {
+	// Path is the path of the scope config file.
+	Path string
+	// Data is the content of the scope config file.
+	Data []byte
+	// Base is the assessment scope merged from the layers before this one.
+	Base AssessmentScope
+}
+
+// LayeredScope is an assessment scope merged from scope config layers in order.
+// Each value of the scope keeps the path of the layer that set it.
+type LayeredScope struct {
+	Scope  AssessmentScope
+	Layers []ScopeLayer
+	// origins holds the path of the layer that set each value of the scope by origin key.
+	origins map[string]string
+}
+
+// LoadLayeredScope reads the scope config files and merges them in order. A scope config
+// extending another one with the extends key is merged after the file it extends, which
+// is resolved relative to the extending file.
+//
+// Later layers add controls, rules, parameter selections, and waivers to the ones set by
+// the layers before them. Controls listed again are merged: a non-empty controlTitle replaces
+// the title, parameter selections replace the values of the same parameters, and rules are added
+// to each rule list. Values prefixed with "!" remove the control, rule, parameter selection, or
+// waiver with the same ID set by the layers before.
+func LoadLayeredScope(paths ...string) (LayeredScope, error) {
+	layered := LayeredScope{origins: make(map[string]string)}
+	for _, path := range paths {
+		if err := layered.load(path, nil); err != nil {
+			return LayeredScope{}, err
+		}
+	}
+	return layered, nil
+}
+
+// load merges the scope config file after the layers it extends. chain holds the files
+// extending it, to detect cycles.
+func (l *LayeredScope) load(path string, chain []string) error {
+	path = filepath.Clean(path)
+	if slices.Contains(chain, path) {
+		return fmt.Errorf("scope config %s extends itself through %s", path, strings.Join(chain, ", "))
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("error reading scope config: %w", err)
+	}
+	var layer AssessmentScope
+	if err := yaml.Unmarshal(data, &layer); err != nil {
+		return fmt.Errorf("error unmarshaling scope config %s: %w", path, err)
+	}
+	if layer.Extends != "" {
+		base := layer.Extends
+		if !filepath.IsAbs(base) {
+			base = filepath.Join(filepath.Dir(path), base)
+		}
+		if err := l.load(base, append(chain, path)); err != nil {
+			return err
+		}
+	}
+	l.Layers = append(l.Layers, ScopeLayer{Path: path, Data: data, Base: l.Scope.clone()})
+	return l.merge(layer, path)
+}
+
+// merge merges the scope config layer into the layered scope.
+func (l *LayeredScope) merge(layer AssessmentScope, path string) error {
+	if layer.FrameworkID != "" {
+		if l.Scope.FrameworkID != "" && l.Scope.FrameworkID != layer.FrameworkID {
+			return fmt.Errorf("scope config %s sets framework %q, but the scope configs before it set %q",
+				path, layer.FrameworkID, l.Scope.FrameworkID)
+		}
+		l.Scope.FrameworkID = layer.FrameworkID
+		l.origins[originKey("frameworkId")] = path
+	}
+
+	for _, entry := range layer.IncludeControls {
+		if controlID, removed := strings.CutPrefix(entry.ControlID, scopeRemovePrefix); removed {
+			l.Scope.IncludeControls = slices.DeleteFunc(l.Scope.IncludeControls, func(merged ControlEntry) bool {
+				return merged.ControlID == controlID
+			})
+			continue
+		}
+		key := originKey("includeControls", entry.ControlID)
+		index := slices.IndexFunc(l.Scope.IncludeControls, func(merged ControlEntry) bool {
+			return merged.ControlID == entry.ControlID
+		})
+		if index < 0 {
+			l.Scope.IncludeControls = append(l.Scope.IncludeControls, ControlEntry{ControlID: entry.ControlID})
+			index = len(l.Scope.IncludeControls) - 1
+			l.origins[key] = path
+		}
+		merged := &l.Scope.IncludeControls[index]
+		if entry.ControlTitle != "" {
+			merged.ControlTitle = entry.ControlTitle
+			l.origins[originKey(key, "controlTitle")] = path
+		}
+		merged.IncludeRules = l.mergeRules(originKey(key, "includeRules"), merged.IncludeRules, entry.IncludeRules, path)
+		merged.ExcludeRules = l.mergeRules(originKey(key, "excludeRules"), merged.ExcludeRules, entry.ExcludeRules, path)
+		merged.WaiveRules = l.mergeRules(originKey(key, "waiveRules"), merged.WaiveRules, entry.WaiveRules, path)
+		for _, parameter := range entry.SelectParameters {
+			if name, removed := strings.CutPrefix(parameter.Name, scopeRemovePrefix); removed {
+				merged.SelectParameters = slices.DeleteFunc(merged.SelectParameters, func(selected ParameterEntry) bool {
+					return selected.Name == name
+				})
+				continue
+			}
+			l.origins[originKey(key, "selectParameters", parameter.Name)] = path
+			if i := slices.IndexFunc(merged.SelectParameters, func(selected ParameterEntry) bool {
+				return selected.Name == parameter.Name
+			}); i >= 0 {
+				merged.SelectParameters[i].Value = parameter.Value
+				continue
+			}
+			merged.SelectParameters = append(merged.SelectParameters, parameter)
+		}
+	}
+
+	l.Scope.GlobalExcludeRules = l.mergeRules("globalExcludeRules", l.Scope.GlobalExcludeRules, layer.GlobalExcludeRules, path)
+	l.Scope.GlobalWaiveRules = l.mergeRules("globalWaiveRules", l.Scope.GlobalWaiveRules, layer.GlobalWaiveRules, path)
+
+	for _, waiver := range layer.Waivers {
+		if rule, removed := strings.CutPrefix(waiver.Rule, scopeRemovePrefix); removed {
+			l.Scope.Waivers = slices.DeleteFunc(l.Scope.Waivers, func(merged Waiver) bool { return merged.Rule == rule })
+			continue
+		}
+		l.origins[originKey("waivers", waiver.Rule)] = path
+		if i := slices.IndexFunc(l.Scope.Waivers, func(merged Waiver) bool { return merged.Rule == waiver.Rule }); i >= 0 {
+			l.Scope.Waivers[i] = waiver
+			continue
+		}
+		l.Scope.Waivers = append(l.Scope.Waivers, waiver)
+	}
+	return nil
+}
+
+// mergeRules adds the rules of the layer to the merged rules, and removes the rules prefixed with "!".
+func (l *LayeredScope) mergeRules(key string, merged, rules []string, path string) []string {
+	for _, rule := range rules {
+		if removed, found := strings.CutPrefix(rule, scopeRemovePrefix); found {
+			merged = slices.DeleteFunc(merged, func(mergedRule string) bool { return mergedRule == removed })
+			continue
+		}
+		if !slices.Contains(merged, rule) {
+			merged = append(merged, rule)
+			l.origins[originKey(key, rule)] = path
+		}
+	}
+	return merged
+}
+
+// ExpandPatterns returns a copy of the layered scope with the control selectors and rule patterns
+// of the scope expanded like AssessmentScope.ExpandPatterns. The expanded values keep the layer
+// of the selector or pattern they were expanded from.
+func (l LayeredScope) ExpandPatterns(controlRules, controlGroups map[string][]string) (LayeredScope, error) {
+	expanded := l
+	expanded.origins = make(map[string]string)
+	scope, err := l.Scope.expandPatterns(controlRules, controlGroups, func(from, to string) {
+		if origin, found := l.origins[from]; found {
+			expanded.origins[to] = origin
+		}
+	})
+	if err != nil {
+		return LayeredScope{}, err
+	}
+	if origin, found := l.origins[originKey("frameworkId")]; found {
+		expanded.origins[originKey("frameworkId")] = origin
+	}
+	for _, waiver := range l.Scope.Waivers {
+		if origin, found := l.origins[originKey("waivers", waiver.Rule)]; found {
+			expanded.origins[originKey("waivers", waiver.Rule)] = origin
+		}
+	}
+	expanded.Scope = scope
+	return expanded, nil
+}
+
+// AnnotatedYAML returns the merged assessment scope as YAML with a comment on each value
+// naming the scope config layer that set it.
+func (l LayeredScope) AnnotatedYAML() ([]byte, error) {
+	comments := yaml.CommentMap{}
+	annotate := func(path, key string) {
+		if origin, found := l.origins[key]; found {
+			comments[path] = []*yaml.Comment{yaml.LineComment(" " + origin)}
+		}
+	}
+	annotate("$.frameworkId", originKey("frameworkId"))
+	for i, entry := range l.Scope.IncludeControls {
+		path := fmt.Sprintf("$.includeControls[%d]", i)
+		key := originKey("includeControls", entry.ControlID)
+		annotate(path+".controlId", key)
+		annotate(path+".controlTitle", originKey(key, "controlTitle"))
+		for _, list := range []struct {
+			name  string
+			rules []string
+		}{
+			{"includeRules", entry.IncludeRules},
+			{"excludeRules", entry.ExcludeRules},
+			{"waiveRules", entry.WaiveRules},
+		} {
+			for j, rule := range list.rules {
+				annotate(fmt.Sprintf("%s.%s[%d]", path, list.name, j), originKey(key, list.name, rule))
+			}
+		}
+		for j, parameter := range entry.SelectParameters {
+			annotate(fmt.Sprintf("%s.selectParameters[%d].value", path, j), originKey(key, "selectParameters", parameter.Name))
+		}
+	}
+	for i, rule := range l.Scope.GlobalExcludeRules {
+		annotate(fmt.Sprintf("$.globalExcludeRules[%d]", i), originKey("globalExcludeRules", rule))
+	}
+	for i, rule := range l.Scope.GlobalWaiveRules {
+		annotate(fmt.Sprintf("$.globalWaiveRules[%d]", i), originKey("globalWaiveRules", rule))
+	}
+	for i, waiver := range l.Scope.Waivers {
+		annotate(fmt.Sprintf("$.waivers[%d].rule", i), originKey("waivers", waiver.Rule))
+	}
+	data, err := yaml.MarshalWithOptions(&l.Scope, yaml.WithComment(comments))
+	if err != nil {
+		return nil, fmt.Errorf("error marshalling yaml content: %w", err)
+	}
+	return data, nil
+}
+
+// clone returns a copy of the assessment scope that does not share lists with it.
+func (a AssessmentScope) clone() AssessmentScope {
+	cloned := a
+	cloned.IncludeControls = nil
+	for _, entry := range a.IncludeControls {
+		entry.IncludeRules = slices.Clone(entry.IncludeRules)
+		entry.ExcludeRules = slices.Clone(entry.ExcludeRules)
+		entry.WaiveRules = slices.Clone(entry.WaiveRules)
+		entry.SelectParameters = slices.Clone(entry.SelectParameters)
+		cloned.IncludeControls = append(cloned.IncludeControls, entry)
+	}
+	cloned.GlobalExcludeRules = slices.Clone(a.GlobalExcludeRules)
+	cloned.GlobalWaiveRules = slices.Clone(a.GlobalWaiveRules)
+	cloned.Waivers = slices.Clone(a.Waivers)
+	return cloned
+}
+
+// originKey returns the key of a value of the assessment scope in the origins of a LayeredScope.
+func originKey(parts ...string) string {
+	return strings.Join(parts, "/")
+}
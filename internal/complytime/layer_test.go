@@ -0,0 +1,146 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package complytime
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const testBaseScopeConfig = `frameworkId: example
+includeControls:
+- controlId: ac-1
+  controlTitle: Policy and Procedures
+  includeRules:
+  - "*"
+  selectParameters:
+  - name: var_sshd_timeout
+    value: "300"
+- controlId: ac-2
+  includeRules:
+  - "*"
+  waiveRules:
+  - service_auditd_enabled
+globalWaiveRules:
+- sshd_disable_root_login
+waivers:
+- rule: sshd_disable_root_login
+  reason: Root login is disabled by the image
+`
+
+const testOverlayScopeConfig = `extends: base.yml
+includeControls:
+- controlId: "!ac-2"
+- controlId: ac-1
+  excludeRules:
+  - sshd_set_idle_timeout
+  selectParameters:
+  - name: var_sshd_timeout
+    value: "600"
+- controlId: AC-*
+  includeRules:
+  - sshd_*
+globalWaiveRules:
+- "!sshd_disable_root_login"
+waivers:
+- rule: "!sshd_disable_root_login"
+`
+
+func writeScopeConfigs(t *testing.T, configs map[string]string) string {
+	dir := t.TempDir()
+	for name, config := range configs {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(config), 0600))
+	}
+	return dir
+}
+
+func TestLoadLayeredScope(t *testing.T) {
+	dir := writeScopeConfigs(t, map[string]string{
+		"base.yml":  testBaseScopeConfig,
+		"prod.yml":  testOverlayScopeConfig,
+		"extra.yml": "includeControls:\n- controlId: ac-3\nglobalExcludeRules:\n- package_aide_installed\n",
+	})
+	basePath, prodPath, extraPath := filepath.Join(dir, "base.yml"), filepath.Join(dir, "prod.yml"), filepath.Join(dir, "extra.yml")
+
+	layered, err := LoadLayeredScope(prodPath, extraPath)
+	require.NoError(t, err)
+	require.Equal(t, AssessmentScope{
+		FrameworkID: "example",
+		IncludeControls: []ControlEntry{
+			{
+				ControlID:        "ac-1",
+				ControlTitle:     "Policy and Procedures",
+				IncludeRules:     []string{"*"},
+				ExcludeRules:     []string{"sshd_set_idle_timeout"},
+				SelectParameters: []ParameterEntry{{Name: "var_sshd_timeout", Value: "600"}},
+			},
+			{ControlID: "AC-*", IncludeRules: []string{"sshd_*"}},
+			{ControlID: "ac-3"},
+		},
+		GlobalExcludeRules: []string{"package_aide_installed"},
+		GlobalWaiveRules:   []string{},
+		Waivers:            []Waiver{},
+	}, layered.Scope)
+
+	require.Len(t, layered.Layers, 3)
+	require.Equal(t, []string{basePath, prodPath, extraPath},
+		[]string{layered.Layers[0].Path, layered.Layers[1].Path, layered.Layers[2].Path})
+	require.Empty(t, layered.Layers[0].Base.FrameworkID)
+	require.Len(t, layered.Layers[1].Base.IncludeControls, 2)
+	// The base of a layer is not changed by the layers after it.
+	require.Equal(t, []ParameterEntry{{Name: "var_sshd_timeout", Value: "300"}}, layered.Layers[1].Base.IncludeControls[0].SelectParameters)
+
+	expanded, err := layered.ExpandPatterns(map[string][]string{
+		"ac-1": {"sshd_disable_root_login", "sshd_set_idle_timeout"},
+		"ac-2": {"service_auditd_enabled"},
+		"ac-3": {"package_aide_installed"},
+	}, nil)
+	require.NoError(t, err)
+	data, err := expanded.AnnotatedYAML()
+	require.NoError(t, err)
+	expected := `frameworkId: example # ` + basePath + `
+includeControls:
+- controlId: ac-1 # ` + basePath + `
+  controlTitle: Policy and Procedures # ` + basePath + `
+  includeRules:
+  - "*" # ` + basePath + `
+  excludeRules:
+  - sshd_set_idle_timeout # ` + prodPath + `
+  selectParameters:
+  - name: var_sshd_timeout
+    value: "600" # ` + prodPath + `
+- controlId: ac-2 # ` + prodPath + `
+  controlTitle: ""
+  includeRules:
+  - sshd_* # ` + prodPath + `
+- controlId: ac-3 # ` + extraPath + `
+  controlTitle: ""
+  includeRules: []
+globalExcludeRules:
+- package_aide_installed # ` + extraPath + `
+`
+	require.Equal(t, expected, string(data))
+}
+
+func TestLoadLayeredScope_Errors(t *testing.T) {
+	dir := writeScopeConfigs(t, map[string]string{
+		"a.yml":     "extends: b.yml\nframeworkId: example\n",
+		"b.yml":     "extends: a.yml\n",
+		"other.yml": "frameworkId: other\n",
+		"base.yml":  testBaseScopeConfig,
+	})
+
+	_, err := LoadLayeredScope(filepath.Join(dir, "a.yml"))
+	require.EqualError(t, err, "scope config "+filepath.Join(dir, "a.yml")+" extends itself through "+
+		filepath.Join(dir, "a.yml")+", "+filepath.Join(dir, "b.yml"))
+
+	_, err = LoadLayeredScope(filepath.Join(dir, "base.yml"), filepath.Join(dir, "other.yml"))
+	require.EqualError(t, err, "scope config "+filepath.Join(dir, "other.yml")+
+		" sets framework \"other\", but the scope configs before it set \"example\"")
+
+	_, err = LoadLayeredScope(filepath.Join(dir, "missing.yml"))
+	require.ErrorContains(t, err, "error reading scope config")
+}
@@ -0,0 +1,181 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package complytime
+
+import (
+	"fmt"
+	"slices"
+
+	oscalTypes "github.com/defenseunicorns/go-oscal/src/types/oscal-1-1-3"
+	"github.com/oscal-compass/oscal-sdk-go/extensions"
+)
+
+// Assessment Results metadata properties recording a partial assessment.
+const (
+	partialAssessmentProp = "Partial_Assessment"
+	selectedRuleProp      = "Selected_Rule"
+	selectedControlProp   = "Selected_Control"
+)
+
+// Selection narrows an assessment to some rules and controls of the assessment plan. Rules and
+// controls can be IDs, globs, or regular expressions between slashes, like in the scope config.
+type Selection struct {
+	Rules    []string
+	Controls []string
+}
+
+// IsEmpty returns true if the selection does not narrow the assessment.
+func (s Selection) IsEmpty() bool {
+	return len(s.Rules) == 0 && len(s.Controls) == 0
+}
+
+// NarrowPlan skips the activities of the assessment plan whose rule is not selected or that are not
+// related to a selected control, and reviews only the controls of the remaining activities. An error
+// is returned if a rule or control of the selection matches nothing assessed by the plan.
+func (s Selection) NarrowPlan(plan *oscalTypes.AssessmentPlan) error {
+	for _, pattern := range append(slices.Clone(s.Rules), s.Controls...) {
+		if isPattern(pattern) {
+			if _, err := matchPattern(pattern, nil, false); err != nil {
+				return err
+			}
+		}
+	}
+
+	matchedRules := make(map[string]bool)
+	matchedControls := make(map[string]bool)
+	reviewedControls := includeControlsSet{}
+	if plan.LocalDefinitions != nil && plan.LocalDefinitions.Activities != nil {
+		for i := range *plan.LocalDefinitions.Activities {
+			activity := &(*plan.LocalDefinitions.Activities)[i]
+			if activity.RelatedControls == nil || isSkippedActivity(*activity) {
+				continue
+			}
+			if !s.narrowActivity(activity, matchedRules, matchedControls) {
+				activity.RelatedControls = nil
+				if activity.Props == nil {
+					activity.Props = &[]oscalTypes.Property{}
+				}
+				*activity.Props = append(*activity.Props, oscalTypes.Property{
+					Name:  extensions.SkippedRulesProperty,
+					Value: "true",
+					Ns:    extensions.TrestleNameSpace,
+				})
+				continue
+			}
+			for _, controlSelection := range activity.RelatedControls.ControlSelections {
+				if controlSelection.IncludeControls == nil {
+					continue
+				}
+				for _, control := range *controlSelection.IncludeControls {
+					reviewedControls.Add(control.ControlId)
+				}
+			}
+		}
+	}
+
+	for _, rule := range s.Rules {
+		if !matchedRules[rule] {
+			return fmt.Errorf("rule %q matches no rule assessed by the assessment plan", rule)
+		}
+	}
+	for _, control := range s.Controls {
+		if !matchedControls[control] {
+			return fmt.Errorf("control %q matches no control assessed by the assessment plan", control)
+		}
+	}
+
+	for i := range plan.ReviewedControls.ControlSelections {
+		filterControlSelection(&plan.ReviewedControls.ControlSelections[i], reviewedControls)
+	}
+	return nil
+}
+
+// narrowActivity returns true if the rule of the activity is selected, and narrows its related controls
+// to the selected ones. The rules and controls of the selection matching the activity are recorded.
+func (s Selection) narrowActivity(activity *oscalTypes.Activity, matchedRules, matchedControls map[string]bool) bool {
+	ruleSelected := len(s.Rules) == 0
+	for _, rule := range s.Rules {
+		if MatchesRule(activity.Title, []string{rule}) {
+			matchedRules[rule] = true
+			ruleSelected = true
+		}
+	}
+	if len(s.Controls) == 0 {
+		return ruleSelected
+	}
+
+	selectedControls := includeControlsSet{}
+	for _, controlSelection := range activity.RelatedControls.ControlSelections {
+		if controlSelection.IncludeControls == nil {
+			continue
+		}
+		for _, control := range *controlSelection.IncludeControls {
+			for _, selector := range s.Controls {
+				if !matchesControl(control.ControlId, selector) {
+					continue
+				}
+				// Controls only match through the activities of selected rules
+				if ruleSelected {
+					matchedControls[selector] = true
+				}
+				selectedControls.Add(control.ControlId)
+			}
+		}
+	}
+	if !ruleSelected || len(selectedControls) == 0 {
+		return false
+	}
+	for i := range activity.RelatedControls.ControlSelections {
+		filterControlSelection(&activity.RelatedControls.ControlSelections[i], selectedControls)
+	}
+	return true
+}
+
+// matchesControl returns true if the control is the selected control or matched by the selected pattern,
+// ignoring case.
+func matchesControl(controlID, selector string) bool {
+	if !isPattern(selector) {
+		return controlID == selector
+	}
+	matches, err := matchPattern(selector, []string{controlID}, true)
+	return err == nil && len(matches) > 0
+}
+
+// isSkippedActivity returns true if the activity is skipped by the assessment scope.
+func isSkippedActivity(activity oscalTypes.Activity) bool {
+	if activity.Props == nil {
+		return false
+	}
+	skipped, found := extensions.GetTrestleProp(extensions.SkippedRulesProperty, *activity.Props)
+	return found && skipped.Value == "true"
+}
+
+// AddPartialProps records in the assessment results metadata that only the rules and controls of the
+// selection were assessed.
+func AddPartialProps(assessmentResults *oscalTypes.AssessmentResults, selection Selection) {
+	if assessmentResults.Metadata.Props == nil {
+		assessmentResults.Metadata.Props = &[]oscalTypes.Property{}
+	}
+	props := []oscalTypes.Property{{
+		Name:    partialAssessmentProp,
+		Value:   "true",
+		Ns:      extensions.TrestleNameSpace,
+		Remarks: "Only the selected rules and controls of the assessment plan were assessed.",
+	}}
+	for _, rule := range selection.Rules {
+		props = append(props, oscalTypes.Property{Name: selectedRuleProp, Value: rule, Ns: extensions.TrestleNameSpace})
+	}
+	for _, control := range selection.Controls {
+		props = append(props, oscalTypes.Property{Name: selectedControlProp, Value: control, Ns: extensions.TrestleNameSpace})
+	}
+	*assessmentResults.Metadata.Props = append(*assessmentResults.Metadata.Props, props...)
+}
+
+// IsPartialAssessment returns true if the assessment results only assess some rules or controls of the plan.
+func IsPartialAssessment(assessmentResults *oscalTypes.AssessmentResults) bool {
+	if assessmentResults == nil || assessmentResults.Metadata.Props == nil {
+		return false
+	}
+	partial, found := extensions.GetTrestleProp(partialAssessmentProp, *assessmentResults.Metadata.Props)
+	return found && partial.Value == "true"
+}
@@ -0,0 +1,115 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package complytime
+
+import (
+	"testing"
+
+	oscalTypes "github.com/defenseunicorns/go-oscal/src/types/oscal-1-1-3"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSelection_NarrowPlan(t *testing.T) {
+	tests := []struct {
+		name         string
+		selection    Selection
+		wantRules    []string
+		wantControls []string
+		wantRelated  map[string][]string
+		expError     string
+	}{
+		{
+			name:         "Valid/Rule",
+			selection:    Selection{Rules: []string{"sshd_set_idle_timeout"}},
+			wantRules:    []string{"sshd_set_idle_timeout"},
+			wantControls: []string{"ac-1", "ac-2"},
+			wantRelated:  map[string][]string{"sshd_set_idle_timeout": {"ac-1", "ac-2"}},
+		},
+		{
+			name:         "Valid/RulePattern",
+			selection:    Selection{Rules: []string{"sshd_*"}},
+			wantRules:    []string{"sshd_disable_root_login", "sshd_set_idle_timeout"},
+			wantControls: []string{"ac-1", "ac-2"},
+			wantRelated: map[string][]string{
+				"sshd_disable_root_login": {"ac-1"},
+				"sshd_set_idle_timeout":   {"ac-1", "ac-2"},
+			},
+		},
+		{
+			name:         "Valid/ControlPattern",
+			selection:    Selection{Controls: []string{"/^AC-2$/"}},
+			wantRules:    []string{"service_auditd_enabled", "sshd_set_idle_timeout"},
+			wantControls: []string{"ac-2"},
+			wantRelated: map[string][]string{
+				"service_auditd_enabled": {"ac-2"},
+				"sshd_set_idle_timeout":  {"ac-2"},
+			},
+		},
+		{
+			name:         "Valid/RuleAndControl",
+			selection:    Selection{Rules: []string{"sshd_set_idle_timeout"}, Controls: []string{"ac-1"}},
+			wantRules:    []string{"sshd_set_idle_timeout"},
+			wantControls: []string{"ac-1"},
+			wantRelated:  map[string][]string{"sshd_set_idle_timeout": {"ac-1"}},
+		},
+		{
+			name:      "Invalid/ControlCase",
+			selection: Selection{Controls: []string{"AC-2"}},
+			expError:  `control "AC-2" matches no control assessed by the assessment plan`,
+		},
+		{
+			name:      "Invalid/SkippedRule",
+			selection: Selection{Rules: []string{"package_aide_installed"}},
+			expError:  `rule "package_aide_installed" matches no rule assessed by the assessment plan`,
+		},
+		{
+			name:      "Invalid/ControlOfUnselectedRule",
+			selection: Selection{Rules: []string{"sshd_disable_root_login"}, Controls: []string{"ac-2"}},
+			expError:  `control "ac-2" matches no control assessed by the assessment plan`,
+		},
+		{
+			name:      "Invalid/Pattern",
+			selection: Selection{Rules: []string{"/[/"}},
+			expError:  "error parsing regexp",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			plan := newTestDiffPlan([]string{"ac-1", "ac-2", "ac-3"}, map[string][]string{
+				"sshd_disable_root_login": {"ac-1"},
+				"sshd_set_idle_timeout":   {"ac-1", "ac-2"},
+				"service_auditd_enabled":  {"ac-2"},
+				"package_aide_installed":  nil,
+			}, "600")
+			err := tt.selection.NarrowPlan(plan)
+			if tt.expError != "" {
+				require.ErrorContains(t, err, tt.expError)
+				return
+			}
+			require.NoError(t, err)
+
+			activities := planActivities(plan)
+			rules := assessedRules(activities)
+			require.ElementsMatch(t, tt.wantRules, rules)
+			require.Equal(t, tt.wantControls, planControlIDs(plan))
+			for rule, controls := range tt.wantRelated {
+				activity := activities[rule]
+				require.Equal(t, controls, planControlIDs(&oscalTypes.AssessmentPlan{ReviewedControls: *activity.RelatedControls}))
+			}
+		})
+	}
+}
+
+func TestAddPartialProps(t *testing.T) {
+	ar := &oscalTypes.AssessmentResults{}
+	require.False(t, IsPartialAssessment(ar))
+	require.False(t, IsPartialAssessment(nil))
+
+	AddPartialProps(ar, Selection{Rules: []string{"sshd_*"}, Controls: []string{"ac-1"}})
+	require.True(t, IsPartialAssessment(ar))
+	require.Len(t, *ar.Metadata.Props, 3)
+	require.Equal(t, selectedRuleProp, (*ar.Metadata.Props)[1].Name)
+	require.Equal(t, "sshd_*", (*ar.Metadata.Props)[1].Value)
+	require.Equal(t, selectedControlProp, (*ar.Metadata.Props)[2].Name)
+	require.Equal(t, "ac-1", (*ar.Metadata.Props)[2].Value)
+}
@@ -0,0 +1,159 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package complytime
+
+import (
+	"slices"
+	"sort"
+
+	oscalTypes "github.com/defenseunicorns/go-oscal/src/types/oscal-1-1-3"
+	"github.com/oscal-compass/oscal-sdk-go/extensions"
+)
+
+// ParameterChange describes a parameter of an activity with different values in two Assessment Plans.
+type ParameterChange struct {
+	Activity  string `json:"activity"`
+	Parameter string `json:"parameter"`
+	OldValue  string `json:"oldValue"`
+	NewValue  string `json:"newValue"`
+}
+
+// PlanDiff describes the changes between an older and a newer OSCAL Assessment Plan of the same framework.
+type PlanDiff struct {
+	AddedControls []string `json:"addedControls"`
+	// RemovedControls are the reviewed controls of the older plan that are not reviewed in the newer plan.
+	RemovedControls []string `json:"removedControls"`
+	// AddedRules are the rules assessed in the newer plan that are not assessed in the older plan.
+	AddedRules   []string `json:"addedRules"`
+	RemovedRules []string `json:"removedRules"`
+	// AddedActivities are the activities defined in the newer plan, whether or not their rule is in scope.
+	AddedActivities   []string          `json:"addedActivities"`
+	RemovedActivities []string          `json:"removedActivities"`
+	ChangedParameters []ParameterChange `json:"changedParameters"`
+}
+
+// IsEmpty returns true if no change was detected.
+func (d PlanDiff) IsEmpty() bool {
+	return len(d.AddedControls) == 0 && len(d.RemovedControls) == 0 &&
+		len(d.AddedRules) == 0 && len(d.RemovedRules) == 0 &&
+		len(d.AddedActivities) == 0 && len(d.RemovedActivities) == 0 &&
+		len(d.ChangedParameters) == 0
+}
+
+// DiffAssessmentPlans compares two OSCAL Assessment Plans and returns the controls, rules,
+// activities, and parameter values that changed.
+func DiffAssessmentPlans(oldPlan, newPlan *oscalTypes.AssessmentPlan) PlanDiff {
+	oldControls, newControls := planControlIDs(oldPlan), planControlIDs(newPlan)
+	oldActivities, newActivities := planActivities(oldPlan), planActivities(newPlan)
+	oldRules, newRules := assessedRules(oldActivities), assessedRules(newActivities)
+
+	diff := PlanDiff{ChangedParameters: []ParameterChange{}}
+	diff.AddedControls, diff.RemovedControls = diffIDs(oldControls, newControls)
+	diff.AddedRules, diff.RemovedRules = diffIDs(oldRules, newRules)
+	diff.AddedActivities, diff.RemovedActivities = diffIDs(SortedKeys(oldActivities), SortedKeys(newActivities))
+
+	for _, title := range SortedKeys(newActivities) {
+		oldActivity, found := oldActivities[title]
+		if !found {
+			continue
+		}
+		oldValues := activityParameters(oldActivity)
+		newValues := activityParameters(newActivities[title])
+		for _, parameter := range SortedKeys(newValues) {
+			oldValue, found := oldValues[parameter]
+			if found && oldValue != newValues[parameter] {
+				diff.ChangedParameters = append(diff.ChangedParameters, ParameterChange{
+					Activity:  title,
+					Parameter: parameter,
+					OldValue:  oldValue,
+					NewValue:  newValues[parameter],
+				})
+			}
+		}
+	}
+	return diff
+}
+
+// planControlIDs returns the reviewed controls of the assessment plan.
+func planControlIDs(plan *oscalTypes.AssessmentPlan) []string {
+	var controlIDs []string
+	if plan == nil {
+		return controlIDs
+	}
+	for _, controlSelection := range plan.ReviewedControls.ControlSelections {
+		if controlSelection.IncludeControls == nil {
+			continue
+		}
+		for _, control := range *controlSelection.IncludeControls {
+			controlIDs = AppendUnique(controlIDs, control.ControlId)
+		}
+	}
+	return controlIDs
+}
+
+// planActivities returns the activities of the assessment plan by title.
+func planActivities(plan *oscalTypes.AssessmentPlan) map[string]oscalTypes.Activity {
+	activities := make(map[string]oscalTypes.Activity)
+	if plan == nil || plan.LocalDefinitions == nil || plan.LocalDefinitions.Activities == nil {
+		return activities
+	}
+	for _, activity := range *plan.LocalDefinitions.Activities {
+		if activity.Title != "" {
+			activities[activity.Title] = activity
+		}
+	}
+	return activities
+}
+
+// assessedRules returns the rules of the activities that are not skipped by the assessment scope.
+func assessedRules(activities map[string]oscalTypes.Activity) []string {
+	var rules []string
+	for title, activity := range activities {
+		if activity.RelatedControls != nil && !isSkippedActivity(activity) {
+			rules = append(rules, title)
+		}
+	}
+	return rules
+}
+
+// activityParameters returns the values of the test parameters of the activity by parameter name.
+func activityParameters(activity oscalTypes.Activity) map[string]string {
+	parameters := make(map[string]string)
+	if activity.Props == nil {
+		return parameters
+	}
+	for _, prop := range *activity.Props {
+		if prop.Class == extensions.TestParameterClass {
+			parameters[prop.Name] = prop.Value
+		}
+	}
+	return parameters
+}
+
+// diffIDs returns the sorted IDs that were added to and removed from the older IDs.
+func diffIDs(oldIDs, newIDs []string) (added, removed []string) {
+	added, removed = []string{}, []string{}
+	for _, id := range newIDs {
+		if !slices.Contains(oldIDs, id) {
+			added = AppendUnique(added, id)
+		}
+	}
+	for _, id := range oldIDs {
+		if !slices.Contains(newIDs, id) {
+			removed = AppendUnique(removed, id)
+		}
+	}
+	sort.Strings(added)
+	sort.Strings(removed)
+	return added, removed
+}
+
+// SortedKeys returns the sorted keys of the map.
+func SortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
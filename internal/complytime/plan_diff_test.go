@@ -0,0 +1,79 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package complytime
+
+import (
+	"testing"
+
+	oscalTypes "github.com/defenseunicorns/go-oscal/src/types/oscal-1-1-3"
+	"github.com/oscal-compass/oscal-sdk-go/extensions"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestDiffPlan returns an assessment plan reviewing the controls with an activity for each rule.
+// Rules without controls are skipped, and each rule has a single parameter with the given value.
+func newTestDiffPlan(controls []string, rules map[string][]string, parameterValue string) *oscalTypes.AssessmentPlan {
+	var includeControls []oscalTypes.AssessedControlsSelectControlById
+	for _, control := range controls {
+		includeControls = append(includeControls, oscalTypes.AssessedControlsSelectControlById{ControlId: control})
+	}
+	var activities []oscalTypes.Activity
+	for _, rule := range SortedKeys(rules) {
+		activity := oscalTypes.Activity{
+			Title: rule,
+			Props: &[]oscalTypes.Property{{Name: rule + "_var", Value: parameterValue, Class: extensions.TestParameterClass}},
+		}
+		if len(rules[rule]) == 0 {
+			*activity.Props = append(*activity.Props, oscalTypes.Property{
+				Name: extensions.SkippedRulesProperty, Value: "true", Ns: extensions.TrestleNameSpace,
+			})
+		} else {
+			var related []oscalTypes.AssessedControlsSelectControlById
+			for _, control := range rules[rule] {
+				related = append(related, oscalTypes.AssessedControlsSelectControlById{ControlId: control})
+			}
+			activity.RelatedControls = &oscalTypes.ReviewedControls{
+				ControlSelections: []oscalTypes.AssessedControls{{IncludeControls: &related}},
+			}
+		}
+		activities = append(activities, activity)
+	}
+	return &oscalTypes.AssessmentPlan{
+		ReviewedControls: oscalTypes.ReviewedControls{
+			ControlSelections: []oscalTypes.AssessedControls{{IncludeControls: &includeControls}},
+		},
+		LocalDefinitions: &oscalTypes.LocalDefinitions{Activities: &activities},
+	}
+}
+
+func TestDiffAssessmentPlans(t *testing.T) {
+	oldPlan := newTestDiffPlan([]string{"ac-1", "ac-2"}, map[string][]string{
+		"sshd_disable_root_login": {"ac-1"},
+		"sshd_set_idle_timeout":   {"ac-1"},
+		"service_auditd_enabled":  {"ac-2"},
+		"package_aide_installed":  nil,
+	}, "300")
+	newPlan := newTestDiffPlan([]string{"ac-1", "ac-3"}, map[string][]string{
+		"sshd_disable_root_login": {"ac-1"},
+		"sshd_set_idle_timeout":   nil,
+		"package_aide_installed":  {"ac-3"},
+		"accounts_tmout":          {"ac-3"},
+	}, "600")
+
+	require.Equal(t, PlanDiff{
+		AddedControls:     []string{"ac-3"},
+		RemovedControls:   []string{"ac-2"},
+		AddedRules:        []string{"accounts_tmout", "package_aide_installed"},
+		RemovedRules:      []string{"service_auditd_enabled", "sshd_set_idle_timeout"},
+		AddedActivities:   []string{"accounts_tmout"},
+		RemovedActivities: []string{"service_auditd_enabled"},
+		ChangedParameters: []ParameterChange{
+			{Activity: "package_aide_installed", Parameter: "package_aide_installed_var", OldValue: "300", NewValue: "600"},
+			{Activity: "sshd_disable_root_login", Parameter: "sshd_disable_root_login_var", OldValue: "300", NewValue: "600"},
+			{Activity: "sshd_set_idle_timeout", Parameter: "sshd_set_idle_timeout_var", OldValue: "300", NewValue: "600"},
+		},
+	}, DiffAssessmentPlans(oldPlan, newPlan))
+
+	diff := DiffAssessmentPlans(oldPlan, oldPlan)
+	require.True(t, diff.IsEmpty())
+}
@@ -4,11 +4,16 @@ package complytime
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
+	"sort"
+	"strings"
 
 	"github.com/hashicorp/go-hclog"
 	"github.com/oscal-compass/compliance-to-policy-go/v2/framework"
@@ -17,6 +22,14 @@ import (
 	"github.com/oscal-compass/compliance-to-policy-go/v2/policy"
 )
 
+const (
+	pluginManifestPrefix = "c2p-"
+	pluginManifestSuffix = "-manifest.json"
+)
+
+// ErrChecksumMismatch is returned when the plugin executable does not match the manifest checksum.
+var ErrChecksumMismatch = errors.New("checksum mismatch")
+
 // PluginOptions defines global options all complytime plugins should
 // support.
 type PluginOptions struct {
@@ -101,26 +114,32 @@ func (p PluginOptions) ToMap(pluginId string, logger hclog.Logger) (map[string]s
 	return selections, nil
 }
 
-// Plugins launches and configures plugins with the given complytime global options. This function returns the plugin map with the
-// launched plugins, a plugin cleanup function, and an error. The cleanup function should be used if it is not nil.
-func Plugins(manager *framework.PluginManager, inputs *actions.InputContext, selections PluginOptions, logger hclog.Logger) (map[plugin.ID]policy.Provider, func(), error) {
-	manifests, err := manager.FindRequestedPlugins(inputs.RequestedProviders())
-	if err != nil {
-		return nil, nil, err
-	}
-
+// PluginSelections returns the configuration selections for the given plugin. The user configuration
+// is read from the DefaultPluginConfigDir when the selections do not set a UserConfigRoot.
+func PluginSelections(pluginId string, selections PluginOptions, logger hclog.Logger) (map[string]string, error) {
 	if selections.UserConfigRoot == "" {
 		if _, err := os.Stat(DefaultPluginConfigDir); err == nil {
 			selections.UserConfigRoot = DefaultPluginConfigDir
 		}
 	}
 	if err := selections.Validate(); err != nil {
-		return nil, nil, fmt.Errorf("failed plugin config validation: %w", err)
+		return nil, fmt.Errorf("failed plugin config validation: %w", err)
+	}
+	return selections.ToMap(pluginId, logger)
+}
+
+// Plugins launches and configures plugins with the given complytime global options. This function returns the plugin map with the
+// launched plugins, a plugin cleanup function, and an error. The cleanup function should be used if it is not nil, including when
+// the context is canceled, so the plugin processes are stopped.
+func Plugins(ctx context.Context, manager *framework.PluginManager, inputs *actions.InputContext, selections PluginOptions, logger hclog.Logger) (map[plugin.ID]policy.Provider, func(), error) {
+	manifests, err := manager.FindRequestedPlugins(inputs.RequestedProviders())
+	if err != nil {
+		return nil, nil, err
 	}
 
 	pluginSelectionsMap := make(map[plugin.ID]map[string]string)
 	for pluginId := range manifests {
-		selectionsMap, err := selections.ToMap(pluginId.String(), logger)
+		selectionsMap, err := PluginSelections(pluginId.String(), selections, logger)
 		if err != nil {
 			return nil, nil, err
 		}
@@ -129,10 +148,108 @@ func Plugins(manager *framework.PluginManager, inputs *actions.InputContext, sel
 	getSelections := func(pluginId plugin.ID) map[string]string {
 		return pluginSelectionsMap[pluginId]
 	}
-	plugins, err := manager.LaunchPolicyPlugins(context.Background(), manifests, getSelections)
+	plugins, err := manager.LaunchPolicyPlugins(ctx, manifests, getSelections)
 	// Plugin subprocess has now been launched; cleanup always required below
 	if err != nil {
 		return nil, manager.Clean, err
 	}
 	return plugins, manager.Clean, nil
 }
+
+// DiscoveredPlugin is a plugin manifest found in the plugin manifest directory.
+type DiscoveredPlugin struct {
+	// ID is the plugin ID from the manifest file name.
+	ID string
+	// ManifestPath is the location of the manifest file.
+	ManifestPath string
+	Manifest     plugin.Manifest
+	// Err is set when the manifest cannot be read or the plugin executable
+	// cannot be resolved.
+	Err error
+}
+
+// DiscoverPlugins reads every plugin manifest in the plugin manifest directory and resolves the
+// plugin executables under the plugin directory. Unlike the C2P plugin discovery, an invalid manifest
+// does not fail the discovery. It is returned with its error so it can be reported with the valid ones.
+func DiscoverPlugins(appDir ApplicationDirectory) ([]DiscoveredPlugin, error) {
+	manifestFiles, err := findPluginManifests(appDir.PluginManifestDir())
+	if err != nil {
+		return nil, err
+	}
+	var discovered []DiscoveredPlugin
+	for id, fileName := range manifestFiles {
+		discoveredPlugin := DiscoveredPlugin{
+			ID:           id,
+			ManifestPath: filepath.Join(appDir.PluginManifestDir(), fileName),
+		}
+		discoveredPlugin.Manifest, discoveredPlugin.Err = readPluginManifest(discoveredPlugin.ManifestPath)
+		if discoveredPlugin.Err == nil {
+			manifestID := discoveredPlugin.Manifest.ID
+			if !manifestID.Validate() || manifestID.String() != id {
+				discoveredPlugin.Err = fmt.Errorf("invalid plugin id %q in manifest %s", manifestID, fileName)
+			} else {
+				discoveredPlugin.Err = discoveredPlugin.Manifest.ResolvePath(appDir.PluginDir())
+			}
+		}
+		discovered = append(discovered, discoveredPlugin)
+	}
+	sort.Slice(discovered, func(i, j int) bool {
+		return discovered[i].ID < discovered[j].ID
+	})
+	return discovered, nil
+}
+
+// VerifyChecksum compares the sha256 checksum of the plugin executable with the
+// checksum in the manifest. The manifest executable path must be resolved.
+func VerifyChecksum(manifest plugin.Manifest) error {
+	if manifest.Checksum == "" {
+		return fmt.Errorf("%w: manifest for plugin %s has no sha256 checksum", ErrChecksumMismatch, manifest.ID)
+	}
+	file, err := os.Open(manifest.ExecutablePath)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	hash := sha256.New()
+	if _, err := io.Copy(hash, file); err != nil {
+		return fmt.Errorf("failed to compute checksum of %s: %w", manifest.ExecutablePath, err)
+	}
+	actual := hex.EncodeToString(hash.Sum(nil))
+	if !strings.EqualFold(actual, manifest.Checksum) {
+		return fmt.Errorf("%w: manifest for plugin %s has %s, executable %s has %s",
+			ErrChecksumMismatch, manifest.ID, manifest.Checksum, manifest.ExecutablePath, actual)
+	}
+	return nil
+}
+
+// findPluginManifests returns the manifest file names in the given directory by plugin ID.
+// The manifest file names follow the C2P naming scheme c2p-$PLUGIN-ID-manifest.json.
+func findPluginManifests(manifestDir string) (map[string]string, error) {
+	items, err := os.ReadDir(manifestDir)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read plugin manifest directory %s: %w", manifestDir, err)
+	}
+	manifests := make(map[string]string)
+	for _, item := range items {
+		name := item.Name()
+		if item.IsDir() || !strings.HasPrefix(name, pluginManifestPrefix) || !strings.HasSuffix(name, pluginManifestSuffix) {
+			continue
+		}
+		manifests[strings.TrimSuffix(strings.TrimPrefix(name, pluginManifestPrefix), pluginManifestSuffix)] = name
+	}
+	return manifests, nil
+}
+
+// readPluginManifest reads a plugin manifest from a JSON file.
+func readPluginManifest(manifestPath string) (plugin.Manifest, error) {
+	var manifest plugin.Manifest
+	manifestFile, err := os.Open(filepath.Clean(manifestPath))
+	if err != nil {
+		return manifest, err
+	}
+	defer manifestFile.Close()
+	if err := json.NewDecoder(manifestFile).Decode(&manifest); err != nil {
+		return manifest, fmt.Errorf("failed to parse plugin manifest %s: %w", manifestPath, err)
+	}
+	return manifest, nil
+}
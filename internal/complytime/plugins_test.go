@@ -3,10 +3,15 @@
 package complytime
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"os"
 	"path/filepath"
 	"testing"
 
 	"github.com/hashicorp/go-hclog"
+	"github.com/oscal-compass/compliance-to-policy-go/v2/plugin"
 	"github.com/stretchr/testify/require"
 )
 
@@ -73,3 +78,54 @@ func TestPluginOptions(t *testing.T) {
 		})
 	}
 }
+
+// writeTestPlugin writes an executable plugin script and its manifest to the application directory.
+// The checksum of the script is used when the given checksum is empty.
+func writeTestPlugin(t *testing.T, appDir ApplicationDirectory, id, script, checksum string) {
+	executablePath := filepath.Join(appDir.PluginDir(), id)
+	require.NoError(t, os.WriteFile(executablePath, []byte(script), 0700)) // #nosec G306
+	if checksum == "" {
+		sum := sha256.Sum256([]byte(script))
+		checksum = hex.EncodeToString(sum[:])
+	}
+	manifest := plugin.Manifest{
+		Metadata: plugin.Metadata{
+			ID:      plugin.ID(id),
+			Version: "0.0.1",
+			Types:   []string{"pvp"},
+		},
+		ExecutablePath: id,
+		Checksum:       checksum,
+	}
+	data, err := json.Marshal(manifest)
+	require.NoError(t, err)
+	manifestPath := filepath.Join(appDir.PluginManifestDir(), pluginManifestPrefix+id+pluginManifestSuffix)
+	require.NoError(t, os.WriteFile(manifestPath, data, 0600))
+}
+
+func TestDiscoverPlugins(t *testing.T) {
+	appDir, err := newApplicationDirectory(t.TempDir(), true)
+	require.NoError(t, err)
+	writeTestPlugin(t, appDir, "myplugin", "#!/bin/sh\n", "")
+	writeTestPlugin(t, appDir, "badsum", "#!/bin/sh\n", "0000")
+	require.NoError(t, os.WriteFile(filepath.Join(appDir.PluginManifestDir(), "c2p-broken-manifest.json"), []byte("{"), 0600))
+
+	plugins, err := DiscoverPlugins(appDir)
+	require.NoError(t, err)
+	require.Len(t, plugins, 3)
+
+	require.Equal(t, "badsum", plugins[0].ID)
+	require.NoError(t, plugins[0].Err)
+	require.ErrorIs(t, VerifyChecksum(plugins[0].Manifest), ErrChecksumMismatch)
+
+	require.Equal(t, "broken", plugins[1].ID)
+	require.ErrorContains(t, plugins[1].Err, "failed to parse plugin manifest")
+
+	require.Equal(t, "myplugin", plugins[2].ID)
+	require.NoError(t, plugins[2].Err)
+	require.Equal(t, filepath.Join(appDir.PluginDir(), "myplugin"), plugins[2].Manifest.ExecutablePath)
+	require.NoError(t, VerifyChecksum(plugins[2].Manifest))
+
+	_, err = DiscoverPlugins(ApplicationDirectory{pluginManifestDir: "doesnotexist"})
+	require.EqualError(t, err, "unable to read plugin manifest directory doesnotexist: open doesnotexist: no such file or directory")
+}
@@ -0,0 +1,496 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package complytime
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/defenseunicorns/go-oscal/src/pkg/uuid"
+	oscalTypes "github.com/defenseunicorns/go-oscal/src/types/oscal-1-1-3"
+	"github.com/goccy/go-yaml"
+	"github.com/oscal-compass/oscal-sdk-go/extensions"
+)
+
+// Remediation types generated by plugins.
+const (
+	RemediationBash      = "bash"
+	RemediationAnsible   = "ansible"
+	RemediationBlueprint = "blueprint"
+)
+
+// remediationDir is the directory of a plugin workspace holding the generated remediations.
+const remediationDir = "remediations"
+
+// Properties recorded on the risk responses of applied remediations.
+const (
+	remediationTypeProp   = "Remediation_Type"
+	remediationStatusProp = "Remediation_Status"
+)
+
+// Statuses of the remediations recorded in assessment results.
+const (
+	RemediationApplied = "applied"
+	RemediationFailed  = "failed"
+)
+
+// ErrNoRemediation is returned when no remediation of the requested type exists for the selected rules.
+var ErrNoRemediation = errors.New("no remediation available")
+
+var (
+	// xccdfRulePattern matches XCCDF 1.2 rule IDs and captures the rule short name.
+	xccdfRulePattern = regexp.MustCompile(`^xccdf_[^_]+_rule_(.+)$`)
+	bashBeginPattern = regexp.MustCompile(`^# BEGIN fix .* for '([^']+)'$`)
+	bashEndPattern   = regexp.MustCompile(`^# END fix for '([^']+)'$`)
+	bashSeparator    = regexp.MustCompile(`^#{10,}$`)
+)
+
+// remediationTypes maps the file extensions of generated remediations to their type.
+var remediationTypes = map[string]string{
+	".sh":   RemediationBash,
+	".yml":  RemediationAnsible,
+	".yaml": RemediationAnsible,
+	".toml": RemediationBlueprint,
+}
+
+// RemediationFile is a remediation generated by a plugin in the workspace.
+type RemediationFile struct {
+	Type   string
+	Plugin string
+	Path   string
+	fixes  []remediationFix
+	// header is the bash script content preceding the fixes.
+	header string
+	// plays are the ansible plays with their tasks removed.
+	plays []yaml.MapSlice
+}
+
+// remediationFix is the fix of a single rule in a remediation file.
+type remediationFix struct {
+	// ids identify the rule of the fix, such as the XCCDF rule ID or the ansible task tags.
+	ids     []string
+	content string
+	play    int
+	task    any
+}
+
+// PlanRules returns the check IDs of the rules in scope of the assessment plan by rule ID.
+func PlanRules(plan *oscalTypes.AssessmentPlan) map[string][]string {
+	rules := make(map[string][]string)
+	if plan.LocalDefinitions == nil || plan.LocalDefinitions.Activities == nil {
+		return rules
+	}
+	for _, activity := range *plan.LocalDefinitions.Activities {
+		if activity.Title == "" {
+			continue
+		}
+		if activity.Props != nil {
+			if skipped, found := extensions.GetTrestleProp(extensions.SkippedRulesProperty, *activity.Props); found && skipped.Value == "true" {
+				continue
+			}
+		}
+		checkIDs := []string{}
+		if activity.Steps != nil {
+			for _, step := range *activity.Steps {
+				checkIDs = append(checkIDs, step.Title)
+			}
+		}
+		rules[activity.Title] = checkIDs
+	}
+	return rules
+}
+
+// FindRemediations returns the remediations generated by plugins in the workspace.
+func FindRemediations(workspace string) ([]RemediationFile, error) {
+	matches, err := filepath.Glob(filepath.Join(workspace, "*", remediationDir, "*"))
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(matches)
+	var files []RemediationFile
+	for _, match := range matches {
+		remediationType, supported := remediationTypes[filepath.Ext(match)]
+		if !supported {
+			continue
+		}
+		file := RemediationFile{
+			Type:   remediationType,
+			Plugin: filepath.Base(filepath.Dir(filepath.Dir(match))),
+			Path:   match,
+		}
+		data, err := os.ReadFile(filepath.Clean(match))
+		if err != nil {
+			return nil, err
+		}
+		switch remediationType {
+		case RemediationBash:
+			err = file.parseBash(data)
+		case RemediationAnsible:
+			err = file.parseAnsible(data)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse remediation %s: %w", match, err)
+		}
+		files = append(files, file)
+	}
+	return files, nil
+}
+
+// parseBash splits a bash remediation script into the fixes of each XCCDF rule.
+func (r *RemediationFile) parseBash(data []byte) error {
+	var header, block []string
+	var current, previous string
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 0, 64*1024), 10*1024*1024)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if match := bashBeginPattern.FindStringSubmatch(line); match != nil {
+			if current != "" {
+				return fmt.Errorf("fix for %s is not terminated", current)
+			}
+			current = match[1]
+			block = []string{line}
+			// The separator preceding the BEGIN marker belongs to the fix.
+			if bashSeparator.MatchString(previous) {
+				block = []string{previous, line}
+				if len(r.fixes) == 0 {
+					header = header[:len(header)-1]
+				}
+			}
+			continue
+		}
+		if current == "" {
+			// Only the content preceding the first fix is kept as header.
+			if len(r.fixes) == 0 {
+				header = append(header, line)
+			}
+			previous = line
+			continue
+		}
+		block = append(block, line)
+		if match := bashEndPattern.FindStringSubmatch(line); match != nil && match[1] == current {
+			r.fixes = append(r.fixes, remediationFix{ids: fixIDs(current), content: strings.Join(block, "\n") + "\n"})
+			current = ""
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return err
+	}
+	if current != "" {
+		return fmt.Errorf("fix for %s is not terminated", current)
+	}
+	r.header = strings.TrimRight(strings.Join(header, "\n"), "\n") + "\n\n"
+	return nil
+}
+
+// parseAnsible splits an ansible playbook into its tasks, identified by their tags.
+func (r *RemediationFile) parseAnsible(data []byte) error {
+	var plays []yaml.MapSlice
+	if err := yaml.UnmarshalWithOptions(data, &plays, yaml.UseOrderedMap()); err != nil {
+		return errors.New(yaml.FormatError(err, false, false))
+	}
+	for i, play := range plays {
+		var kept yaml.MapSlice
+		for _, item := range play {
+			if item.Key != "tasks" {
+				kept = append(kept, item)
+				continue
+			}
+			tasks, ok := item.Value.([]any)
+			if !ok {
+				return fmt.Errorf("tasks of play %d are not a list", i+1)
+			}
+			for _, task := range tasks {
+				r.fixes = append(r.fixes, remediationFix{ids: taskTags(task), play: i, task: task})
+			}
+		}
+		r.plays = append(r.plays, kept)
+	}
+	return nil
+}
+
+// taskTags returns the tags of an ansible task.
+func taskTags(task any) []string {
+	taskMap, ok := task.(yaml.MapSlice)
+	if !ok {
+		return nil
+	}
+	var tags []string
+	for _, item := range taskMap {
+		if item.Key != "tags" {
+			continue
+		}
+		switch value := item.Value.(type) {
+		case string:
+			tags = append(tags, value)
+		case []any:
+			for _, tag := range value {
+				tags = append(tags, fmt.Sprint(tag))
+			}
+		}
+	}
+	return tags
+}
+
+// fixIDs returns the IDs identifying the fix of an XCCDF rule.
+func fixIDs(ruleID string) []string {
+	if match := xccdfRulePattern.FindStringSubmatch(ruleID); match != nil {
+		return []string{ruleID, match[1]}
+	}
+	return []string{ruleID}
+}
+
+// matches returns true if the fix remediates the rule with the given check IDs.
+func (f remediationFix) matches(ruleID string, checkIDs []string) bool {
+	candidates := []string{ruleID}
+	for _, checkID := range checkIDs {
+		candidates = append(candidates, fixIDs(checkID)...)
+	}
+	for _, id := range f.ids {
+		for _, candidate := range candidates {
+			if id == candidate {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Rules returns the sorted IDs of the given rules with a fix in the remediation.
+// Remediations that apply to a whole profile, such as blueprints, have no rules.
+func (r RemediationFile) Rules(rules map[string][]string) []string {
+	var ruleIDs []string
+	for ruleID, checkIDs := range rules {
+		for _, fix := range r.fixes {
+			if fix.matches(ruleID, checkIDs) {
+				ruleIDs = append(ruleIDs, ruleID)
+				break
+			}
+		}
+	}
+	sort.Strings(ruleIDs)
+	return ruleIDs
+}
+
+// Render returns the script or playbook applying only the fixes of the selected rules.
+// The rules map the selected rule IDs to their check IDs.
+func (r RemediationFile) Render(rules map[string][]string) ([]byte, error) {
+	var selected []remediationFix
+	for _, fix := range r.fixes {
+		for ruleID, checkIDs := range rules {
+			if fix.matches(ruleID, checkIDs) {
+				selected = append(selected, fix)
+				break
+			}
+		}
+	}
+	if len(selected) == 0 {
+		return nil, fmt.Errorf("%w: %s has no fixes for the selected rules", ErrNoRemediation, r.Path)
+	}
+
+	switch r.Type {
+	case RemediationBash:
+		var script strings.Builder
+		script.WriteString(r.header)
+		for _, fix := range selected {
+			script.WriteString(fix.content)
+			script.WriteString("\n")
+		}
+		return []byte(script.String()), nil
+	case RemediationAnsible:
+		var plays []yaml.MapSlice
+		for i, play := range r.plays {
+			var tasks []any
+			for _, fix := range selected {
+				if fix.play == i {
+					tasks = append(tasks, fix.task)
+				}
+			}
+			if len(tasks) == 0 {
+				continue
+			}
+			plays = append(plays, append(append(yaml.MapSlice{}, play...), yaml.MapItem{Key: "tasks", Value: tasks}))
+		}
+		return yaml.Marshal(plays)
+	default:
+		return nil, fmt.Errorf("%s remediations cannot be rendered for rules", r.Type)
+	}
+}
+
+// AppliedRemediation describes a remediation run for a set of rules.
+type AppliedRemediation struct {
+	Type    string
+	Plugin  string
+	RuleIDs []string
+	// Script is the path of the executed script or playbook.
+	Script string
+	// Log is the path of the file holding the remediation output.
+	Log   string
+	Start time.Time
+	End   time.Time
+	// Err is the error of a failed remediation.
+	Err error
+}
+
+// AddRemediation records the remediation in the latest result of the assessment results.
+// Each rule gets a risk holding the remediation response, linked to the observations of the rule
+// and to the findings referencing them. The IDs of the rules without observations are returned.
+func AddRemediation(assessmentResults *oscalTypes.AssessmentResults, applied AppliedRemediation) []string {
+	if len(assessmentResults.Results) == 0 {
+		return applied.RuleIDs
+	}
+	result := &assessmentResults.Results[len(assessmentResults.Results)-1]
+
+	observationsByRule := make(map[string][]oscalTypes.RelatedObservation)
+	if result.Observations != nil {
+		for _, observation := range *result.Observations {
+			if observation.Props == nil {
+				continue
+			}
+			ruleID, found := extensions.GetTrestleProp(extensions.AssessmentRuleIdProp, *observation.Props)
+			if !found {
+				continue
+			}
+			observationsByRule[ruleID.Value] = append(observationsByRule[ruleID.Value], oscalTypes.RelatedObservation{ObservationUuid: observation.UUID})
+		}
+	}
+
+	status, lifecycle, riskStatus := RemediationApplied, "completed", "remediating"
+	if applied.Err != nil {
+		status, lifecycle, riskStatus = RemediationFailed, "planned", "open"
+	}
+	var links []oscalTypes.Link
+	if applied.Script != "" {
+		links = append(links, oscalTypes.Link{Href: "file://" + applied.Script, Text: "remediation"})
+	}
+	if applied.Log != "" {
+		links = append(links, oscalTypes.Link{Href: "file://" + applied.Log, Text: "remediation log"})
+	}
+
+	var unrecorded []string
+	for _, ruleID := range applied.RuleIDs {
+		observations, found := observationsByRule[ruleID]
+		if !found {
+			unrecorded = append(unrecorded, ruleID)
+			continue
+		}
+		response := oscalTypes.Response{
+			UUID:        uuid.NewUUID(),
+			Title:       fmt.Sprintf("Remediation of rule %s", ruleID),
+			Description: fmt.Sprintf("The %s remediation generated by the %s plugin was %s.", applied.Type, applied.Plugin, status),
+			Lifecycle:   lifecycle,
+			Props: &[]oscalTypes.Property{
+				{Name: remediationTypeProp, Value: applied.Type, Ns: extensions.TrestleNameSpace},
+				{Name: remediationStatusProp, Value: status, Ns: extensions.TrestleNameSpace},
+			},
+		}
+		if len(links) > 0 {
+			response.Links = &links
+		}
+		if applied.Err != nil {
+			response.Remarks = applied.Err.Error()
+		}
+		end := applied.End
+		entry := oscalTypes.RiskLogEntry{
+			UUID:             uuid.NewUUID(),
+			Title:            fmt.Sprintf("Remediation %s", status),
+			Start:            applied.Start,
+			End:              &end,
+			StatusChange:     riskStatus,
+			RelatedResponses: &[]oscalTypes.RiskResponseReference{{ResponseUuid: response.UUID}},
+		}
+
+		risk := findRuleRisk(result, ruleID)
+		if risk == nil {
+			if result.Risks == nil {
+				result.Risks = &[]oscalTypes.Risk{}
+			}
+			*result.Risks = append(*result.Risks, oscalTypes.Risk{
+				UUID:                uuid.NewUUID(),
+				Title:               fmt.Sprintf("Rule %s is not satisfied", ruleID),
+				Description:         fmt.Sprintf("The assessment of rule %s did not pass.", ruleID),
+				Statement:           fmt.Sprintf("The requirements checked by rule %s are not met.", ruleID),
+				Props:               &[]oscalTypes.Property{{Name: extensions.AssessmentRuleIdProp, Value: ruleID, Ns: extensions.TrestleNameSpace}},
+				RelatedObservations: &observations,
+				Remediations:        &[]oscalTypes.Response{},
+				RiskLog:             &oscalTypes.RiskLog{},
+			})
+			risk = &(*result.Risks)[len(*result.Risks)-1]
+		}
+		if risk.Remediations == nil {
+			risk.Remediations = &[]oscalTypes.Response{}
+		}
+		if risk.RiskLog == nil {
+			risk.RiskLog = &oscalTypes.RiskLog{}
+		}
+		*risk.Remediations = append(*risk.Remediations, response)
+		risk.RiskLog.Entries = append(risk.RiskLog.Entries, entry)
+		risk.Status = riskStatus
+		linkFindings(result, observations, risk.UUID)
+	}
+	return unrecorded
+}
+
+// findRuleRisk returns the risk recorded for the rule in the result.
+func findRuleRisk(result *oscalTypes.Result, ruleID string) *oscalTypes.Risk {
+	if result.Risks == nil {
+		return nil
+	}
+	for i := range *result.Risks {
+		risk := &(*result.Risks)[i]
+		if risk.Props == nil {
+			continue
+		}
+		if prop, found := extensions.GetTrestleProp(extensions.AssessmentRuleIdProp, *risk.Props); found && prop.Value == ruleID {
+			return risk
+		}
+	}
+	return nil
+}
+
+// linkFindings adds the risk to the findings related to the observations.
+func linkFindings(result *oscalTypes.Result, observations []oscalTypes.RelatedObservation, riskUUID string) {
+	if result.Findings == nil {
+		return
+	}
+	related := make(map[string]struct{})
+	for _, observation := range observations {
+		related[observation.ObservationUuid] = struct{}{}
+	}
+	for i := range *result.Findings {
+		finding := &(*result.Findings)[i]
+		if finding.RelatedObservations == nil || !referencesObservation(*finding.RelatedObservations, related) {
+			continue
+		}
+		if finding.RelatedRisks == nil {
+			finding.RelatedRisks = &[]oscalTypes.AssociatedRisk{}
+		}
+		linked := false
+		for _, risk := range *finding.RelatedRisks {
+			if risk.RiskUuid == riskUUID {
+				linked = true
+				break
+			}
+		}
+		if !linked {
+			*finding.RelatedRisks = append(*finding.RelatedRisks, oscalTypes.AssociatedRisk{RiskUuid: riskUUID})
+		}
+	}
+}
+
+func referencesObservation(observations []oscalTypes.RelatedObservation, uuids map[string]struct{}) bool {
+	for _, observation := range observations {
+		if _, found := uuids[observation.ObservationUuid]; found {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,185 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package complytime
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	oscalTypes "github.com/defenseunicorns/go-oscal/src/types/oscal-1-1-3"
+	"github.com/oscal-compass/oscal-sdk-go/extensions"
+	"github.com/stretchr/testify/require"
+)
+
+// testRules are the rules of the test remediations by rule ID. The first rule is identified
+// by its check ID and the second by its rule ID.
+var testRules = map[string][]string{
+	"rule_ssh_disable_root":  {"xccdf_org.ssgproject.content_rule_sshd_disable_root_login"},
+	"service_auditd_enabled": {},
+	"no_remediation":         {"no_remediation_check"},
+}
+
+// newTestRemediations returns a workspace with the test remediations generated by the openscap plugin.
+func newTestRemediations(t *testing.T) string {
+	workspace := t.TempDir()
+	remediationPath := filepath.Join(workspace, "openscap", remediationDir)
+	require.NoError(t, os.MkdirAll(remediationPath, 0700))
+	for _, name := range []string{"remediation-script.sh", "remediation-playbook.yml", "remediation-blueprint.toml"} {
+		data, err := os.ReadFile(filepath.Join("testdata", "remediations", name))
+		require.NoError(t, err)
+		require.NoError(t, os.WriteFile(filepath.Join(remediationPath, name), data, 0600))
+	}
+	return workspace
+}
+
+func TestPlanRules(t *testing.T) {
+	plan := &oscalTypes.AssessmentPlan{
+		LocalDefinitions: &oscalTypes.LocalDefinitions{
+			Activities: &[]oscalTypes.Activity{
+				{Title: "rule-1", Steps: &[]oscalTypes.Step{{Title: "check-1"}, {Title: "check-2"}}},
+				{Title: "rule-2"},
+				{
+					Title: "rule-3",
+					Props: &[]oscalTypes.Property{{Name: extensions.SkippedRulesProperty, Value: "true", Ns: extensions.TrestleNameSpace}},
+				},
+				{Description: "activity without rule"},
+			},
+		},
+	}
+	require.Equal(t, map[string][]string{
+		"rule-1": {"check-1", "check-2"},
+		"rule-2": {},
+	}, PlanRules(plan))
+}
+
+func TestFindRemediations(t *testing.T) {
+	remediations, err := FindRemediations(newTestRemediations(t))
+	require.NoError(t, err)
+	require.Len(t, remediations, 3)
+
+	gotRules := make(map[string][]string)
+	for _, remediation := range remediations {
+		require.Equal(t, "openscap", remediation.Plugin)
+		gotRules[remediation.Type] = remediation.Rules(testRules)
+	}
+	require.Equal(t, map[string][]string{
+		RemediationAnsible:   {"rule_ssh_disable_root", "service_auditd_enabled"},
+		RemediationBash:      {"rule_ssh_disable_root", "service_auditd_enabled"},
+		RemediationBlueprint: nil,
+	}, gotRules)
+}
+
+func TestRemediationRender(t *testing.T) {
+	remediations, err := FindRemediations(newTestRemediations(t))
+	require.NoError(t, err)
+	byType := make(map[string]RemediationFile)
+	for _, remediation := range remediations {
+		byType[remediation.Type] = remediation
+	}
+	selected := map[string][]string{"rule_ssh_disable_root": testRules["rule_ssh_disable_root"]}
+
+	script, err := byType[RemediationBash].Render(selected)
+	require.NoError(t, err)
+	require.Contains(t, string(script), "#!/usr/bin/env bash\n")
+	require.Contains(t, string(script), "# BEGIN fix (1 / 2) for 'xccdf_org.ssgproject.content_rule_sshd_disable_root_login'")
+	require.Contains(t, string(script), "PermitRootLogin no")
+	require.NotContains(t, string(script), "auditd")
+
+	playbook, err := byType[RemediationAnsible].Render(selected)
+	require.NoError(t, err)
+	require.Contains(t, string(playbook), "name: Ansible Playbook for Example Profile")
+	require.Contains(t, string(playbook), "sshd_config: /etc/ssh/sshd_config")
+	require.Contains(t, string(playbook), "name: Disable SSH Root Login")
+	require.NotContains(t, string(playbook), "auditd")
+
+	_, err = byType[RemediationBash].Render(map[string][]string{"no_remediation": testRules["no_remediation"]})
+	require.ErrorIs(t, err, ErrNoRemediation)
+	_, err = byType[RemediationBlueprint].Render(selected)
+	require.ErrorIs(t, err, ErrNoRemediation)
+}
+
+func TestAddRemediation(t *testing.T) {
+	observationUUID := "0f1e2d3c-4b5a-4697-8877-665544332211"
+	newResults := func() *oscalTypes.AssessmentResults {
+		return &oscalTypes.AssessmentResults{
+			Results: []oscalTypes.Result{
+				{
+					Observations: &[]oscalTypes.Observation{
+						{
+							UUID:  observationUUID,
+							Props: &[]oscalTypes.Property{{Name: extensions.AssessmentRuleIdProp, Value: "rule_ssh_disable_root", Ns: extensions.TrestleNameSpace}},
+						},
+					},
+					Findings: &[]oscalTypes.Finding{
+						{Target: oscalTypes.FindingTarget{TargetId: "ac-1_smt"}, RelatedObservations: &[]oscalTypes.RelatedObservation{{ObservationUuid: observationUUID}}},
+						{Target: oscalTypes.FindingTarget{TargetId: "ac-2_smt"}},
+					},
+				},
+			},
+		}
+	}
+	start := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name           string
+		err            error
+		wantStatus     string
+		wantLifecycle  string
+		wantRiskStatus string
+	}{
+		{
+			name:           "Valid/Applied",
+			wantStatus:     RemediationApplied,
+			wantLifecycle:  "completed",
+			wantRiskStatus: "remediating",
+		},
+		{
+			name:           "Valid/Failed",
+			err:            errors.New("exit status 1"),
+			wantStatus:     RemediationFailed,
+			wantLifecycle:  "planned",
+			wantRiskStatus: "open",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			results := newResults()
+			applied := AppliedRemediation{
+				Type:    RemediationBash,
+				Plugin:  "openscap",
+				RuleIDs: []string{"rule_ssh_disable_root", "rule_not_observed"},
+				Script:  "/workspace/remediations/openscap-bash.sh",
+				Log:     "/workspace/remediations/openscap-bash.log",
+				Start:   start,
+				End:     start.Add(time.Minute),
+				Err:     tt.err,
+			}
+			require.Equal(t, []string{"rule_not_observed"}, AddRemediation(results, applied))
+			// Remediating the rule again adds a response to the same risk.
+			AddRemediation(results, applied)
+
+			result := results.Results[0]
+			require.Len(t, *result.Risks, 1)
+			risk := (*result.Risks)[0]
+			require.Equal(t, tt.wantRiskStatus, risk.Status)
+			require.Equal(t, []oscalTypes.RelatedObservation{{ObservationUuid: observationUUID}}, *risk.RelatedObservations)
+			require.Len(t, *risk.Remediations, 2)
+			require.Len(t, risk.RiskLog.Entries, 2)
+
+			response := (*risk.Remediations)[0]
+			require.Equal(t, tt.wantLifecycle, response.Lifecycle)
+			status, found := extensions.GetTrestleProp(remediationStatusProp, *response.Props)
+			require.True(t, found)
+			require.Equal(t, tt.wantStatus, status.Value)
+			require.Len(t, *response.Links, 2)
+			require.Equal(t, response.UUID, (*risk.RiskLog.Entries[0].RelatedResponses)[0].ResponseUuid)
+
+			findings := *result.Findings
+			require.Equal(t, []oscalTypes.AssociatedRisk{{RiskUuid: risk.UUID}}, *findings[0].RelatedRisks)
+			require.Nil(t, findings[1].RelatedRisks)
+		})
+	}
+}
@@ -0,0 +1,284 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package complytime
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	oscalTypes "github.com/defenseunicorns/go-oscal/src/types/oscal-1-1-3"
+	"github.com/oscal-compass/compliance-to-policy-go/v2/policy"
+	"github.com/oscal-compass/oscal-sdk-go/extensions"
+)
+
+// Rule statuses that are derived from observation subjects and are not
+// part of the policy.Result values reported by plugins.
+const (
+	// ResultWaived is the status of a rule where all non-passing subjects are waived.
+	ResultWaived = "waived"
+	// ResultNotAssessed is the status of a rule without evaluated subjects.
+	ResultNotAssessed = "not-assessed"
+	// ResultNotApplicable is the status of a rule that was not applicable to any subject.
+	ResultNotApplicable = "not-applicable"
+)
+
+// notApplicableResults are the openscap rule-results reported as errors by the plugin,
+// which records the original rule-result at the end of the reason.
+var notApplicableResults = []string{"notapplicable", "notselected"}
+
+// Subject property names set by the C2P framework on OSCAL observation subjects.
+const (
+	resourceIDProp  = "resource-id"
+	resultProp      = "result"
+	reasonProp      = "reason"
+	evaluatedOnProp = "evaluated-on"
+	// targetIDSuffix is appended to a control ID to create a finding target id.
+	targetIDSuffix = "_smt"
+)
+
+// SubjectResult is a flattened view of an OSCAL observation subject and its outcome.
+type SubjectResult struct {
+	UUID        string `json:"uuid"`
+	Title       string `json:"title"`
+	Type        string `json:"type"`
+	ResourceID  string `json:"resourceId,omitempty"`
+	Result      string `json:"result"`
+	Reason      string `json:"reason,omitempty"`
+	EvaluatedOn string `json:"evaluatedOn,omitempty"`
+	Waived      bool   `json:"waived"`
+	// Props holds the remaining subject properties (e.g. hostname).
+	Props []oscalTypes.Property `json:"-"`
+}
+
+// Key returns the identifier used to compare the subject across results.
+func (s SubjectResult) Key() string {
+	if s.ResourceID != "" {
+		return s.ResourceID
+	}
+	return s.Title
+}
+
+// IsNotApplicable returns true if the subject was not applicable to the rule.
+func (s SubjectResult) IsNotApplicable() bool {
+	if s.Result != policy.ResultError.String() {
+		return false
+	}
+	for _, result := range notApplicableResults {
+		if strings.HasSuffix(s.Reason, " "+result) {
+			return true
+		}
+	}
+	return false
+}
+
+// RuleResult is a flattened view of the OSCAL observations recorded for a single rule.
+type RuleResult struct {
+	RuleID      string
+	CheckIDs    []string
+	Title       string
+	Description string
+	Collected   time.Time
+	Subjects    []SubjectResult
+	Evidence    []oscalTypes.RelevantEvidence
+	// ControlIDs are the controls with findings referencing this rule.
+	ControlIDs []string
+}
+
+// Status returns the aggregated outcome of the rule across all subjects.
+// Failures on waived subjects are reported as ResultWaived, and subjects the
+// rule was not applicable to are ignored.
+func (r RuleResult) Status() string {
+	if len(r.Subjects) == 0 {
+		return ResultNotAssessed
+	}
+	status := policy.ResultPass.String()
+	notApplicable := 0
+	for _, subject := range r.Subjects {
+		if subject.IsNotApplicable() {
+			notApplicable++
+			continue
+		}
+		failing := IsFailingResult(subject.Result)
+		switch {
+		case failing && subject.Waived:
+			if status == policy.ResultPass.String() {
+				status = ResultWaived
+			}
+		case subject.Result == policy.ResultFail.String():
+			return subject.Result
+		case subject.Result == policy.ResultError.String():
+			status = subject.Result
+		}
+	}
+	if notApplicable == len(r.Subjects) {
+		return ResultNotApplicable
+	}
+	return status
+}
+
+// IsFailingResult returns true if the given result or status represents a non-passing outcome.
+// Rules that were not applicable have the ResultNotApplicable status, which is not failing.
+func IsFailingResult(result string) bool {
+	return result == policy.ResultFail.String() || result == policy.ResultError.String()
+}
+
+// ResultsIndex indexes OSCAL Assessment Results by rule and control.
+type ResultsIndex struct {
+	// Rules holds the rule results by rule ID.
+	Rules map[string]*RuleResult
+	// ReviewedControls is the set of control IDs reviewed in the results.
+	ReviewedControls includeControlsSet
+	// FailedControls maps the control IDs with not-satisfied findings to the
+	// failing rule IDs.
+	FailedControls map[string][]string
+}
+
+// NewResultsIndex creates a ResultsIndex from the given OSCAL Assessment Results.
+// All results in the Assessment Results are merged into the index.
+func NewResultsIndex(assessmentResults *oscalTypes.AssessmentResults) ResultsIndex {
+	index := ResultsIndex{
+		Rules:            make(map[string]*RuleResult),
+		ReviewedControls: make(includeControlsSet),
+		FailedControls:   make(map[string][]string),
+	}
+	if assessmentResults == nil {
+		return index
+	}
+
+	for _, result := range assessmentResults.Results {
+		for _, controlSelection := range result.ReviewedControls.ControlSelections {
+			if controlSelection.IncludeControls == nil {
+				continue
+			}
+			for _, control := range *controlSelection.IncludeControls {
+				index.ReviewedControls.Add(control.ControlId)
+			}
+		}
+
+		rulesByObservation := make(map[string]string)
+		if result.Observations != nil {
+			for _, observation := range *result.Observations {
+				// Observations without props did not receive results from a plugin.
+				if observation.Props == nil {
+					continue
+				}
+				ruleID, found := extensions.GetTrestleProp(extensions.AssessmentRuleIdProp, *observation.Props)
+				if !found {
+					continue
+				}
+				rulesByObservation[observation.UUID] = ruleID.Value
+				index.addObservation(ruleID.Value, observation)
+			}
+		}
+
+		if result.Findings == nil {
+			continue
+		}
+		for _, finding := range *result.Findings {
+			controlID := ControlIDFromTarget(finding.Target.TargetId)
+			index.ReviewedControls.Add(controlID)
+			if finding.Target.Status.State != "not-satisfied" || finding.RelatedObservations == nil {
+				continue
+			}
+			for _, relatedObservation := range *finding.RelatedObservations {
+				ruleID, found := rulesByObservation[relatedObservation.ObservationUuid]
+				if !found {
+					continue
+				}
+				index.Rules[ruleID].ControlIDs = AppendUnique(index.Rules[ruleID].ControlIDs, controlID)
+				if IsFailingResult(index.Rules[ruleID].Status()) {
+					index.FailedControls[controlID] = AppendUnique(index.FailedControls[controlID], ruleID)
+				}
+			}
+		}
+	}
+	return index
+}
+
+// addObservation merges an OSCAL observation into the rule result for the given rule.
+func (r ResultsIndex) addObservation(ruleID string, observation oscalTypes.Observation) {
+	ruleResult, ok := r.Rules[ruleID]
+	if !ok {
+		ruleResult = &RuleResult{
+			RuleID:      ruleID,
+			Title:       observation.Title,
+			Description: observation.Description,
+			Collected:   observation.Collected,
+		}
+		r.Rules[ruleID] = ruleResult
+	}
+	if checkID, found := extensions.GetTrestleProp(extensions.AssessmentCheckIdProp, *observation.Props); found {
+		ruleResult.CheckIDs = AppendUnique(ruleResult.CheckIDs, checkID.Value)
+	}
+	if observation.RelevantEvidence != nil {
+		ruleResult.Evidence = append(ruleResult.Evidence, *observation.RelevantEvidence...)
+	}
+	if observation.Subjects == nil {
+		return
+	}
+	for _, subject := range *observation.Subjects {
+		ruleResult.Subjects = append(ruleResult.Subjects, newSubjectResult(subject))
+	}
+}
+
+// newSubjectResult flattens an OSCAL SubjectReference.
+func newSubjectResult(subject oscalTypes.SubjectReference) SubjectResult {
+	subjectResult := SubjectResult{
+		UUID:  subject.SubjectUuid,
+		Title: subject.Title,
+		Type:  subject.Type,
+	}
+	if subject.Props == nil {
+		return subjectResult
+	}
+	for _, prop := range *subject.Props {
+		switch prop.Name {
+		case resourceIDProp:
+			subjectResult.ResourceID = prop.Value
+		case resultProp:
+			subjectResult.Result = prop.Value
+		case reasonProp:
+			subjectResult.Reason = prop.Value
+		case evaluatedOnProp:
+			subjectResult.EvaluatedOn = prop.Value
+		case extensions.WaivedRulesProperty:
+			subjectResult.Waived = prop.Value == "true"
+		default:
+			subjectResult.Props = append(subjectResult.Props, prop)
+		}
+	}
+	return subjectResult
+}
+
+// RuleIDs returns the sorted IDs of all rules in the index.
+func (r ResultsIndex) RuleIDs() []string {
+	ruleIDs := make([]string, 0, len(r.Rules))
+	for ruleID := range r.Rules {
+		ruleIDs = append(ruleIDs, ruleID)
+	}
+	sort.Strings(ruleIDs)
+	return ruleIDs
+}
+
+// ControlIDs returns the sorted IDs of all reviewed controls in the index.
+func (r ResultsIndex) ControlIDs() []string {
+	controlIDs := r.ReviewedControls.All()
+	sort.Strings(controlIDs)
+	return controlIDs
+}
+
+// ControlIDFromTarget returns the control ID from an OSCAL finding target ID.
+func ControlIDFromTarget(targetID string) string {
+	return strings.TrimSuffix(targetID, targetIDSuffix)
+}
+
+// AppendUnique appends the value to the slice if it is not already present.
+func AppendUnique(slice []string, value string) []string {
+	for _, existing := range slice {
+		if existing == value {
+			return slice
+		}
+	}
+	return append(slice, value)
+}
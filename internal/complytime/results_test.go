@@ -0,0 +1,185 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package complytime
+
+import (
+	"testing"
+
+	oscalTypes "github.com/defenseunicorns/go-oscal/src/types/oscal-1-1-3"
+	"github.com/oscal-compass/oscal-sdk-go/extensions"
+	"github.com/stretchr/testify/require"
+)
+
+// testObservation is a compact representation of an observation used to build
+// test Assessment Results.
+type testObservation struct {
+	uuid     string
+	ruleID   string
+	subjects map[string]string
+	waived   bool
+}
+
+// newTestAssessmentResults creates Assessment Results with the given reviewed controls,
+// observations, and not-satisfied findings by control ID to observation UUIDs.
+func newTestAssessmentResults(controls []string, observations []testObservation, findings map[string][]string) *oscalTypes.AssessmentResults {
+	var includeControls []oscalTypes.AssessedControlsSelectControlById
+	for _, control := range controls {
+		includeControls = append(includeControls, oscalTypes.AssessedControlsSelectControlById{ControlId: control})
+	}
+
+	var oscalObservations []oscalTypes.Observation
+	for _, obs := range observations {
+		var subjects []oscalTypes.SubjectReference
+		for resourceID, result := range obs.subjects {
+			props := []oscalTypes.Property{
+				{Name: "resource-id", Value: resourceID, Ns: extensions.TrestleNameSpace},
+				{Name: "result", Value: result, Ns: extensions.TrestleNameSpace},
+				{Name: "reason", Value: "openscap rule-result is " + result, Ns: extensions.TrestleNameSpace},
+				{Name: "hostname", Value: resourceID, Ns: extensions.TrestleNameSpace},
+			}
+			if obs.waived {
+				props = append(props, oscalTypes.Property{Name: extensions.WaivedRulesProperty, Value: "true", Ns: extensions.TrestleNameSpace})
+			}
+			subjects = append(subjects, oscalTypes.SubjectReference{
+				SubjectUuid: "subject-" + resourceID,
+				Title:       "Host " + resourceID,
+				Type:        "inventory-item",
+				Props:       &props,
+			})
+		}
+		oscalObservations = append(oscalObservations, oscalTypes.Observation{
+			UUID:  obs.uuid,
+			Title: obs.ruleID,
+			Props: &[]oscalTypes.Property{
+				{Name: extensions.AssessmentRuleIdProp, Value: obs.ruleID, Ns: extensions.TrestleNameSpace},
+				{Name: extensions.AssessmentCheckIdProp, Value: "check-" + obs.ruleID, Ns: extensions.TrestleNameSpace},
+			},
+			Subjects: &subjects,
+			RelevantEvidence: &[]oscalTypes.RelevantEvidence{
+				{Href: "file:///tmp/arf.xml", Description: "ARF_FILE"},
+			},
+		})
+	}
+
+	var oscalFindings []oscalTypes.Finding
+	for control, observationUUIDs := range findings {
+		var related []oscalTypes.RelatedObservation
+		for _, observationUUID := range observationUUIDs {
+			related = append(related, oscalTypes.RelatedObservation{ObservationUuid: observationUUID})
+		}
+		oscalFindings = append(oscalFindings, oscalTypes.Finding{
+			UUID:                "finding-" + control,
+			RelatedObservations: &related,
+			Target: oscalTypes.FindingTarget{
+				TargetId: control + "_smt",
+				Type:     "statement-id",
+				Status:   oscalTypes.ObjectiveStatus{State: "not-satisfied"},
+			},
+		})
+	}
+
+	return &oscalTypes.AssessmentResults{
+		UUID: "228ff6d0-0d67-4c15-9c16-ece9a554c4de",
+		Results: []oscalTypes.Result{
+			{
+				UUID: "348fc6d0-706d-4c15-9c16-bce2a22ac3ee",
+				ReviewedControls: oscalTypes.ReviewedControls{
+					ControlSelections: []oscalTypes.AssessedControls{
+						{IncludeControls: &includeControls},
+					},
+				},
+				Observations: &oscalObservations,
+				Findings:     &oscalFindings,
+			},
+		},
+	}
+}
+
+func TestNewResultsIndex(t *testing.T) {
+	ar := newTestAssessmentResults(
+		[]string{"control-1", "control-2", "control-3"},
+		[]testObservation{
+			{uuid: "obs-1", ruleID: "rule-1", subjects: map[string]string{"host-a": "pass"}},
+			{uuid: "obs-2", ruleID: "rule-2", subjects: map[string]string{"host-a": "fail"}},
+			{uuid: "obs-3", ruleID: "rule-3", subjects: map[string]string{"host-a": "fail"}, waived: true},
+		},
+		map[string][]string{
+			"control-2": {"obs-2"},
+			"control-3": {"obs-3"},
+		},
+	)
+	// An observation without results is ignored
+	*ar.Results[0].Observations = append(*ar.Results[0].Observations, oscalTypes.Observation{UUID: "obs-4", Title: "check-4"})
+
+	index := NewResultsIndex(ar)
+	require.Equal(t, []string{"rule-1", "rule-2", "rule-3"}, index.RuleIDs())
+	require.Equal(t, []string{"control-1", "control-2", "control-3"}, index.ControlIDs())
+	require.Equal(t, map[string][]string{"control-2": {"rule-2"}}, index.FailedControls)
+
+	rule2 := index.Rules["rule-2"]
+	require.Equal(t, "fail", rule2.Status())
+	require.Equal(t, []string{"check-rule-2"}, rule2.CheckIDs)
+	require.Equal(t, []string{"control-2"}, rule2.ControlIDs)
+	require.Len(t, rule2.Evidence, 1)
+	require.Len(t, rule2.Subjects, 1)
+	require.Equal(t, "host-a", rule2.Subjects[0].ResourceID)
+	require.Equal(t, "openscap rule-result is fail", rule2.Subjects[0].Reason)
+	require.Equal(t, []oscalTypes.Property{{Name: "hostname", Value: "host-a", Ns: extensions.TrestleNameSpace}}, rule2.Subjects[0].Props)
+
+	require.Equal(t, "pass", index.Rules["rule-1"].Status())
+	require.Equal(t, ResultWaived, index.Rules["rule-3"].Status())
+}
+
+func TestRuleResultStatus(t *testing.T) {
+	tests := []struct {
+		name       string
+		subjects   []SubjectResult
+		wantStatus string
+	}{
+		{
+			name:       "Valid/NoSubjects",
+			wantStatus: ResultNotAssessed,
+		},
+		{
+			name:       "Valid/AllPass",
+			subjects:   []SubjectResult{{Result: "pass"}, {Result: "pass"}},
+			wantStatus: "pass",
+		},
+		{
+			name:       "Valid/FailTakesPrecedence",
+			subjects:   []SubjectResult{{Result: "error"}, {Result: "fail"}, {Result: "pass"}},
+			wantStatus: "fail",
+		},
+		{
+			name:       "Valid/Error",
+			subjects:   []SubjectResult{{Result: "pass"}, {Result: "error"}},
+			wantStatus: "error",
+		},
+		{
+			name:       "Valid/WaivedFailure",
+			subjects:   []SubjectResult{{Result: "fail", Waived: true}, {Result: "pass"}},
+			wantStatus: ResultWaived,
+		},
+		{
+			name:       "Valid/NotApplicable",
+			subjects:   []SubjectResult{{Result: "error", Reason: "openscap rule-result is notapplicable"}},
+			wantStatus: ResultNotApplicable,
+		},
+		{
+			name:       "Valid/NotApplicableSubjectIgnored",
+			subjects:   []SubjectResult{{Result: "error", Reason: "openscap rule-result is notselected"}, {Result: "pass"}},
+			wantStatus: "pass",
+		},
+		{
+			name:       "Valid/UnwaivedErrorAfterWaivedFailure",
+			subjects:   []SubjectResult{{Result: "fail", Waived: true}, {Result: "error"}},
+			wantStatus: "error",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rule := RuleResult{RuleID: "rule-1", Subjects: tt.subjects}
+			require.Equal(t, tt.wantStatus, rule.Status())
+		})
+	}
+}
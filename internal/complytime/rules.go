@@ -0,0 +1,87 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package complytime
+
+import (
+	"strings"
+
+	oscalTypes "github.com/defenseunicorns/go-oscal/src/types/oscal-1-1-3"
+	"github.com/oscal-compass/oscal-sdk-go/extensions"
+	"github.com/oscal-compass/oscal-sdk-go/models/components"
+)
+
+// severityPropSuffix matches rule properties carrying the rule severity (e.g. Rule_Severity).
+const severityPropSuffix = "severity"
+
+// RuleDefinition is a rule as defined in the properties of a component definition.
+type RuleDefinition struct {
+	ID          string
+	Description string
+	// Severity is empty when the rule set has no severity property.
+	Severity string
+	// Plugin is the title of the validation component implementing the rule.
+	Plugin string
+}
+
+// RuleDefinitions returns the rules defined in the given component definitions by rule ID.
+// Rule properties are grouped into rule sets by their remarks.
+func RuleDefinitions(compDefs []oscalTypes.ComponentDefinition) map[string]RuleDefinition {
+	definitions := make(map[string]RuleDefinition)
+	for _, compDef := range compDefs {
+		if compDef.Components == nil {
+			continue
+		}
+		for _, component := range *compDef.Components {
+			if component.Props == nil {
+				continue
+			}
+			for _, ruleSet := range groupPropsByRemarks(*component.Props) {
+				definition := RuleDefinition{}
+				if component.Type == string(components.Validation) {
+					definition.Plugin = component.Title
+				}
+				for _, prop := range ruleSet {
+					switch {
+					case prop.Name == extensions.RuleIdProp:
+						definition.ID = prop.Value
+					case prop.Name == extensions.RuleDescriptionProp:
+						definition.Description = prop.Value
+					case strings.HasSuffix(strings.ToLower(prop.Name), severityPropSuffix):
+						definition.Severity = strings.ToLower(prop.Value)
+					}
+				}
+				if definition.ID == "" {
+					continue
+				}
+				// Merge the definitions of the rule across components
+				if existing, found := definitions[definition.ID]; found {
+					if existing.Description == "" {
+						existing.Description = definition.Description
+					}
+					if existing.Severity == "" {
+						existing.Severity = definition.Severity
+					}
+					if existing.Plugin == "" {
+						existing.Plugin = definition.Plugin
+					}
+					definition = existing
+				}
+				definitions[definition.ID] = definition
+			}
+		}
+	}
+	return definitions
+}
+
+// groupPropsByRemarks groups properties with the same remarks into rule sets.
+// Properties without remarks do not belong to a rule set.
+func groupPropsByRemarks(props []oscalTypes.Property) map[string][]oscalTypes.Property {
+	grouped := make(map[string][]oscalTypes.Property)
+	for _, prop := range props {
+		if prop.Remarks == "" {
+			continue
+		}
+		grouped[prop.Remarks] = append(grouped[prop.Remarks], prop)
+	}
+	return grouped
+}
@@ -0,0 +1,46 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package complytime
+
+import (
+	"testing"
+
+	oscalTypes "github.com/defenseunicorns/go-oscal/src/types/oscal-1-1-3"
+	"github.com/oscal-compass/oscal-sdk-go/extensions"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRuleDefinitions(t *testing.T) {
+	compDefs := []oscalTypes.ComponentDefinition{
+		{
+			Components: &[]oscalTypes.DefinedComponent{
+				{
+					Title: "My Software",
+					Props: &[]oscalTypes.Property{
+						{Name: extensions.RuleIdProp, Value: "rule-1", Remarks: "rule_set_00"},
+						{Name: extensions.RuleDescriptionProp, Value: "My first rule", Remarks: "rule_set_00"},
+						{Name: "Rule_Severity", Value: "High", Remarks: "rule_set_00"},
+						{Name: extensions.RuleIdProp, Value: "rule-2", Remarks: "rule_set_01"},
+						{Name: extensions.RuleDescriptionProp, Value: "My second rule", Remarks: "rule_set_01"},
+						{Name: extensions.ParameterIdProp, Value: "param-1", Remarks: "rule_set_02"},
+						{Name: extensions.RuleIdProp, Value: "rule-3"},
+					},
+				},
+				{
+					Title: "openscap",
+					Type:  "validation",
+					Props: &[]oscalTypes.Property{
+						{Name: extensions.RuleIdProp, Value: "rule-2", Remarks: "rule_set_00"},
+						{Name: "severity", Value: "low", Remarks: "rule_set_00"},
+					},
+				},
+			},
+		},
+	}
+
+	definitions := RuleDefinitions(compDefs)
+	require.Equal(t, map[string]RuleDefinition{
+		"rule-1": {ID: "rule-1", Description: "My first rule", Severity: "high"},
+		"rule-2": {ID: "rule-2", Description: "My second rule", Severity: "low", Plugin: "openscap"},
+	}, definitions)
+}
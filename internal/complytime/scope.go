@@ -4,8 +4,10 @@ package complytime
 
 import (
 	"fmt"
+	"slices"
 	"sort"
 	"strings"
+	"time"
 
 	oscalTypes "github.com/defenseunicorns/go-oscal/src/types/oscal-1-1-3"
 	"github.com/hashicorp/go-hclog"
@@ -32,6 +34,8 @@ type ControlEntry struct {
 // AssessmentScope sets up the yaml mapping type for writing to config file.
 // Formats testdata as go struct.
 type AssessmentScope struct {
+	// Extends is the path of a scope config merged before this one, relative to this one.
+	Extends string `yaml:"extends,omitempty"`
 	// FrameworkID is the identifier for the control set
 	// in the Assessment Plan.
 	FrameworkID string `yaml:"frameworkId"`
@@ -40,6 +44,8 @@ type AssessmentScope struct {
 	IncludeControls    []ControlEntry `yaml:"includeControls"`
 	GlobalExcludeRules []string       `yaml:"globalExcludeRules,omitempty"`
 	GlobalWaiveRules   []string       `yaml:"globalWaiveRules,omitempty"`
+	// Waivers justify the rules waived by WaiveRules and GlobalWaiveRules.
+	Waivers []Waiver `yaml:"waivers,omitempty"`
 }
 
 // NewAssessmentScope creates an AssessmentScope struct for a given framework id.
@@ -150,6 +156,51 @@ func NewAssessmentScopeFromCDs(frameworkId string, appDir ApplicationDirectory,
 	return scope, nil
 }
 
+// ControlRules returns the sorted IDs of the rules implementing each control of the framework
+// in the given component definitions, by control ID. Controls without rules are included with no rules.
+func ControlRules(frameworkID string, cds []oscalTypes.ComponentDefinition) map[string][]string {
+	controlRules := make(map[string][]string)
+	for _, componentDef := range cds {
+		if componentDef.Components == nil {
+			continue
+		}
+		for _, component := range *componentDef.Components {
+			if component.ControlImplementations == nil {
+				continue
+			}
+			for _, ci := range *component.ControlImplementations {
+				if ci.Props == nil {
+					continue
+				}
+				frameworkProp, found := extensions.GetTrestleProp(extensions.FrameworkProp, *ci.Props)
+				if !found || frameworkProp.Value != frameworkID {
+					continue
+				}
+				for _, ir := range ci.ImplementedRequirements {
+					if ir.ControlId == "" {
+						continue
+					}
+					if _, found := controlRules[ir.ControlId]; !found {
+						controlRules[ir.ControlId] = nil
+					}
+					if ir.Props == nil {
+						continue
+					}
+					for _, prop := range *ir.Props {
+						if prop.Name == extensions.RuleIdProp {
+							controlRules[ir.ControlId] = AppendUnique(controlRules[ir.ControlId], prop.Value)
+						}
+					}
+				}
+			}
+		}
+	}
+	for _, rules := range controlRules {
+		sort.Strings(rules)
+	}
+	return controlRules
+}
+
 // processSetParameters processes set parameters for a control implementation
 func processSetParameters(ci oscalTypes.ControlImplementationSet, result *componentDefResult, cds []oscalTypes.ComponentDefinition) {
 	implementedSetParams := make(map[string][]string)
@@ -244,6 +295,9 @@ func (a AssessmentScope) ApplyScope(assessmentPlan *oscalTypes.AssessmentPlan, l
 
 	// This is a thin wrapper right now, but the goal to expand to different areas
 	// of customization.
+	if err := a.validateWaivers(); err != nil {
+		return err
+	}
 	a.applyControlScope(assessmentPlan, logger)
 	a.applyRuleScope(assessmentPlan, logger)
 	return a.applyParameterScope(assessmentPlan, componentDefs, logger)
@@ -320,6 +374,9 @@ func (a AssessmentScope) applyRuleScope(assessmentPlan *oscalTypes.AssessmentPla
 		logger.Warn("Global waive rules contains '*' - all rules except excluded ones will be waived from all controls")
 	}
 
+	// Rules with an expired waiver are evaluated again
+	expiredWaivers := a.expiredWaivers(time.Now(), logger)
+
 	// Build a map of control ID to ControlEntry for quick lookup
 	controlRuleConfig := make(map[string]ControlEntry)
 	for _, entry := range a.IncludeControls {
@@ -364,8 +421,13 @@ func (a AssessmentScope) applyRuleScope(assessmentPlan *oscalTypes.AssessmentPla
 						} else {
 							// If the rule is waived in one control, add a waivedActivity prop to activity
 							shouldWaive := a.checkWaive(controlSelection, activity.Title, controlRuleConfig, globalWaiveRules)
-							if shouldWaive {
+							if shouldWaive && !a.isWaiverExpired(activity.Title, expiredWaivers) {
 								a.addActivityProperty(activity, extensions.WaivedRulesProperty, "true")
+								if waiver, found := a.waiverFor(activity.Title); found {
+									for _, prop := range waiver.props() {
+										a.addActivityProperty(activity, prop.Name, prop.Value)
+									}
+								}
 							}
 						}
 					}
@@ -390,8 +452,13 @@ func (a AssessmentScope) applyRuleScope(assessmentPlan *oscalTypes.AssessmentPla
 								a.addStepProperty(step, extensions.SkippedRulesProperty, "true")
 							} else {
 								shouldWaive := a.checkWaive(controlSelection, activity.Title, controlRuleConfig, globalWaiveRules)
-								if shouldWaive {
+								if shouldWaive && !a.isWaiverExpired(activity.Title, expiredWaivers) {
 									a.addStepProperty(step, extensions.WaivedRulesProperty, "true")
+									if waiver, found := a.waiverFor(activity.Title); found {
+										for _, prop := range waiver.props() {
+											a.addStepProperty(step, prop.Name, prop.Value)
+										}
+									}
 								}
 							}
 						}
@@ -708,10 +775,32 @@ func filterParameterSelection(parameterID, selectedValue string, remarksProps ma
 		return true, nil
 	}
 
+	alternatives := parameterAlternatives(parameterID, remarksProps)
+	if len(alternatives) == 0 {
+		return true, nil
+	}
+	return slices.Contains(alternatives, selectedValue), alternatives
+}
+
+// ParameterAlternatives returns the values that can be selected for a parameter in the
+// given component definitions. An empty result means any value is accepted.
+func ParameterAlternatives(parameterID string, componentDefinitions []oscalTypes.ComponentDefinition) []string {
+	return parameterAlternatives(parameterID, extractRemarksProperties(componentDefinitions))
+}
+
+// parameterAlternatives returns the alternatives of a parameter across all rule sets defining it.
+func parameterAlternatives(parameterID string, remarksProps map[string][]oscalTypes.Property) []string {
 	var allPossibleAlternatives []string
-	var hasAlternatives bool
 
-	for _, props := range remarksProps {
+	// Iterate in a stable order so alternatives are returned consistently.
+	remarks := make([]string, 0, len(remarksProps))
+	for remark := range remarksProps {
+		remarks = append(remarks, remark)
+	}
+	sort.Strings(remarks)
+
+	for _, remark := range remarks {
+		props := remarksProps[remark]
 		var foundParameterID bool
 		var parameterSuffix string
 
@@ -738,28 +827,13 @@ func filterParameterSelection(parameterID, selectedValue string, remarksProps ma
 
 			for _, prop := range props {
 				if prop.Name == alternativesPropertyName {
-					alternatives := parseParameterAlternatives(prop.Value)
-					if len(alternatives) > 0 {
-						hasAlternatives = true
-						for _, altValue := range alternatives {
-							if altValue == selectedValue {
-								return true, alternatives
-							}
-						}
-						allPossibleAlternatives = append(allPossibleAlternatives, alternatives...)
-					}
+					allPossibleAlternatives = append(allPossibleAlternatives, parseParameterAlternatives(prop.Value)...)
 					break
 				}
 			}
 		}
 	}
-
-	if hasAlternatives {
-		cleanedAlternatives := removeDuplicates(allPossibleAlternatives)
-		return false, cleanedAlternatives
-	}
-
-	return true, nil
+	return removeDuplicates(allPossibleAlternatives)
 }
 
 // isParameterIdProperty checks if a property matches Parameter_Id.
@@ -0,0 +1,446 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package complytime
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	oscalTypes "github.com/defenseunicorns/go-oscal/src/types/oscal-1-1-3"
+	"github.com/goccy/go-yaml"
+	"github.com/goccy/go-yaml/ast"
+	"github.com/goccy/go-yaml/parser"
+	"github.com/oscal-compass/oscal-sdk-go/extensions"
+)
+
+// ScopeIssue is a mistake found in an assessment scope config.
+type ScopeIssue struct {
+	// File is the scope config file of the issue when linting a layered scope, or empty.
+	File string
+	// Line is the line of the offending value in the scope config, or 0 when unknown.
+	Line int
+	// Path is the YAML path of the offending value.
+	Path    string
+	Message string
+	// Suggestion is the closest valid value, if any.
+	Suggestion string
+}
+
+// String returns the issue with its suggestion.
+func (i ScopeIssue) String() string {
+	if i.Suggestion == "" {
+		return i.Message
+	}
+	return fmt.Sprintf("%s (did you mean %q?)", i.Message, i.Suggestion)
+}
+
+// scopeLinter cross-checks an assessment scope against the framework in the component definitions.
+type scopeLinter struct {
+	file   *ast.File
+	issues []ScopeIssue
+	// controlRules holds the rules of each control of the framework.
+	controlRules map[string][]string
+	// rules are the rules of all controls of the framework.
+	rules []string
+	// ruleParameters holds the parameters used by each rule.
+	ruleParameters map[string][]string
+	remarksProps   map[string][]oscalTypes.Property
+	// controlGroups holds the controls of each catalog group of the framework.
+	controlGroups map[string][]string
+}
+
+// LintAssessmentScope parses the scope config and cross-checks every control, rule, and parameter
+// selection against the framework in the component definitions. Unlike ApplyScope, unknown IDs are
+// reported as issues with their line in the scope config and the closest valid ID. Control selectors
+// and rule patterns are reported when they are invalid or match nothing. controlGroups holds the
+// controls of each catalog group of the framework, as returned by ControlGroups.
+func LintAssessmentScope(data []byte, cds []oscalTypes.ComponentDefinition, controlGroups map[string][]string) ([]ScopeIssue, error) {
+	return lintScopeLayer(data, AssessmentScope{}, cds, controlGroups)
+}
+
+// LintLayeredScope lints each layer of the layered scope like LintAssessmentScope. The framework, controls,
+// and waived rules set by the layers before a layer are taken into account, and the file of each issue is set.
+func LintLayeredScope(layered LayeredScope, cds []oscalTypes.ComponentDefinition, controlGroups map[string][]string) ([]ScopeIssue, error) {
+	var issues []ScopeIssue
+	for _, layer := range layered.Layers {
+		layerIssues, err := lintScopeLayer(layer.Data, layer.Base, cds, controlGroups)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", layer.Path, err)
+		}
+		for i := range layerIssues {
+			layerIssues[i].File = layer.Path
+		}
+		issues = append(issues, layerIssues...)
+	}
+	return issues, nil
+}
+
+// lintScopeLayer lints the scope config merged after the given base scope.
+func lintScopeLayer(data []byte, base AssessmentScope, cds []oscalTypes.ComponentDefinition, controlGroups map[string][]string) ([]ScopeIssue, error) {
+	var scope AssessmentScope
+	if err := yaml.UnmarshalWithOptions(data, &scope, yaml.Strict()); err != nil {
+		return nil, fmt.Errorf("invalid assessment scope: %s", yaml.FormatError(err, false, false))
+	}
+	file, err := parser.ParseBytes(data, 0)
+	if err != nil {
+		return nil, fmt.Errorf("invalid assessment scope: %s", yaml.FormatError(err, false, false))
+	}
+	linter := &scopeLinter{file: file, controlGroups: controlGroups}
+	linter.lint(scope, base, cds)
+	sort.SliceStable(linter.issues, func(i, j int) bool { return linter.issues[i].Line < linter.issues[j].Line })
+	return linter.issues, nil
+}
+
+func (l *scopeLinter) lint(scope, base AssessmentScope, cds []oscalTypes.ComponentDefinition) {
+	frameworks := frameworkIDs(cds)
+	frameworkID := scope.FrameworkID
+	if frameworkID == "" {
+		frameworkID = base.FrameworkID
+	}
+	if frameworkID == "" {
+		l.addIssue("$.frameworkId", "", "frameworkId must be set")
+		return
+	}
+	if !slices.Contains(frameworks, frameworkID) {
+		l.addIssue("$.frameworkId", closestMatch(frameworkID, frameworks),
+			"framework %q is not defined in the component definitions", frameworkID)
+		return
+	}
+
+	l.controlRules = ControlRules(frameworkID, cds)
+	for _, rules := range l.controlRules {
+		for _, rule := range rules {
+			l.rules = AppendUnique(l.rules, rule)
+		}
+	}
+	sort.Strings(l.rules)
+	l.remarksProps = extractRemarksProperties(cds)
+	l.ruleParameters = make(map[string][]string)
+	for _, props := range l.remarksProps {
+		var ruleID string
+		var parameters []string
+		for _, prop := range props {
+			if prop.Name == extensions.RuleIdProp {
+				ruleID = prop.Value
+			}
+			if isParameterIdProperty(prop.Name) {
+				parameters = append(parameters, prop.Value)
+			}
+		}
+		if ruleID != "" {
+			l.ruleParameters[ruleID] = append(l.ruleParameters[ruleID], parameters...)
+		}
+	}
+
+	controls := make([]string, 0, len(l.controlRules))
+	for control := range l.controlRules {
+		controls = append(controls, control)
+	}
+	sort.Strings(controls)
+
+	baseControls := make([]string, 0, len(base.IncludeControls))
+	for _, entry := range base.IncludeControls {
+		baseControls = append(baseControls, entry.ControlID)
+	}
+
+	seen := make(map[string]bool)
+	for i, entry := range scope.IncludeControls {
+		path := fmt.Sprintf("$.includeControls[%d]", i)
+		switch {
+		case entry.ControlID == "":
+			l.addIssue(path, "", "controlId must be set")
+			continue
+		case strings.HasPrefix(entry.ControlID, scopeRemovePrefix):
+			controlID := strings.TrimPrefix(entry.ControlID, scopeRemovePrefix)
+			if !slices.Contains(baseControls, controlID) {
+				l.addIssue(path+".controlId", closestMatch(controlID, baseControls),
+					"control %q cannot be removed: it is not included by the scope configs before this one", controlID)
+			}
+			continue
+		case isControlSelector(entry.ControlID):
+			selectorRules, ok := l.lintControlSelector(path+".controlId", entry.ControlID, frameworkID, controls)
+			if !ok {
+				continue
+			}
+			l.lintControlRules(path+".includeRules", entry.ControlID, selectorRules, entry.IncludeRules)
+			l.lintControlRules(path+".excludeRules", entry.ControlID, selectorRules, entry.ExcludeRules)
+			l.lintControlRules(path+".waiveRules", entry.ControlID, selectorRules, entry.WaiveRules)
+			l.lintParameters(path+".selectParameters", entry.ControlID, selectorRules, entry.SelectParameters)
+			continue
+		case seen[entry.ControlID]:
+			l.addIssue(path+".controlId", "", "control %q is listed more than once", entry.ControlID)
+		}
+		seen[entry.ControlID] = true
+
+		controlRules, found := l.controlRules[entry.ControlID]
+		if !found {
+			l.addIssue(path+".controlId", closestMatch(entry.ControlID, controls),
+				"control %q is not implemented for framework %q", entry.ControlID, frameworkID)
+			continue
+		}
+		l.lintControlRules(path+".includeRules", entry.ControlID, controlRules, entry.IncludeRules)
+		l.lintControlRules(path+".excludeRules", entry.ControlID, controlRules, entry.ExcludeRules)
+		l.lintControlRules(path+".waiveRules", entry.ControlID, controlRules, entry.WaiveRules)
+		l.lintParameters(path+".selectParameters", entry.ControlID, controlRules, entry.SelectParameters)
+	}
+	l.lintGlobalRules("$.globalExcludeRules", frameworkID, scope.GlobalExcludeRules)
+	l.lintGlobalRules("$.globalWaiveRules", frameworkID, scope.GlobalWaiveRules)
+	l.lintWaivers(scope, base)
+}
+
+// lintControlSelector checks that the control selector matches controls of the framework, and
+// returns the rules of the matched controls.
+func (l *scopeLinter) lintControlSelector(path, selector, frameworkID string, controls []string) ([]string, bool) {
+	matches, err := selectControls(selector, controls, l.controlGroups)
+	if err != nil {
+		var suggestion string
+		if groupID, found := strings.CutPrefix(selector, controlGroupPrefix); found {
+			groups := make([]string, 0, len(l.controlGroups))
+			for group := range l.controlGroups {
+				groups = append(groups, group)
+			}
+			sort.Strings(groups)
+			if suggestion = closestMatch(groupID, groups); suggestion != "" {
+				suggestion = controlGroupPrefix + suggestion
+			}
+		}
+		l.addIssue(path, suggestion, "invalid control selector %q: %v", selector, err)
+		return nil, false
+	}
+	if len(matches) == 0 {
+		l.addIssue(path, "", "control selector %q matches no control of framework %q", selector, frameworkID)
+		return nil, false
+	}
+	var rules []string
+	for _, match := range matches {
+		for _, rule := range l.controlRules[match] {
+			rules = AppendUnique(rules, rule)
+		}
+	}
+	sort.Strings(rules)
+	return rules, true
+}
+
+// lintRulePattern checks that the rule pattern is valid and matches at least one of the rules.
+func (l *scopeLinter) lintRulePattern(path, pattern, scopeName string, rules []string) {
+	matches, err := matchPattern(pattern, rules, false)
+	if err != nil {
+		l.addIssue(path, "", "%v", err)
+		return
+	}
+	if len(matches) == 0 {
+		l.addIssue(path, "", "rule pattern %q matches no rule of %s", pattern, scopeName)
+	}
+}
+
+// lintControlRules checks that the rules are implemented by the control.
+func (l *scopeLinter) lintControlRules(path, controlID string, controlRules, rules []string) {
+	for i, rule := range rules {
+		rule = strings.TrimPrefix(rule, scopeRemovePrefix)
+		if rule == scopeWildcard {
+			continue
+		}
+		rulePath := fmt.Sprintf("%s[%d]", path, i)
+		if isPattern(rule) {
+			l.lintRulePattern(rulePath, rule, fmt.Sprintf("control %q", controlID), controlRules)
+			continue
+		}
+		if slices.Contains(controlRules, rule) {
+			continue
+		}
+		suggestion := closestMatch(rule, controlRules)
+		if slices.Contains(l.rules, rule) {
+			l.addIssue(rulePath, suggestion, "rule %q is not implemented by control %q", rule, controlID)
+			continue
+		}
+		if suggestion == "" {
+			suggestion = closestMatch(rule, l.rules)
+		}
+		l.addIssue(rulePath, suggestion, "rule %q is not defined in the component definitions", rule)
+	}
+}
+
+// lintGlobalRules checks that the rules are implemented by a control of the framework.
+func (l *scopeLinter) lintGlobalRules(path, frameworkID string, rules []string) {
+	for i, rule := range rules {
+		rule = strings.TrimPrefix(rule, scopeRemovePrefix)
+		if rule == scopeWildcard || slices.Contains(l.rules, rule) {
+			continue
+		}
+		if isPattern(rule) {
+			l.lintRulePattern(fmt.Sprintf("%s[%d]", path, i), rule, fmt.Sprintf("framework %q", frameworkID), l.rules)
+			continue
+		}
+		l.addIssue(fmt.Sprintf("%s[%d]", path, i), closestMatch(rule, l.rules),
+			"rule %q is not implemented by any control of framework %q", rule, frameworkID)
+	}
+}
+
+// lintParameters checks that the parameters are used by the rules of the control and that the
+// selected values are valid alternatives.
+func (l *scopeLinter) lintParameters(path, controlID string, controlRules []string, parameters []ParameterEntry) {
+	var controlParameters []string
+	for _, rule := range controlRules {
+		for _, parameter := range l.ruleParameters[rule] {
+			controlParameters = AppendUnique(controlParameters, parameter)
+		}
+	}
+	sort.Strings(controlParameters)
+
+	for i, parameter := range parameters {
+		parameterPath := fmt.Sprintf("%s[%d]", path, i)
+		name, removed := strings.CutPrefix(parameter.Name, scopeRemovePrefix)
+		if name == "N/A" {
+			continue
+		}
+		if !slices.Contains(controlParameters, name) {
+			l.addIssue(parameterPath+".name", closestMatch(name, controlParameters),
+				"parameter %q is not used by the rules of control %q", name, controlID)
+			continue
+		}
+		if removed {
+			continue
+		}
+		if valid, alternatives := filterParameterSelection(parameter.Name, parameter.Value, l.remarksProps); !valid {
+			l.addIssue(parameterPath+".value", closestMatch(parameter.Value, alternatives),
+				"value %q is not a valid alternative for parameter %q: must be one of %s",
+				parameter.Value, parameter.Name, strings.Join(alternatives, ", "))
+		}
+	}
+}
+
+// lintWaivers checks that every waiver justifies a waived rule and has a valid expiry date.
+func (l *scopeLinter) lintWaivers(scope, base AssessmentScope) {
+	var waived []string
+	for _, layer := range []AssessmentScope{base, scope} {
+		for _, rule := range layer.GlobalWaiveRules {
+			waived = AppendUnique(waived, rule)
+		}
+		for _, entry := range layer.IncludeControls {
+			for _, rule := range entry.WaiveRules {
+				waived = AppendUnique(waived, rule)
+			}
+		}
+	}
+	for i, waiver := range scope.Waivers {
+		path := fmt.Sprintf("$.waivers[%d]", i)
+		switch {
+		case waiver.Rule == "":
+			l.addIssue(path, "", "waiver rule must be set")
+		case strings.HasPrefix(waiver.Rule, scopeRemovePrefix):
+			continue
+		case waiver.Rule != scopeWildcard && !isWaivedRule(waiver.Rule, waived):
+			l.addIssue(path+".rule", closestMatch(waiver.Rule, waived),
+				"waiver of rule %q has no effect: the rule is not in waiveRules or globalWaiveRules", waiver.Rule)
+		}
+		if _, err := waiver.Expired(time.Now()); err != nil {
+			l.addIssue(path+".expires", "", "%v", err)
+		}
+	}
+}
+
+// isWaivedRule returns true if the waiver rule is waived, or if its pattern matches a waived rule.
+func isWaivedRule(waiverRule string, waived []string) bool {
+	if MatchesRule(waiverRule, waived) {
+		return true
+	}
+	if !isPattern(waiverRule) {
+		return false
+	}
+	for _, rule := range waived {
+		if rule == waiverRule || MatchesRule(rule, []string{waiverRule}) {
+			return true
+		}
+	}
+	return false
+}
+
+func (l *scopeLinter) addIssue(path, suggestion, format string, args ...any) {
+	l.issues = append(l.issues, ScopeIssue{
+		Line:       l.line(path),
+		Path:       path,
+		Message:    fmt.Sprintf(format, args...),
+		Suggestion: suggestion,
+	})
+}
+
+// line returns the line of the value at the YAML path, or 0 if it cannot be found.
+func (l *scopeLinter) line(path string) int {
+	yamlPath, err := yaml.PathString(path)
+	if err != nil {
+		return 0
+	}
+	node, err := yamlPath.FilterFile(l.file)
+	if err != nil || node == nil || node.GetToken() == nil {
+		return 0
+	}
+	return node.GetToken().Position.Line
+}
+
+// frameworkIDs returns the sorted IDs of the frameworks implemented in the component definitions.
+func frameworkIDs(cds []oscalTypes.ComponentDefinition) []string {
+	var frameworks []string
+	for _, componentDef := range cds {
+		if componentDef.Components == nil {
+			continue
+		}
+		for _, component := range *componentDef.Components {
+			if component.ControlImplementations == nil {
+				continue
+			}
+			for _, ci := range *component.ControlImplementations {
+				if ci.Props == nil {
+					continue
+				}
+				if frameworkProp, found := extensions.GetTrestleProp(extensions.FrameworkProp, *ci.Props); found {
+					frameworks = AppendUnique(frameworks, frameworkProp.Value)
+				}
+			}
+		}
+	}
+	sort.Strings(frameworks)
+	return frameworks
+}
+
+// closestMatch returns the candidate closest to the value by edit distance, or an empty string
+// if no candidate is close enough to be a likely typo.
+func closestMatch(value string, candidates []string) string {
+	if value == "" {
+		return ""
+	}
+	maxDistance := max(len(value)/3, 2)
+	var closest string
+	closestDistance := maxDistance + 1
+	for _, candidate := range candidates {
+		distance := editDistance(strings.ToLower(value), strings.ToLower(candidate))
+		if distance < closestDistance {
+			closest, closestDistance = candidate, distance
+		}
+	}
+	return closest
+}
+
+// editDistance returns the Levenshtein distance between two strings.
+func editDistance(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	previous := make([]int, len(rb)+1)
+	current := make([]int, len(rb)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		current[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+		previous, current = current, previous
+	}
+	return previous[len(rb)]
+}
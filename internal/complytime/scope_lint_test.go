@@ -0,0 +1,330 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package complytime
+
+import (
+	"path/filepath"
+	"testing"
+
+	oscalTypes "github.com/defenseunicorns/go-oscal/src/types/oscal-1-1-3"
+	"github.com/oscal-compass/oscal-sdk-go/extensions"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestLintComponentDefinition returns a component definition of the "example" framework
+// with control ac-1 implemented by two rules and ac-2 implemented by a single rule.
+func newTestLintComponentDefinition() oscalTypes.ComponentDefinition {
+	ruleProps := func(rules ...string) *[]oscalTypes.Property {
+		var props []oscalTypes.Property
+		for _, rule := range rules {
+			props = append(props, oscalTypes.Property{Name: extensions.RuleIdProp, Value: rule, Ns: extensions.TrestleNameSpace})
+		}
+		return &props
+	}
+	return oscalTypes.ComponentDefinition{
+		Components: &[]oscalTypes.DefinedComponent{
+			{
+				Title: "Component",
+				Props: &[]oscalTypes.Property{
+					{Name: extensions.RuleIdProp, Value: "sshd_disable_root_login", Remarks: "rule_set_00"},
+					{Name: extensions.ParameterIdProp, Value: "var_sshd_timeout", Remarks: "rule_set_00"},
+					{Name: "Parameter_Value_Alternatives", Value: `{"300": "300", "600": "600"}`, Remarks: "rule_set_00"},
+					{Name: extensions.RuleIdProp, Value: "sshd_set_idle_timeout", Remarks: "rule_set_01"},
+					{Name: extensions.RuleIdProp, Value: "service_auditd_enabled", Remarks: "rule_set_02"},
+				},
+				ControlImplementations: &[]oscalTypes.ControlImplementationSet{
+					{
+						Props: &[]oscalTypes.Property{{Name: extensions.FrameworkProp, Value: "example", Ns: extensions.TrestleNameSpace}},
+						ImplementedRequirements: []oscalTypes.ImplementedRequirementControlImplementation{
+							{ControlId: "ac-1", Props: ruleProps("sshd_disable_root_login", "sshd_set_idle_timeout")},
+							{ControlId: "ac-2", Props: ruleProps("service_auditd_enabled")},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func TestLintAssessmentScope(t *testing.T) {
+	cds := []oscalTypes.ComponentDefinition{newTestLintComponentDefinition()}
+	controlGroups := map[string][]string{"ac": {"ac-1", "ac-2"}}
+
+	tests := []struct {
+		name       string
+		config     string
+		wantIssues []ScopeIssue
+		wantErr    string
+	}{
+		{
+			name: "Valid/NoIssues",
+			config: `frameworkId: example
+includeControls:
+- controlId: ac-1
+  includeRules:
+  - "*"
+  excludeRules:
+  - sshd_set_idle_timeout
+  selectParameters:
+  - name: var_sshd_timeout
+    value: "600"
+- controlId: ac-2
+  includeRules:
+  - "*"
+  selectParameters:
+  - name: N/A
+    value: N/A
+globalWaiveRules:
+- service_auditd_enabled
+waivers:
+- rule: service_auditd_*
+  reason: Audit is handled by the host
+`,
+		},
+		{
+			name: "Invalid/Typos",
+			config: `frameworkId: example
+includeControls:
+- controlId: ac-1
+  includeRules:
+  - "*"
+  excludeRules:
+  - sshd_disable_rot_login
+  waiveRules:
+  - service_auditd_enabled
+  selectParameters:
+  - name: var_sshd_timout
+    value: "600"
+- controlId: ac-3
+  includeRules:
+  - "*"
+globalExcludeRules:
+- service_audit_enabled
+`,
+			wantIssues: []ScopeIssue{
+				{
+					Line:       7,
+					Path:       "$.includeControls[0].excludeRules[0]",
+					Message:    `rule "sshd_disable_rot_login" is not defined in the component definitions`,
+					Suggestion: "sshd_disable_root_login",
+				},
+				{
+					Line:    9,
+					Path:    "$.includeControls[0].waiveRules[0]",
+					Message: `rule "service_auditd_enabled" is not implemented by control "ac-1"`,
+				},
+				{
+					Line:       11,
+					Path:       "$.includeControls[0].selectParameters[0].name",
+					Message:    `parameter "var_sshd_timout" is not used by the rules of control "ac-1"`,
+					Suggestion: "var_sshd_timeout",
+				},
+				{
+					Line:       13,
+					Path:       "$.includeControls[1].controlId",
+					Message:    `control "ac-3" is not implemented for framework "example"`,
+					Suggestion: "ac-1",
+				},
+				{
+					Line:       17,
+					Path:       "$.globalExcludeRules[0]",
+					Message:    `rule "service_audit_enabled" is not implemented by any control of framework "example"`,
+					Suggestion: "service_auditd_enabled",
+				},
+			},
+		},
+		{
+			name: "Invalid/ParameterValue",
+			config: `frameworkId: example
+includeControls:
+- controlId: ac-1
+  includeRules:
+  - "*"
+  selectParameters:
+  - name: var_sshd_timeout
+    value: "60"
+`,
+			wantIssues: []ScopeIssue{
+				{
+					Line:       8,
+					Path:       "$.includeControls[0].selectParameters[0].value",
+					Message:    `value "60" is not a valid alternative for parameter "var_sshd_timeout": must be one of 300, 600`,
+					Suggestion: "600",
+				},
+			},
+		},
+		{
+			name: "Valid/Patterns",
+			config: `frameworkId: example
+includeControls:
+- controlId: AC-*
+  includeRules:
+  - sshd_*
+  waiveRules:
+  - /^service_/
+- controlId: group:ac
+  includeRules:
+  - "*"
+globalExcludeRules:
+- "*_idle_*"
+waivers:
+- rule: service_auditd_enabled
+  reason: Not applicable to containers
+`,
+		},
+		{
+			name: "Invalid/Patterns",
+			config: `frameworkId: example
+includeControls:
+- controlId: group:ad
+  includeRules:
+  - "*"
+- controlId: cm-*
+  includeRules:
+  - "*"
+- controlId: ac-1
+  includeRules:
+  - package_*
+  excludeRules:
+  - /sshd_[/
+`,
+			wantIssues: []ScopeIssue{
+				{
+					Line:       3,
+					Path:       "$.includeControls[0].controlId",
+					Message:    `invalid control selector "group:ad": catalog group "ad" is not defined`,
+					Suggestion: "group:ac",
+				},
+				{
+					Line:    6,
+					Path:    "$.includeControls[1].controlId",
+					Message: `control selector "cm-*" matches no control of framework "example"`,
+				},
+				{
+					Line:    11,
+					Path:    "$.includeControls[2].includeRules[0]",
+					Message: `rule pattern "package_*" matches no rule of control "ac-1"`,
+				},
+				{
+					Line:    13,
+					Path:    "$.includeControls[2].excludeRules[0]",
+					Message: "invalid regular expression \"/sshd_[/\": error parsing regexp: missing closing ]: `[`",
+				},
+			},
+		},
+		{
+			name: "Invalid/Waivers",
+			config: `frameworkId: example
+includeControls:
+- controlId: ac-2
+  includeRules:
+  - "*"
+  waiveRules:
+  - service_auditd_enabled
+waivers:
+- rule: service_auditd_enable
+  reason: Not applicable to containers
+- rule: service_auditd_enabled
+  expires: 31-12-2025
+`,
+			wantIssues: []ScopeIssue{
+				{
+					Line:       9,
+					Path:       "$.waivers[0].rule",
+					Message:    `waiver of rule "service_auditd_enable" has no effect: the rule is not in waiveRules or globalWaiveRules`,
+					Suggestion: "service_auditd_enabled",
+				},
+				{
+					Line:    12,
+					Path:    "$.waivers[1].expires",
+					Message: `invalid expiry date "31-12-2025" of the waiver for rule "service_auditd_enabled": must be YYYY-MM-DD`,
+				},
+			},
+		},
+		{
+			name: "Invalid/Framework",
+			config: `frameworkId: exampel
+includeControls: []
+`,
+			wantIssues: []ScopeIssue{
+				{
+					Line:       1,
+					Path:       "$.frameworkId",
+					Message:    `framework "exampel" is not defined in the component definitions`,
+					Suggestion: "example",
+				},
+			},
+		},
+		{
+			name:    "Invalid/UnknownField",
+			config:  "frameworkId: example\nincludeControl: []\n",
+			wantErr: "invalid assessment scope: [2:1] unknown field \"includeControl\"",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			issues, err := LintAssessmentScope([]byte(tt.config), cds, controlGroups)
+			if tt.wantErr != "" {
+				require.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.wantIssues, issues)
+		})
+	}
+}
+
+func TestClosestMatch(t *testing.T) {
+	candidates := []string{"sshd_disable_root_login", "sshd_set_idle_timeout"}
+	require.Equal(t, "sshd_disable_root_login", closestMatch("SSHD_DISABLE_ROOT_LOGN", candidates))
+	require.Equal(t, "", closestMatch("package_aide_installed", candidates))
+	require.Equal(t, "", closestMatch("", candidates))
+}
+
+func TestLintLayeredScope(t *testing.T) {
+	cds := []oscalTypes.ComponentDefinition{newTestLintComponentDefinition()}
+	dir := writeScopeConfigs(t, map[string]string{
+		"base.yml": `frameworkId: example
+includeControls:
+- controlId: ac-1
+  includeRules:
+  - "*"
+globalWaiveRules:
+- service_auditd_enabled
+`,
+		"prod.yml": `extends: base.yml
+includeControls:
+- controlId: "!ac-2"
+- controlId: ac-1
+  excludeRules:
+  - "!sshd_set_idle_timeout"
+  selectParameters:
+  - name: "!var_sshd_timout"
+waivers:
+- rule: service_auditd_enabled
+  reason: Not applicable to containers
+`,
+	})
+	layered, err := LoadLayeredScope(filepath.Join(dir, "prod.yml"))
+	require.NoError(t, err)
+
+	issues, err := LintLayeredScope(layered, cds, nil)
+	require.NoError(t, err)
+	prodPath := filepath.Join(dir, "prod.yml")
+	require.Equal(t, []ScopeIssue{
+		{
+			File:       prodPath,
+			Line:       3,
+			Path:       "$.includeControls[0].controlId",
+			Message:    `control "ac-2" cannot be removed: it is not included by the scope configs before this one`,
+			Suggestion: "ac-1",
+		},
+		{
+			File:       prodPath,
+			Line:       8,
+			Path:       "$.includeControls[1].selectParameters[0].name",
+			Message:    `parameter "var_sshd_timout" is not used by the rules of control "ac-1"`,
+			Suggestion: "var_sshd_timeout",
+		},
+	}, issues)
+}
@@ -988,6 +988,77 @@ func TestFilterParameterSelection(t *testing.T) {
 	}
 }
 
+func TestParameterAlternatives(t *testing.T) {
+	cd := oscalTypes.ComponentDefinition{
+		Components: &[]oscalTypes.DefinedComponent{
+			{
+				Title: "Component",
+				Props: &[]oscalTypes.Property{
+					{Name: extensions.RuleIdProp, Value: "rule-1", Remarks: "remarks-group-1"},
+					{Name: extensions.ParameterIdProp, Value: "param-1", Remarks: "remarks-group-1"},
+					{Name: "Parameter_Value_Alternatives", Value: `{"300": "300", "600": "600"}`, Remarks: "remarks-group-1"},
+					{Name: extensions.RuleIdProp, Value: "rule-2", Remarks: "remarks-group-2"},
+					{Name: extensions.ParameterIdProp, Value: "param-2", Remarks: "remarks-group-2"},
+				},
+			},
+		},
+	}
+	require.Equal(t, []string{"300", "600"}, ParameterAlternatives("param-1", []oscalTypes.ComponentDefinition{cd}))
+	require.Empty(t, ParameterAlternatives("param-2", []oscalTypes.ComponentDefinition{cd}))
+}
+
+func TestControlRules(t *testing.T) {
+	frameworkProps := func(framework string) *[]oscalTypes.Property {
+		return &[]oscalTypes.Property{{Name: extensions.FrameworkProp, Value: framework, Ns: extensions.TrestleNameSpace}}
+	}
+	ruleProps := func(rules ...string) *[]oscalTypes.Property {
+		var props []oscalTypes.Property
+		for _, rule := range rules {
+			props = append(props, oscalTypes.Property{Name: extensions.RuleIdProp, Value: rule, Ns: extensions.TrestleNameSpace})
+		}
+		return &props
+	}
+	cd := oscalTypes.ComponentDefinition{
+		Components: &[]oscalTypes.DefinedComponent{
+			{
+				Title: "Component",
+				ControlImplementations: &[]oscalTypes.ControlImplementationSet{
+					{
+						Props: frameworkProps("example"),
+						ImplementedRequirements: []oscalTypes.ImplementedRequirementControlImplementation{
+							{ControlId: "ac-1", Props: ruleProps("rule-1", "rule-2")},
+							{ControlId: "ac-2", Props: ruleProps("rule-2")},
+							{ControlId: "ac-3"},
+						},
+					},
+					{
+						Props: frameworkProps("other"),
+						ImplementedRequirements: []oscalTypes.ImplementedRequirementControlImplementation{
+							{ControlId: "ac-1", Props: ruleProps("rule-3")},
+						},
+					},
+				},
+			},
+			{
+				Title: "Validation Component",
+				ControlImplementations: &[]oscalTypes.ControlImplementationSet{
+					{
+						Props: frameworkProps("example"),
+						ImplementedRequirements: []oscalTypes.ImplementedRequirementControlImplementation{
+							{ControlId: "ac-1", Props: ruleProps("rule-1")},
+						},
+					},
+				},
+			},
+		},
+	}
+	require.Equal(t, map[string][]string{
+		"ac-1": {"rule-1", "rule-2"},
+		"ac-2": {"rule-2"},
+		"ac-3": nil,
+	}, ControlRules("example", []oscalTypes.ComponentDefinition{cd}))
+}
+
 func TestAssessmentScope_ApplyRuleScope(t *testing.T) {
 	testLogger := hclog.NewNullLogger()
 
@@ -0,0 +1,318 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package complytime
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+
+	oscalTypes "github.com/defenseunicorns/go-oscal/src/types/oscal-1-1-3"
+	"github.com/oscal-compass/oscal-sdk-go/extensions"
+	"github.com/oscal-compass/oscal-sdk-go/validation"
+)
+
+// controlGroupPrefix selects the controls of a catalog group in the assessment scope, such as "group:ac".
+const controlGroupPrefix = "group:"
+
+// isPattern returns true if the ID in the assessment scope is a glob or a regular expression
+// between slashes rather than a literal ID. The literal "*" wildcard is not a pattern.
+func isPattern(id string) bool {
+	return id != scopeWildcard && (isRegexPattern(id) || strings.ContainsAny(id, "*?["))
+}
+
+// isControlSelector returns true if the control ID in the assessment scope selects several controls.
+func isControlSelector(id string) bool {
+	return strings.HasPrefix(id, controlGroupPrefix) || isPattern(id)
+}
+
+func isRegexPattern(id string) bool {
+	return len(id) > 2 && strings.HasPrefix(id, "/") && strings.HasSuffix(id, "/")
+}
+
+// matchPattern returns the candidates matched by the glob or regular expression, in the order of the candidates.
+// Regular expressions match anywhere in the ID unless they are anchored.
+func matchPattern(pattern string, candidates []string, ignoreCase bool) ([]string, error) {
+	var match func(string) bool
+	if isRegexPattern(pattern) {
+		expr := strings.TrimSuffix(strings.TrimPrefix(pattern, "/"), "/")
+		if ignoreCase {
+			expr = "(?i)" + expr
+		}
+		re, err := regexp.Compile(expr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid regular expression %q: %w", pattern, err)
+		}
+		match = re.MatchString
+	} else {
+		glob := pattern
+		if ignoreCase {
+			glob = strings.ToLower(glob)
+		}
+		if _, err := path.Match(glob, ""); err != nil {
+			return nil, fmt.Errorf("invalid pattern %q: %w", pattern, err)
+		}
+		match = func(id string) bool {
+			if ignoreCase {
+				id = strings.ToLower(id)
+			}
+			matched, _ := path.Match(glob, id)
+			return matched
+		}
+	}
+	var matches []string
+	for _, candidate := range candidates {
+		if match(candidate) {
+			matches = append(matches, candidate)
+		}
+	}
+	return matches, nil
+}
+
+// MatchesRule returns true if the rule is in the list, matched by a pattern of the list, or the list has "*".
+func MatchesRule(ruleID string, rules []string) bool {
+	for _, rule := range rules {
+		if rule == ruleID || rule == scopeWildcard {
+			return true
+		}
+		if isPattern(rule) {
+			if matches, err := matchPattern(rule, []string{ruleID}, false); err == nil && len(matches) > 0 {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// selectControls returns the controls selected by a catalog group or a pattern. Patterns on control
+// IDs ignore case so that "AC-*" selects the "ac" family.
+func selectControls(selector string, controls []string, controlGroups map[string][]string) ([]string, error) {
+	if groupID, found := strings.CutPrefix(selector, controlGroupPrefix); found {
+		members, found := controlGroups[groupID]
+		if !found {
+			return nil, fmt.Errorf("catalog group %q is not defined", groupID)
+		}
+		var selected []string
+		for _, control := range controls {
+			for _, member := range members {
+				if member == control {
+					selected = append(selected, control)
+					break
+				}
+			}
+		}
+		return selected, nil
+	}
+	return matchPattern(selector, controls, true)
+}
+
+// expandRules replaces the patterns in the rules with the candidates they match. Patterns
+// matching no candidate are kept so that they still match no rule when the scope is applied.
+// record is called with each rule or pattern and the rules it was replaced with.
+func expandRules(rules, candidates []string, record func(rule, expanded string)) ([]string, error) {
+	var expanded []string
+	for _, rule := range rules {
+		if !isPattern(rule) {
+			expanded = AppendUnique(expanded, rule)
+			record(rule, rule)
+			continue
+		}
+		matches, err := matchPattern(rule, candidates, false)
+		if err != nil {
+			return nil, err
+		}
+		if len(matches) == 0 {
+			expanded = AppendUnique(expanded, rule)
+			record(rule, rule)
+			continue
+		}
+		for _, match := range matches {
+			expanded = AppendUnique(expanded, match)
+			record(rule, match)
+		}
+	}
+	return expanded, nil
+}
+
+// ExpandPatterns returns a copy of the assessment scope with the control selectors and rule patterns
+// replaced by the IDs they match in the framework. controlRules holds the rules of each control, as
+// returned by ControlRules, and controlGroups the controls of each catalog group, as returned by
+// ControlGroups. A control selector adds an entry for each control it matches, unless the control has
+// its own entry. Rule patterns are matched against the rules of each control, and against all rules of
+// the framework in the global lists.
+func (a AssessmentScope) ExpandPatterns(controlRules, controlGroups map[string][]string) (AssessmentScope, error) {
+	return a.expandPatterns(controlRules, controlGroups, func(_, _ string) {})
+}
+
+// expandPatterns expands the assessment scope like ExpandPatterns. record is called with the origin key
+// of each value of the scope and the origin key of the values it was expanded to.
+func (a AssessmentScope) expandPatterns(controlRules, controlGroups map[string][]string, record func(from, to string)) (AssessmentScope, error) {
+	controls := make([]string, 0, len(controlRules))
+	var allRules []string
+	for control, rules := range controlRules {
+		controls = append(controls, control)
+		for _, rule := range rules {
+			allRules = AppendUnique(allRules, rule)
+		}
+	}
+	sort.Strings(controls)
+	sort.Strings(allRules)
+
+	listed := make(map[string]bool)
+	for _, entry := range a.IncludeControls {
+		if !isControlSelector(entry.ControlID) {
+			listed[entry.ControlID] = true
+		}
+	}
+
+	expanded := a
+	expanded.IncludeControls = nil
+	selected := make(map[string]bool)
+	for _, entry := range a.IncludeControls {
+		controlIDs := []string{entry.ControlID}
+		if isControlSelector(entry.ControlID) {
+			matches, err := selectControls(entry.ControlID, controls, controlGroups)
+			if err != nil {
+				return AssessmentScope{}, fmt.Errorf("invalid control selector %q: %w", entry.ControlID, err)
+			}
+			controlIDs = nil
+			for _, match := range matches {
+				if !listed[match] && !selected[match] {
+					selected[match] = true
+					controlIDs = append(controlIDs, match)
+				}
+			}
+		}
+		for _, controlID := range controlIDs {
+			controlEntry, err := expandControlEntry(entry, controlID, controlRules[controlID], record)
+			if err != nil {
+				return AssessmentScope{}, err
+			}
+			expanded.IncludeControls = append(expanded.IncludeControls, controlEntry)
+		}
+	}
+
+	for _, list := range []struct {
+		name  string
+		rules *[]string
+	}{
+		{"globalExcludeRules", &expanded.GlobalExcludeRules},
+		{"globalWaiveRules", &expanded.GlobalWaiveRules},
+	} {
+		var err error
+		*list.rules, err = expandRules(*list.rules, allRules, func(rule, match string) {
+			record(originKey(list.name, rule), originKey(list.name, match))
+		})
+		if err != nil {
+			return AssessmentScope{}, err
+		}
+	}
+	return expanded, nil
+}
+
+// expandControlEntry returns the entry for the control with its rule patterns matched against the rules of the control.
+func expandControlEntry(entry ControlEntry, controlID string, rules []string, record func(from, to string)) (ControlEntry, error) {
+	from, to := originKey("includeControls", entry.ControlID), originKey("includeControls", controlID)
+	record(from, to)
+	for _, parameter := range entry.SelectParameters {
+		record(originKey(from, "selectParameters", parameter.Name), originKey(to, "selectParameters", parameter.Name))
+	}
+	if entry.ControlID != controlID {
+		entry.ControlID = controlID
+		entry.ControlTitle = ""
+	} else {
+		record(originKey(from, "controlTitle"), originKey(to, "controlTitle"))
+	}
+	for _, list := range []struct {
+		name  string
+		rules *[]string
+	}{
+		{"includeRules", &entry.IncludeRules},
+		{"excludeRules", &entry.ExcludeRules},
+		{"waiveRules", &entry.WaiveRules},
+	} {
+		var err error
+		*list.rules, err = expandRules(*list.rules, rules, func(rule, match string) {
+			record(originKey(from, list.name, rule), originKey(to, list.name, match))
+		})
+		if err != nil {
+			return ControlEntry{}, err
+		}
+	}
+	return entry, nil
+}
+
+// ControlGroups returns the IDs of the controls in each group of the catalogs of the framework, by group ID.
+// Nested groups and control enhancements are included in their parent groups. Catalogs that cannot be
+// loaded are skipped.
+func ControlGroups(frameworkID string, appDir ApplicationDirectory, validator validation.Validator, cds ...oscalTypes.ComponentDefinition) map[string][]string {
+	controlGroups := make(map[string][]string)
+	loadedSources := make(map[string]bool)
+	for _, componentDef := range cds {
+		if componentDef.Components == nil {
+			continue
+		}
+		for _, component := range *componentDef.Components {
+			if component.ControlImplementations == nil {
+				continue
+			}
+			for _, ci := range *component.ControlImplementations {
+				if ci.Props == nil || loadedSources[ci.Source] {
+					continue
+				}
+				frameworkProp, found := extensions.GetTrestleProp(extensions.FrameworkProp, *ci.Props)
+				if !found || frameworkProp.Value != frameworkID {
+					continue
+				}
+				loadedSources[ci.Source] = true
+				profile, err := LoadProfile(appDir, ci.Source, validator)
+				if err != nil || profile.Imports == nil {
+					continue
+				}
+				for _, imp := range profile.Imports {
+					catalog, err := LoadCatalogSource(appDir, imp.Href, validator)
+					if err != nil || catalog.Groups == nil {
+						continue
+					}
+					for _, group := range *catalog.Groups {
+						addGroupControls(group, controlGroups)
+					}
+				}
+			}
+		}
+	}
+	return controlGroups
+}
+
+// addGroupControls adds the controls of the group and its nested groups, and returns them.
+func addGroupControls(group oscalTypes.Group, controlGroups map[string][]string) []string {
+	var controls []string
+	if group.Controls != nil {
+		controls = append(controls, nestedControlIDs(*group.Controls)...)
+	}
+	if group.Groups != nil {
+		for _, nested := range *group.Groups {
+			controls = append(controls, addGroupControls(nested, controlGroups)...)
+		}
+	}
+	if group.ID != "" {
+		for _, control := range controls {
+			controlGroups[group.ID] = AppendUnique(controlGroups[group.ID], control)
+		}
+	}
+	return controls
+}
+
+// nestedControlIDs returns the IDs of the controls and their enhancements.
+func nestedControlIDs(controls []oscalTypes.Control) []string {
+	var ids []string
+	for _, control := range controls {
+		ids = append(ids, control.ID)
+		if control.Controls != nil {
+			ids = append(ids, nestedControlIDs(*control.Controls)...)
+		}
+	}
+	return ids
+}
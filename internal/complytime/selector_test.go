@@ -0,0 +1,147 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package complytime
+
+import (
+	"testing"
+
+	oscalTypes "github.com/defenseunicorns/go-oscal/src/types/oscal-1-1-3"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAssessmentScope_ExpandPatterns(t *testing.T) {
+	controlRules := map[string][]string{
+		"ac-1": {"sshd_disable_root_login", "sshd_set_idle_timeout"},
+		"ac-2": {"service_auditd_enabled", "sshd_set_idle_timeout"},
+		"cm-1": {"package_aide_installed"},
+	}
+	controlGroups := map[string][]string{
+		"ac": {"ac-1", "ac-2", "ac-3"},
+		"cm": {"cm-1"},
+	}
+
+	tests := []struct {
+		name    string
+		scope   AssessmentScope
+		want    AssessmentScope
+		wantErr string
+	}{
+		{
+			name: "Valid/Literal",
+			scope: AssessmentScope{
+				FrameworkID:     "example",
+				IncludeControls: []ControlEntry{{ControlID: "ac-1", ControlTitle: "Policy", IncludeRules: []string{"*"}}},
+			},
+			want: AssessmentScope{
+				FrameworkID:     "example",
+				IncludeControls: []ControlEntry{{ControlID: "ac-1", ControlTitle: "Policy", IncludeRules: []string{"*"}}},
+			},
+		},
+		{
+			name: "Valid/ControlFamily",
+			scope: AssessmentScope{
+				FrameworkID: "example",
+				IncludeControls: []ControlEntry{
+					{ControlID: "ac-2", IncludeRules: []string{"service_auditd_enabled"}},
+					{ControlID: "AC-*", IncludeRules: []string{"sshd_*"}, WaiveRules: []string{"/idle/"}},
+				},
+				GlobalExcludeRules: []string{"package_*"},
+			},
+			want: AssessmentScope{
+				FrameworkID: "example",
+				IncludeControls: []ControlEntry{
+					{ControlID: "ac-2", IncludeRules: []string{"service_auditd_enabled"}},
+					{
+						ControlID:    "ac-1",
+						IncludeRules: []string{"sshd_disable_root_login", "sshd_set_idle_timeout"},
+						WaiveRules:   []string{"sshd_set_idle_timeout"},
+					},
+				},
+				GlobalExcludeRules: []string{"package_aide_installed"},
+			},
+		},
+		{
+			name: "Valid/CatalogGroup",
+			scope: AssessmentScope{
+				FrameworkID:      "example",
+				IncludeControls:  []ControlEntry{{ControlID: "group:ac", IncludeRules: []string{"*"}, ExcludeRules: []string{"/^sshd_(disable|enable)_/"}}},
+				GlobalWaiveRules: []string{"*"},
+			},
+			want: AssessmentScope{
+				FrameworkID: "example",
+				IncludeControls: []ControlEntry{
+					{ControlID: "ac-1", IncludeRules: []string{"*"}, ExcludeRules: []string{"sshd_disable_root_login"}},
+					{ControlID: "ac-2", IncludeRules: []string{"*"}, ExcludeRules: []string{"/^sshd_(disable|enable)_/"}},
+				},
+				GlobalWaiveRules: []string{"*"},
+			},
+		},
+		{
+			name: "Valid/NoMatch",
+			scope: AssessmentScope{
+				FrameworkID:     "example",
+				IncludeControls: []ControlEntry{{ControlID: "sc-*"}, {ControlID: "cm-1", IncludeRules: []string{"sshd_*"}}},
+			},
+			want: AssessmentScope{
+				FrameworkID:     "example",
+				IncludeControls: []ControlEntry{{ControlID: "cm-1", IncludeRules: []string{"sshd_*"}}},
+			},
+		},
+		{
+			name: "Invalid/Group",
+			scope: AssessmentScope{
+				FrameworkID:     "example",
+				IncludeControls: []ControlEntry{{ControlID: "group:sc"}},
+			},
+			wantErr: "invalid control selector \"group:sc\": catalog group \"sc\" is not defined",
+		},
+		{
+			name: "Invalid/Glob",
+			scope: AssessmentScope{
+				FrameworkID:        "example",
+				GlobalExcludeRules: []string{"sshd_[a-"},
+			},
+			wantErr: "invalid pattern \"sshd_[a-\": syntax error in pattern",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			expanded, err := tt.scope.ExpandPatterns(controlRules, controlGroups)
+			if tt.wantErr != "" {
+				require.EqualError(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.want, expanded)
+		})
+	}
+}
+
+func TestMatchesRule(t *testing.T) {
+	require.True(t, MatchesRule("sshd_set_idle_timeout", []string{"sshd_set_idle_timeout"}))
+	require.True(t, MatchesRule("sshd_set_idle_timeout", []string{"package_*", "sshd_*"}))
+	require.True(t, MatchesRule("sshd_set_idle_timeout", []string{"/idle/"}))
+	require.True(t, MatchesRule("sshd_set_idle_timeout", []string{"*"}))
+	require.False(t, MatchesRule("sshd_set_idle_timeout", []string{"SSHD_*", "/^idle/"}))
+	require.False(t, MatchesRule("sshd_set_idle_timeout", nil))
+}
+
+func TestAddGroupControls(t *testing.T) {
+	group := oscalTypes.Group{
+		ID: "ac",
+		Controls: &[]oscalTypes.Control{
+			{ID: "ac-1"},
+			{ID: "ac-2", Controls: &[]oscalTypes.Control{{ID: "ac-2.1"}}},
+		},
+		Groups: &[]oscalTypes.Group{
+			{ID: "ac-remote", Controls: &[]oscalTypes.Control{{ID: "ac-17"}}},
+		},
+	}
+	controlGroups := make(map[string][]string)
+	addGroupControls(group, controlGroups)
+	require.Equal(t, map[string][]string{
+		"ac":        {"ac-1", "ac-2", "ac-2.1", "ac-17"},
+		"ac-remote": {"ac-17"},
+	}, controlGroups)
+}
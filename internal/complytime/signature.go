@@ -0,0 +1,458 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package complytime
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	oscalTypes "github.com/defenseunicorns/go-oscal/src/types/oscal-1-1-3"
+	"github.com/goccy/go-yaml"
+	"github.com/oscal-compass/oscal-sdk-go/extensions"
+	"github.com/oscal-compass/oscal-sdk-go/validation"
+)
+
+const (
+	// DefaultTrustPolicyPath is the trust policy used to verify the signatures of compliance content.
+	DefaultTrustPolicyPath = "/etc/complytime/trust-policy.yaml"
+	// DefaultTrustedKeysDir contains the public keys trusted when no trust policy exists.
+	DefaultTrustedKeysDir = "/etc/complytime/keys/"
+
+	// SignatureSuffix is appended to the content file name for detached signatures.
+	SignatureSuffix = ".sig"
+	// SigstoreBundleSuffix is appended to the content file name for sigstore bundles.
+	SigstoreBundleSuffix    = ".sigstore.json"
+	sigstoreMediaTypePrefix = "application/vnd.dev.sigstore.bundle"
+
+	// Properties recording the signature verification in the assessment plan metadata
+	contentVerificationProp = "Content_Verification"
+	contentSignatureProp    = "Content_Signature"
+)
+
+// Signature statuses of compliance content
+const (
+	SignatureVerified = "verified"
+	SignatureUnsigned = "unsigned"
+	SignatureInvalid  = "invalid"
+)
+
+// ErrUnverifiedContent is returned when compliance content is unsigned or its signature cannot be verified.
+var ErrUnverifiedContent = errors.New("compliance content failed signature verification")
+
+// TrustPolicy defines the public keys trusted to sign compliance content.
+type TrustPolicy struct {
+	// Keys are the trusted public keys. Relative paths are resolved against the policy file.
+	Keys []TrustedKey `yaml:"keys"`
+	// Rules restrict the keys trusted for the content files matching a pattern.
+	// Content not matched by any rule can be signed by any trusted key.
+	Rules []TrustRule `yaml:"rules,omitempty"`
+}
+
+// TrustedKey is a named PEM encoded public key.
+type TrustedKey struct {
+	Name string `yaml:"name"`
+	Path string `yaml:"path"`
+}
+
+// TrustRule restricts the keys trusted for the content files matching the pattern. The pattern
+// is matched against the path of the file relative to the application directory.
+type TrustRule struct {
+	Pattern string   `yaml:"pattern"`
+	Keys    []string `yaml:"keys"`
+}
+
+// SignatureResult is the outcome of the signature verification of a content file.
+type SignatureResult struct {
+	// File is the path of the content relative to the application directory.
+	File   string
+	Digest string
+	Status string
+	// Key is the name of the trusted key that verified the signature.
+	Key     string
+	Message string
+}
+
+// SignatureVerifier verifies the detached signatures of compliance content with trusted public keys.
+type SignatureVerifier struct {
+	keys  map[string]crypto.PublicKey
+	rules []TrustRule
+}
+
+// NewSignatureVerifier returns a verifier trusting the keys of the policy at the given path. When the
+// policy does not exist, all *.pub and *.pem public keys in the keys directory are trusted for all content.
+func NewSignatureVerifier(policyPath, keysDir string) (*SignatureVerifier, error) {
+	policy, err := LoadTrustPolicy(policyPath)
+	switch {
+	case errors.Is(err, os.ErrNotExist):
+		policy, err = keysDirPolicy(keysDir)
+		if err != nil {
+			return nil, err
+		}
+	case err != nil:
+		return nil, err
+	}
+	if len(policy.Keys) == 0 {
+		return nil, fmt.Errorf("no trusted public keys found in trust policy %s or directory %s", policyPath, keysDir)
+	}
+
+	verifier := &SignatureVerifier{
+		keys:  make(map[string]crypto.PublicKey),
+		rules: policy.Rules,
+	}
+	for _, key := range policy.Keys {
+		keyPath := key.Path
+		if !filepath.IsAbs(keyPath) {
+			keyPath = filepath.Join(filepath.Dir(policyPath), keyPath)
+		}
+		publicKey, err := loadPublicKey(keyPath)
+		if err != nil {
+			return nil, fmt.Errorf("trusted key %s: %w", key.Name, err)
+		}
+		verifier.keys[key.Name] = publicKey
+	}
+	for _, rule := range policy.Rules {
+		if _, err := path.Match(rule.Pattern, ""); err != nil {
+			return nil, fmt.Errorf("trust rule %q: %w", rule.Pattern, err)
+		}
+		for _, name := range rule.Keys {
+			if _, ok := verifier.keys[name]; !ok {
+				return nil, fmt.Errorf("trust rule %q references unknown key %q", rule.Pattern, name)
+			}
+		}
+	}
+	return verifier, nil
+}
+
+// LoadTrustPolicy reads the trust policy at the given path.
+func LoadTrustPolicy(policyPath string) (*TrustPolicy, error) {
+	data, err := os.ReadFile(filepath.Clean(policyPath))
+	if err != nil {
+		return nil, err
+	}
+	var policy TrustPolicy
+	if err := yaml.UnmarshalWithOptions(data, &policy, yaml.Strict()); err != nil {
+		return nil, fmt.Errorf("invalid trust policy %s: %s", policyPath, yaml.FormatError(err, false, false))
+	}
+	for _, key := range policy.Keys {
+		if key.Name == "" || key.Path == "" {
+			return nil, fmt.Errorf("invalid trust policy %s: keys must have a name and path", policyPath)
+		}
+	}
+	return &policy, nil
+}
+
+// keysDirPolicy returns a policy trusting the public keys in the directory, named after their file.
+func keysDirPolicy(keysDir string) (*TrustPolicy, error) {
+	items, err := os.ReadDir(keysDir)
+	if errors.Is(err, os.ErrNotExist) {
+		return &TrustPolicy{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	policy := &TrustPolicy{}
+	for _, item := range items {
+		extension := filepath.Ext(item.Name())
+		if item.IsDir() || (extension != ".pub" && extension != ".pem") {
+			continue
+		}
+		policy.Keys = append(policy.Keys, TrustedKey{
+			Name: strings.TrimSuffix(item.Name(), extension),
+			Path: filepath.Join(keysDir, item.Name()),
+		})
+	}
+	return policy, nil
+}
+
+// loadPublicKey reads a PEM encoded ECDSA, Ed25519, or RSA public key.
+func loadPublicKey(keyPath string) (crypto.PublicKey, error) {
+	data, err := os.ReadFile(filepath.Clean(keyPath))
+	if err != nil {
+		return nil, err
+	}
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("%s is not a PEM encoded public key", keyPath)
+	}
+	var publicKey crypto.PublicKey
+	switch block.Type {
+	case "PUBLIC KEY":
+		publicKey, err = x509.ParsePKIXPublicKey(block.Bytes)
+	case "RSA PUBLIC KEY":
+		publicKey, err = x509.ParsePKCS1PublicKey(block.Bytes)
+	default:
+		return nil, fmt.Errorf("%s: unsupported PEM block type %q", keyPath, block.Type)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", keyPath, err)
+	}
+	switch publicKey.(type) {
+	case *ecdsa.PublicKey, ed25519.PublicKey, *rsa.PublicKey:
+		return publicKey, nil
+	default:
+		return nil, fmt.Errorf("%s: unsupported public key type %T", keyPath, publicKey)
+	}
+}
+
+// VerifyContent verifies the signatures of the component definitions in the bundle directory
+// and of the profiles and catalogs they reference.
+func (v *SignatureVerifier) VerifyContent(appDir ApplicationDirectory) ([]SignatureResult, error) {
+	files, err := contentFiles(appDir)
+	if err != nil {
+		return nil, err
+	}
+	var results []SignatureResult
+	for _, file := range files {
+		results = append(results, v.VerifyFile(file, contentName(appDir, file)))
+	}
+	return results, nil
+}
+
+// VerifyFile verifies the signature of the file with the keys trusted for the given name. A sigstore
+// bundle named <file>.sigstore.json takes precedence over a detached signature named <file>.sig.
+func (v *SignatureVerifier) VerifyFile(filePath, name string) SignatureResult {
+	result := SignatureResult{File: name, Status: SignatureInvalid}
+	content, err := os.ReadFile(filepath.Clean(filePath))
+	if err != nil {
+		result.Message = err.Error()
+		return result
+	}
+	digest := sha256.Sum256(content)
+	result.Digest = "sha256:" + hex.EncodeToString(digest[:])
+
+	signature, err := readSigstoreBundle(filePath+SigstoreBundleSuffix, digest[:])
+	if errors.Is(err, os.ErrNotExist) {
+		signature, err = readDetachedSignature(filePath + SignatureSuffix)
+	}
+	if errors.Is(err, os.ErrNotExist) {
+		result.Status = SignatureUnsigned
+		result.Message = fmt.Sprintf("no %s or %s signature found", SigstoreBundleSuffix, SignatureSuffix)
+		return result
+	}
+	if err != nil {
+		result.Message = err.Error()
+		return result
+	}
+
+	for _, keyName := range v.trustedKeys(name) {
+		if verifySignature(v.keys[keyName], content, digest[:], signature) {
+			result.Status = SignatureVerified
+			result.Key = keyName
+			return result
+		}
+	}
+	result.Message = "signature does not match the content or a trusted key"
+	return result
+}
+
+// trustedKeys returns the names of the keys trusted for the content file, sorted by name.
+func (v *SignatureVerifier) trustedKeys(name string) []string {
+	for _, rule := range v.rules {
+		if matched, _ := path.Match(rule.Pattern, filepath.ToSlash(name)); matched {
+			return rule.Keys
+		}
+	}
+	var names []string
+	for keyName := range v.keys {
+		names = append(names, keyName)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func verifySignature(publicKey crypto.PublicKey, content, digest, signature []byte) bool {
+	switch key := publicKey.(type) {
+	case *ecdsa.PublicKey:
+		return ecdsa.VerifyASN1(key, digest, signature)
+	case ed25519.PublicKey:
+		return ed25519.Verify(key, content, signature)
+	case *rsa.PublicKey:
+		return rsa.VerifyPKCS1v15(key, crypto.SHA256, digest, signature) == nil ||
+			rsa.VerifyPSS(key, crypto.SHA256, digest, signature, nil) == nil
+	}
+	return false
+}
+
+// readDetachedSignature reads a base64 encoded or raw signature.
+func readDetachedSignature(signaturePath string) ([]byte, error) {
+	data, err := os.ReadFile(filepath.Clean(signaturePath))
+	if err != nil {
+		return nil, err
+	}
+	if decoded, err := base64.StdEncoding.DecodeString(string(bytes.TrimSpace(data))); err == nil {
+		return decoded, nil
+	}
+	return data, nil
+}
+
+// sigstoreBundle holds the fields of a sigstore bundle used for key-based message signatures.
+type sigstoreBundle struct {
+	MediaType            string `json:"mediaType"`
+	VerificationMaterial struct {
+		PublicKey            *json.RawMessage `json:"publicKey"`
+		Certificate          *json.RawMessage `json:"certificate"`
+		X509CertificateChain *json.RawMessage `json:"x509CertificateChain"`
+	} `json:"verificationMaterial"`
+	MessageSignature *struct {
+		MessageDigest struct {
+			Algorithm string `json:"algorithm"`
+			Digest    []byte `json:"digest"`
+		} `json:"messageDigest"`
+		Signature []byte `json:"signature"`
+	} `json:"messageSignature"`
+}
+
+// readSigstoreBundle reads the signature of a sigstore bundle signed with a public key and checks
+// that the bundle was created for the content digest.
+func readSigstoreBundle(bundlePath string, digest []byte) ([]byte, error) {
+	data, err := os.ReadFile(filepath.Clean(bundlePath))
+	if err != nil {
+		return nil, err
+	}
+	var bundle sigstoreBundle
+	if err := json.Unmarshal(data, &bundle); err != nil {
+		return nil, fmt.Errorf("failed to parse sigstore bundle %s: %w", bundlePath, err)
+	}
+	if !strings.HasPrefix(bundle.MediaType, sigstoreMediaTypePrefix) {
+		return nil, fmt.Errorf("sigstore bundle %s has unsupported media type %q", bundlePath, bundle.MediaType)
+	}
+	if bundle.VerificationMaterial.PublicKey == nil {
+		return nil, fmt.Errorf("sigstore bundle %s: only bundles signed with a public key are supported", bundlePath)
+	}
+	if bundle.MessageSignature == nil {
+		return nil, fmt.Errorf("sigstore bundle %s: only message signatures are supported", bundlePath)
+	}
+	messageDigest := bundle.MessageSignature.MessageDigest
+	if messageDigest.Algorithm != "SHA2_256" {
+		return nil, fmt.Errorf("sigstore bundle %s has unsupported digest algorithm %q", bundlePath, messageDigest.Algorithm)
+	}
+	if !bytes.Equal(messageDigest.Digest, digest) {
+		return nil, fmt.Errorf("sigstore bundle %s was created for different content", bundlePath)
+	}
+	return bundle.MessageSignature.Signature, nil
+}
+
+// contentFiles returns the component definitions in the bundle directory and the profiles and
+// catalogs they reference. Sources that cannot be resolved are left to fail when they are loaded.
+func contentFiles(appDir ApplicationDirectory) ([]string, error) {
+	items, err := os.ReadDir(appDir.BundleDir())
+	if err != nil {
+		return nil, fmt.Errorf("unable to read bundle directory %s: %w", appDir.BundleDir(), err)
+	}
+	seen := make(map[string]struct{})
+	var files []string
+	var addSource func(href string)
+	addSource = func(href string) {
+		sourcePath, err := controlSourcePath(appDir, href)
+		if err != nil {
+			return
+		}
+		if _, ok := seen[sourcePath]; ok {
+			return
+		}
+		seen[sourcePath] = struct{}{}
+		files = append(files, sourcePath)
+		data, err := os.ReadFile(sourcePath)
+		if err != nil {
+			return
+		}
+		var oscalModels oscalTypes.OscalModels
+		if err := json.Unmarshal(data, &oscalModels); err != nil || oscalModels.Profile == nil {
+			return
+		}
+		for _, imported := range oscalModels.Profile.Imports {
+			if !strings.HasPrefix(imported.Href, "#") {
+				addSource(imported.Href)
+			}
+		}
+	}
+	for _, item := range items {
+		if !strings.HasSuffix(item.Name(), ComponentDefinitionSuffix) {
+			continue
+		}
+		compDefPath := filepath.Join(appDir.BundleDir(), item.Name())
+		files = append(files, compDefPath)
+		compDef, err := ReadComponentDefinition(compDefPath, validation.NoopValidator{})
+		if err != nil || compDef.Components == nil {
+			continue
+		}
+		for _, component := range *compDef.Components {
+			if component.ControlImplementations == nil {
+				continue
+			}
+			for _, implementation := range *component.ControlImplementations {
+				addSource(implementation.Source)
+			}
+		}
+	}
+	sort.Strings(files)
+	return files, nil
+}
+
+// contentName returns the path of the file relative to the application directory, if it is below it.
+func contentName(appDir ApplicationDirectory, file string) string {
+	name, err := filepath.Rel(appDir.AppDir(), file)
+	if err != nil || strings.HasPrefix(name, "..") {
+		return file
+	}
+	return name
+}
+
+// CheckSignatures returns an error listing the content that failed signature verification.
+func CheckSignatures(results []SignatureResult) error {
+	var failed []string
+	for _, result := range results {
+		if result.Status != SignatureVerified {
+			failed = append(failed, fmt.Sprintf("%s (%s: %s)", result.File, result.Status, result.Message))
+		}
+	}
+	if len(failed) > 0 {
+		return fmt.Errorf("%w: %s", ErrUnverifiedContent, strings.Join(failed, ", "))
+	}
+	return nil
+}
+
+// AddSignatureProps records the signature verification of the content in the assessment plan metadata.
+// When the verification is skipped, only the skipped status is recorded.
+func AddSignatureProps(plan *oscalTypes.AssessmentPlan, results []SignatureResult, skipped bool) {
+	if plan.Metadata.Props == nil {
+		plan.Metadata.Props = &[]oscalTypes.Property{}
+	}
+	verification := "enforced"
+	if skipped {
+		verification = "skipped"
+	}
+	*plan.Metadata.Props = append(*plan.Metadata.Props, oscalTypes.Property{
+		Name:  contentVerificationProp,
+		Value: verification,
+		Ns:    extensions.TrestleNameSpace,
+	})
+	for _, result := range results {
+		remarks := fmt.Sprintf("%s %s", result.File, result.Digest)
+		if result.Key != "" {
+			remarks = fmt.Sprintf("%s verified with key %s", remarks, result.Key)
+		}
+		*plan.Metadata.Props = append(*plan.Metadata.Props, oscalTypes.Property{
+			Name:    contentSignatureProp,
+			Value:   result.Status,
+			Ns:      extensions.TrestleNameSpace,
+			Remarks: remarks,
+		})
+	}
+}
@@ -0,0 +1,250 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package complytime
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"testing"
+
+	oscalTypes "github.com/defenseunicorns/go-oscal/src/types/oscal-1-1-3"
+	"github.com/oscal-compass/oscal-sdk-go/extensions"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestContent returns an application directory with the test component definition, profile, and catalog.
+func newTestContent(t *testing.T) ApplicationDirectory {
+	appDir, err := newApplicationDirectory(t.TempDir(), true)
+	require.NoError(t, err)
+	for _, file := range []string{
+		filepath.Join(BundlesDir, "example-component-definition.json"),
+		filepath.Join(ControlsDir, "sample-profile.json"),
+		filepath.Join(ControlsDir, "sample-catalog.json"),
+	} {
+		data, err := os.ReadFile(filepath.Join("testdata", "complytime", file))
+		require.NoError(t, err)
+		require.NoError(t, os.WriteFile(filepath.Join(appDir.AppDir(), file), data, 0600))
+	}
+	return appDir
+}
+
+// writeTestKey writes the PEM encoded public key of the signer to the directory.
+func writeTestKey(t *testing.T, dir, name string, signer crypto.Signer) {
+	der, err := x509.MarshalPKIXPublicKey(signer.Public())
+	require.NoError(t, err)
+	data := pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der})
+	require.NoError(t, os.WriteFile(filepath.Join(dir, name+".pub"), data, 0600))
+}
+
+// signTestFile writes a base64 encoded detached signature of the file.
+func signTestFile(t *testing.T, file string, signer crypto.Signer) {
+	signature := testSignature(t, file, signer)
+	require.NoError(t, os.WriteFile(file+SignatureSuffix, []byte(base64.StdEncoding.EncodeToString(signature)), 0600))
+}
+
+// writeTestSigstoreBundle writes a sigstore bundle with a message signature of the file.
+func writeTestSigstoreBundle(t *testing.T, file string, signer crypto.Signer) {
+	content, err := os.ReadFile(file)
+	require.NoError(t, err)
+	digest := sha256.Sum256(content)
+	bundle := map[string]any{
+		"mediaType": "application/vnd.dev.sigstore.bundle.v0.3+json",
+		"verificationMaterial": map[string]any{
+			"publicKey": map[string]string{"hint": "content-team"},
+		},
+		"messageSignature": map[string]any{
+			"messageDigest": map[string]any{"algorithm": "SHA2_256", "digest": digest[:]},
+			"signature":     testSignature(t, file, signer),
+		},
+	}
+	data, err := json.Marshal(bundle)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(file+SigstoreBundleSuffix, data, 0600))
+}
+
+func testSignature(t *testing.T, file string, signer crypto.Signer) []byte {
+	content, err := os.ReadFile(file)
+	require.NoError(t, err)
+	if _, ok := signer.(ed25519.PrivateKey); ok {
+		signature, err := signer.Sign(rand.Reader, content, crypto.Hash(0))
+		require.NoError(t, err)
+		return signature
+	}
+	digest := sha256.Sum256(content)
+	signature, err := signer.Sign(rand.Reader, digest[:], crypto.SHA256)
+	require.NoError(t, err)
+	return signature
+}
+
+func TestVerifyContent(t *testing.T) {
+	ecdsaKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	_, ed25519Key, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	otherKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	compDef := filepath.Join(BundlesDir, "example-component-definition.json")
+	profile := filepath.Join(ControlsDir, "sample-profile.json")
+	catalog := filepath.Join(ControlsDir, "sample-catalog.json")
+
+	tests := []struct {
+		name string
+		// prepare signs the content and returns the trust policy to use
+		prepare    func(t *testing.T, appDir ApplicationDirectory, keysDir string) string
+		wantStatus map[string]string
+		wantKeys   map[string]string
+	}{
+		{
+			name: "Valid/KeysDir",
+			prepare: func(t *testing.T, appDir ApplicationDirectory, keysDir string) string {
+				writeTestKey(t, keysDir, "content-team", ecdsaKey)
+				signTestFile(t, filepath.Join(appDir.AppDir(), compDef), ecdsaKey)
+				writeTestSigstoreBundle(t, filepath.Join(appDir.AppDir(), profile), ecdsaKey)
+				signTestFile(t, filepath.Join(appDir.AppDir(), catalog), ecdsaKey)
+				return filepath.Join(keysDir, "missing-policy.yaml")
+			},
+			wantStatus: map[string]string{compDef: SignatureVerified, profile: SignatureVerified, catalog: SignatureVerified},
+			wantKeys:   map[string]string{compDef: "content-team", profile: "content-team", catalog: "content-team"},
+		},
+		{
+			name: "Valid/TrustPolicyRules",
+			prepare: func(t *testing.T, appDir ApplicationDirectory, keysDir string) string {
+				writeTestKey(t, keysDir, "content", ecdsaKey)
+				writeTestKey(t, keysDir, "nist", ed25519Key)
+				signTestFile(t, filepath.Join(appDir.AppDir(), compDef), ecdsaKey)
+				signTestFile(t, filepath.Join(appDir.AppDir(), profile), ecdsaKey)
+				writeTestSigstoreBundle(t, filepath.Join(appDir.AppDir(), catalog), ed25519Key)
+				policy := "keys:\n- name: content-team\n  path: content.pub\n- name: nist\n  path: nist.pub\n" +
+					"rules:\n- pattern: controls/*-catalog.json\n  keys: [nist]\n"
+				policyPath := filepath.Join(keysDir, "trust-policy.yaml")
+				require.NoError(t, os.WriteFile(policyPath, []byte(policy), 0600))
+				return policyPath
+			},
+			wantStatus: map[string]string{compDef: SignatureVerified, profile: SignatureVerified, catalog: SignatureVerified},
+			wantKeys:   map[string]string{compDef: "content-team", profile: "content-team", catalog: "nist"},
+		},
+		{
+			name: "Invalid/UnsignedTamperedAndUntrusted",
+			prepare: func(t *testing.T, appDir ApplicationDirectory, keysDir string) string {
+				writeTestKey(t, keysDir, "content-team", ecdsaKey)
+				signTestFile(t, filepath.Join(appDir.AppDir(), compDef), otherKey)
+				signTestFile(t, filepath.Join(appDir.AppDir(), profile), ecdsaKey)
+				profilePath := filepath.Join(appDir.AppDir(), profile)
+				data, err := os.ReadFile(profilePath)
+				require.NoError(t, err)
+				require.NoError(t, os.WriteFile(profilePath, append(data, '\n'), 0600))
+				return filepath.Join(keysDir, "missing-policy.yaml")
+			},
+			wantStatus: map[string]string{compDef: SignatureInvalid, profile: SignatureInvalid, catalog: SignatureUnsigned},
+			wantKeys:   map[string]string{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			appDir := newTestContent(t)
+			keysDir := t.TempDir()
+			policyPath := tt.prepare(t, appDir, keysDir)
+
+			verifier, err := NewSignatureVerifier(policyPath, keysDir)
+			require.NoError(t, err)
+			results, err := verifier.VerifyContent(appDir)
+			require.NoError(t, err)
+
+			gotStatus := make(map[string]string)
+			gotKeys := make(map[string]string)
+			for _, result := range results {
+				gotStatus[result.File] = result.Status
+				if result.Key != "" {
+					gotKeys[result.File] = result.Key
+				}
+				require.Regexp(t, "^sha256:[a-f0-9]{64}$", result.Digest)
+			}
+			require.Equal(t, tt.wantStatus, gotStatus)
+			require.Equal(t, tt.wantKeys, gotKeys)
+
+			if tt.wantKeys[compDef] == "" {
+				require.ErrorIs(t, CheckSignatures(results), ErrUnverifiedContent)
+			} else {
+				require.NoError(t, CheckSignatures(results))
+			}
+		})
+	}
+}
+
+func TestNewSignatureVerifier(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name    string
+		policy  string
+		wantErr string
+	}{
+		{
+			name:    "Invalid/NoKeys",
+			wantErr: "no trusted public keys found in trust policy",
+		},
+		{
+			name:    "Invalid/UnknownField",
+			policy:  "keys:\n- name: content-team\n  file: content.pub\n",
+			wantErr: "unknown field \"file\"",
+		},
+		{
+			name:    "Invalid/UnknownRuleKey",
+			policy:  "keys:\n- name: content-team\n  path: content-team.pub\nrules:\n- pattern: controls/*\n  keys: [nist]\n",
+			wantErr: `trust rule "controls/*" references unknown key "nist"`,
+		},
+		{
+			name:    "Invalid/MissingKeyFile",
+			policy:  "keys:\n- name: nist\n  path: nist.pub\n",
+			wantErr: "trusted key nist:",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			policyPath := filepath.Join(dir, "trust-policy.yaml")
+			if tt.policy != "" {
+				writeTestKey(t, dir, "content-team", key)
+				require.NoError(t, os.WriteFile(policyPath, []byte(tt.policy), 0600))
+			}
+			_, err := NewSignatureVerifier(policyPath, filepath.Join(dir, "keys"))
+			require.ErrorContains(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestAddSignatureProps(t *testing.T) {
+	plan := &oscalTypes.AssessmentPlan{}
+	results := []SignatureResult{
+		{File: "bundles/example-component-definition.json", Digest: "sha256:1234", Status: SignatureVerified, Key: "content-team"},
+		{File: "controls/sample-catalog.json", Digest: "sha256:5678", Status: SignatureUnsigned},
+	}
+	AddSignatureProps(plan, results, true)
+	require.Equal(t, []oscalTypes.Property{
+		{Name: "Content_Verification", Value: "skipped", Ns: extensions.TrestleNameSpace},
+		{
+			Name:    "Content_Signature",
+			Value:   "verified",
+			Ns:      extensions.TrestleNameSpace,
+			Remarks: "bundles/example-component-definition.json sha256:1234 verified with key content-team",
+		},
+		{
+			Name:    "Content_Signature",
+			Value:   "unsigned",
+			Ns:      extensions.TrestleNameSpace,
+			Remarks: "controls/sample-catalog.json sha256:5678",
+		},
+	}, *plan.Metadata.Props)
+}
@@ -0,0 +1,23 @@
+#!/usr/bin/env bash
+###############################################################################
+#
+# Bash Remediation Script for Example Profile
+#
+# Profile Description:
+# Example profile used to test remediations.
+#
+###############################################################################
+
+###############################################################################
+# BEGIN fix (1 / 2) for 'xccdf_org.ssgproject.content_rule_sshd_disable_root_login'
+###############################################################################
+(>&2 echo "Remediating rule 1/2: 'xccdf_org.ssgproject.content_rule_sshd_disable_root_login'")
+echo "PermitRootLogin no" >> "${REMEDIATION_TARGET:-/etc/ssh/sshd_config}"
+# END fix for 'xccdf_org.ssgproject.content_rule_sshd_disable_root_login'
+
+###############################################################################
+# BEGIN fix (2 / 2) for 'xccdf_org.ssgproject.content_rule_service_auditd_enabled'
+###############################################################################
+(>&2 echo "Remediating rule 2/2: 'xccdf_org.ssgproject.content_rule_service_auditd_enabled'")
+systemctl enable --now auditd.service
+# END fix for 'xccdf_org.ssgproject.content_rule_service_auditd_enabled'
@@ -0,0 +1,438 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package complytime
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	oscalValidation "github.com/defenseunicorns/go-oscal/src/pkg/validation"
+	oscalTypes "github.com/defenseunicorns/go-oscal/src/types/oscal-1-1-3"
+	"github.com/goccy/go-yaml"
+	"github.com/oscal-compass/oscal-sdk-go/extensions"
+	"github.com/oscal-compass/oscal-sdk-go/models"
+	"github.com/oscal-compass/oscal-sdk-go/models/components"
+	"github.com/oscal-compass/oscal-sdk-go/validation"
+)
+
+// scopeWildcard selects all rules in the assessment scope.
+const scopeWildcard = "*"
+
+// Problem is an issue found while validating a complytime artifact.
+type Problem struct {
+	// File is the path of the artifact.
+	File string
+	// Path is the JSON pointer to the offending value in the artifact.
+	// It is empty when the problem applies to the whole artifact.
+	Path    string
+	Message string
+}
+
+// String returns the problem prefixed with its location.
+func (p Problem) String() string {
+	if p.Path == "" {
+		return fmt.Sprintf("%s: %s", p.File, p.Message)
+	}
+	return fmt.Sprintf("%s#%s: %s", p.File, p.Path, p.Message)
+}
+
+// ArtifactValidator validates the complytime artifacts in the application directory and the
+// user workspace. All problems are collected so they can be reported at once.
+//
+// ValidateBundle must run before the workspace artifacts are validated so the frameworks and rules
+// defined in the bundle are known.
+type ArtifactValidator struct {
+	appDir   ApplicationDirectory
+	problems []Problem
+	// frameworks are the framework short names with a loadable profile.
+	frameworks map[string]struct{}
+	// rules are the rule IDs defined in the component definitions.
+	rules map[string]struct{}
+	// sources caches the parameters of the loaded control sources by href.
+	sources map[string]controlSourceResult
+}
+
+// controlSourceResult is the outcome of loading a profile and its imported catalogs.
+type controlSourceResult struct {
+	parameters map[string]struct{}
+	err        error
+}
+
+// ruleReference is the location of a rule defined in a component definition.
+type ruleReference struct {
+	file string
+	path string
+}
+
+// NewArtifactValidator returns a new ArtifactValidator for the given application directory.
+func NewArtifactValidator(appDir ApplicationDirectory) *ArtifactValidator {
+	return &ArtifactValidator{
+		appDir:     appDir,
+		frameworks: make(map[string]struct{}),
+		rules:      make(map[string]struct{}),
+		sources:    make(map[string]controlSourceResult),
+	}
+}
+
+// Problems returns all problems found, sorted by file and location.
+func (v *ArtifactValidator) Problems() []Problem {
+	sort.SliceStable(v.problems, func(i, j int) bool {
+		if v.problems[i].File != v.problems[j].File {
+			return v.problems[i].File < v.problems[j].File
+		}
+		return v.problems[i].Path < v.problems[j].Path
+	})
+	return v.problems
+}
+
+func (v *ArtifactValidator) addProblem(file, path, format string, args ...any) {
+	v.problems = append(v.problems, Problem{File: file, Path: path, Message: fmt.Sprintf(format, args...)})
+}
+
+// ValidateBundle validates the component definitions in the bundle directory and the profiles and
+// catalogs they reference. Framework properties must reference a loadable profile, rules must be
+// implemented by a validation component with a plugin manifest, and set-parameters must reference
+// known parameters.
+func (v *ArtifactValidator) ValidateBundle() {
+	bundleDir := v.appDir.BundleDir()
+	items, err := os.ReadDir(bundleDir)
+	if err != nil {
+		v.addProblem(bundleDir, "", "unable to read bundle directory: %v", err)
+		return
+	}
+
+	manifests := v.pluginManifests()
+	// Rules and parameters may be defined in a different component definition than the
+	// one referencing them, so references are checked once all definitions are loaded.
+	definedRules := make(map[string]ruleReference)
+	implementedRules := make(map[string]struct{})
+	parameters := make(map[string]struct{})
+	type parameterReference struct {
+		file, path, paramID string
+		knownParameters     map[string]struct{}
+	}
+	var parameterReferences []parameterReference
+
+	var found bool
+	for _, item := range items {
+		if !strings.HasSuffix(item.Name(), ComponentDefinitionSuffix) {
+			continue
+		}
+		found = true
+		compDefPath := filepath.Join(bundleDir, item.Name())
+		v.validateSchema(compDefPath)
+		compDef, err := ReadComponentDefinition(compDefPath, validation.NoopValidator{})
+		if err != nil {
+			v.addProblem(compDefPath, "", "%v", err)
+			continue
+		}
+		if compDef.Components == nil {
+			continue
+		}
+		for i, component := range *compDef.Components {
+			componentPath := fmt.Sprintf("/component-definition/components/%d", i)
+			isValidation := component.Type == string(components.Validation)
+			if isValidation {
+				if _, ok := manifests[component.Title]; !ok {
+					v.addProblem(compDefPath, componentPath+"/title", "no plugin manifest %s%s%s found for validation component %q in %s",
+						pluginManifestPrefix, component.Title, pluginManifestSuffix, component.Title, v.appDir.PluginManifestDir())
+				}
+			}
+			if component.Props != nil {
+				for j, prop := range *component.Props {
+					switch {
+					case prop.Name == extensions.RuleIdProp && isValidation:
+						implementedRules[prop.Value] = struct{}{}
+					case prop.Name == extensions.RuleIdProp && prop.Remarks != "":
+						if _, ok := definedRules[prop.Value]; !ok {
+							definedRules[prop.Value] = ruleReference{file: compDefPath, path: fmt.Sprintf("%s/props/%d/value", componentPath, j)}
+						}
+					case prop.Name == extensions.ParameterIdProp:
+						parameters[prop.Value] = struct{}{}
+					}
+				}
+			}
+			if component.ControlImplementations == nil {
+				continue
+			}
+			for j, implementation := range *component.ControlImplementations {
+				implementationPath := fmt.Sprintf("%s/control-implementations/%d", componentPath, j)
+				source := v.loadControlSource(implementation.Source)
+				var frameworkProp oscalTypes.Property
+				var hasFramework bool
+				if implementation.Props != nil {
+					frameworkProp, hasFramework = extensions.GetTrestleProp(extensions.FrameworkProp, *implementation.Props)
+				}
+				switch {
+				case source.err != nil && hasFramework:
+					v.addProblem(compDefPath, implementationPath+"/source", "framework %q does not reference a loadable profile: %v", frameworkProp.Value, source.err)
+				case source.err != nil:
+					v.addProblem(compDefPath, implementationPath+"/source", "%v", source.err)
+				case hasFramework:
+					v.frameworks[frameworkProp.Value] = struct{}{}
+				}
+				if source.err != nil || implementation.SetParameters == nil {
+					continue
+				}
+				for k, setParameter := range *implementation.SetParameters {
+					parameterReferences = append(parameterReferences, parameterReference{
+						file:            compDefPath,
+						path:            fmt.Sprintf("%s/set-parameters/%d/param-id", implementationPath, k),
+						paramID:         setParameter.ParamId,
+						knownParameters: source.parameters,
+					})
+				}
+			}
+		}
+	}
+	if !found {
+		v.addProblem(bundleDir, "", "%v", ErrNoComponentDefinitionsFound)
+		return
+	}
+
+	for ruleID, reference := range definedRules {
+		v.rules[ruleID] = struct{}{}
+		if _, ok := implementedRules[ruleID]; !ok {
+			v.addProblem(reference.file, reference.path, "rule %q is not implemented by any validation component", ruleID)
+		}
+	}
+	for _, reference := range parameterReferences {
+		_, isRuleParameter := parameters[reference.paramID]
+		_, isControlParameter := reference.knownParameters[reference.paramID]
+		if !isRuleParameter && !isControlParameter {
+			v.addProblem(reference.file, reference.path, "set-parameter references unknown parameter %q", reference.paramID)
+		}
+	}
+}
+
+// ValidatePlan validates the assessment plan at the given path. The framework of the plan
+// must be defined in the bundle.
+func (v *ArtifactValidator) ValidatePlan(path string) {
+	if !v.validateSchema(path) {
+		return
+	}
+	plan, err := ReadPlan(path, validation.NoopValidator{})
+	if err != nil {
+		v.addProblem(path, "", "%v", err)
+		return
+	}
+	if plan.Metadata.Props == nil {
+		v.addProblem(path, "/assessment-plan/metadata", "missing %s property", extensions.FrameworkProp)
+		return
+	}
+	for i, prop := range *plan.Metadata.Props {
+		if prop.Name != extensions.FrameworkProp || prop.Ns != extensions.TrestleNameSpace {
+			continue
+		}
+		if _, ok := v.frameworks[prop.Value]; !ok {
+			v.addProblem(path, fmt.Sprintf("/assessment-plan/metadata/props/%d/value", i), "framework %q does not match any profile in the bundle", prop.Value)
+		}
+		return
+	}
+	v.addProblem(path, "/assessment-plan/metadata/props", "missing %s property", extensions.FrameworkProp)
+}
+
+// ValidateResults validates the assessment results at the given path.
+func (v *ArtifactValidator) ValidateResults(path string) {
+	v.validateSchema(path)
+}
+
+// ValidateScopeConfig validates the assessment scope configuration at the given path. Unknown
+// fields are rejected, and the framework and rules must be defined in the bundle.
+func (v *ArtifactValidator) ValidateScopeConfig(path string) {
+	data, err := os.ReadFile(filepath.Clean(path))
+	if err != nil {
+		v.addProblem(path, "", "%v", err)
+		return
+	}
+	var scope AssessmentScope
+	if err := yaml.UnmarshalWithOptions(data, &scope, yaml.Strict()); err != nil {
+		v.addProblem(path, "", "invalid assessment scope: %s", yaml.FormatError(err, false, false))
+		return
+	}
+	if scope.FrameworkID == "" {
+		// Scope configs extending another one inherit its framework
+		if scope.Extends == "" {
+			v.addProblem(path, "/frameworkId", "frameworkId must be set")
+		}
+	} else if _, ok := v.frameworks[scope.FrameworkID]; !ok {
+		v.addProblem(path, "/frameworkId", "framework %q does not match any profile in the bundle", scope.FrameworkID)
+	}
+	v.checkScopeRules(path, "/globalExcludeRules", scope.GlobalExcludeRules)
+	v.checkScopeRules(path, "/globalWaiveRules", scope.GlobalWaiveRules)
+	for i, control := range scope.IncludeControls {
+		controlPath := fmt.Sprintf("/includeControls/%d", i)
+		if control.ControlID == "" {
+			v.addProblem(path, controlPath+"/controlId", "controlId must be set")
+		}
+		v.checkScopeRules(path, controlPath+"/includeRules", control.IncludeRules)
+		v.checkScopeRules(path, controlPath+"/excludeRules", control.ExcludeRules)
+		v.checkScopeRules(path, controlPath+"/waiveRules", control.WaiveRules)
+	}
+}
+
+func (v *ArtifactValidator) checkScopeRules(file, path string, rules []string) {
+	for i, rule := range rules {
+		rule = strings.TrimPrefix(rule, scopeRemovePrefix)
+		if rule == scopeWildcard {
+			continue
+		}
+		if isPattern(rule) {
+			if _, err := matchPattern(rule, nil, false); err != nil {
+				v.addProblem(file, fmt.Sprintf("%s/%d", path, i), "%v", err)
+			}
+			continue
+		}
+		if _, ok := v.rules[rule]; !ok {
+			v.addProblem(file, fmt.Sprintf("%s/%d", path, i), "rule %q is not defined in the bundle", rule)
+		}
+	}
+}
+
+// validateSchema validates the OSCAL document at the given path against the OSCAL JSON schema
+// and reports whether it is valid.
+func (v *ArtifactValidator) validateSchema(path string) bool {
+	data, err := os.ReadFile(filepath.Clean(path))
+	if err != nil {
+		v.addProblem(path, "", "%v", err)
+		return false
+	}
+	validator, err := oscalValidation.NewValidatorDesiredVersion(data, validation.OSCALVersion)
+	if err != nil {
+		v.addProblem(path, "", "%v", err)
+		return false
+	}
+	if err := validator.Validate(); err == nil {
+		return true
+	}
+	result, err := validator.GetValidationResult()
+	if err != nil {
+		v.addProblem(path, "", "%v", err)
+		return false
+	}
+	for _, validationErr := range result.Errors {
+		v.addProblem(path, validationErr.InstanceLocation, "%s", strings.Trim(validationErr.Error, `"`))
+	}
+	return false
+}
+
+// loadControlSource schema-validates the profile at the given href and the catalogs it imports and
+// returns the parameters they define. Results are cached so each source is validated once.
+// Problems in the profile and catalogs are reported, while an error loading the profile itself
+// is returned for the caller to report at the referencing location.
+func (v *ArtifactValidator) loadControlSource(href string) controlSourceResult {
+	if result, ok := v.sources[href]; ok {
+		return result
+	}
+	result := controlSourceResult{parameters: make(map[string]struct{})}
+	defer func() { v.sources[href] = result }()
+
+	profilePath, err := controlSourcePath(v.appDir, href)
+	if err != nil {
+		result.err = err
+		return result
+	}
+	if _, err := os.Stat(profilePath); err != nil {
+		result.err = err
+		return result
+	}
+	// Schema problems are reported, but only sources that cannot be loaded
+	// fail the references to them.
+	schemaValid := v.validateSchema(profilePath)
+	profile, err := LoadProfile(v.appDir, href, validation.NoopValidator{})
+	if err != nil {
+		result.err = err
+		if schemaValid {
+			v.addProblem(profilePath, "", "%v", err)
+		}
+		return result
+	}
+	if profile.Modify != nil && profile.Modify.SetParameters != nil {
+		for _, setParameter := range *profile.Modify.SetParameters {
+			result.parameters[setParameter.ParamId] = struct{}{}
+		}
+	}
+	for i, imported := range profile.Imports {
+		importPath := fmt.Sprintf("/profile/imports/%d/href", i)
+		catalogPath, err := controlSourcePath(v.appDir, imported.Href)
+		if err != nil {
+			result.err = err
+			v.addProblem(profilePath, importPath, "%v", err)
+			continue
+		}
+		if _, err := os.Stat(catalogPath); err != nil {
+			result.err = err
+			v.addProblem(profilePath, importPath, "%v", err)
+			continue
+		}
+		schemaValid := v.validateSchema(catalogPath)
+		catalog, err := LoadCatalogSource(v.appDir, imported.Href, validation.NoopValidator{})
+		if err != nil {
+			result.err = err
+			if schemaValid {
+				v.addProblem(catalogPath, "", "%v", err)
+			}
+			continue
+		}
+		addCatalogParameters(catalog, result.parameters)
+	}
+	return result
+}
+
+// pluginManifests returns the IDs of the plugins with a manifest in the plugin manifest directory.
+func (v *ArtifactValidator) pluginManifests() map[string]string {
+	manifests, err := findPluginManifests(v.appDir.PluginManifestDir())
+	if err != nil {
+		v.addProblem(v.appDir.PluginManifestDir(), "", "%v", err)
+	}
+	return manifests
+}
+
+// ReadComponentDefinition reads a component definition, validated with the given validator.
+func ReadComponentDefinition(path string, validator validation.Validator) (*oscalTypes.ComponentDefinition, error) {
+	file, err := os.Open(filepath.Clean(path))
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	return models.NewComponentDefinition(file, validator)
+}
+
+// addCatalogParameters adds the IDs of all parameters defined in the catalog to the given set.
+func addCatalogParameters(catalog *oscalTypes.Catalog, parameters map[string]struct{}) {
+	var addControls func(controls *[]oscalTypes.Control)
+	addParams := func(params *[]oscalTypes.Parameter) {
+		if params == nil {
+			return
+		}
+		for _, param := range *params {
+			parameters[param.ID] = struct{}{}
+		}
+	}
+	addControls = func(controls *[]oscalTypes.Control) {
+		if controls == nil {
+			return
+		}
+		for _, control := range *controls {
+			addParams(control.Params)
+			addControls(control.Controls)
+		}
+	}
+	var addGroups func(groups *[]oscalTypes.Group)
+	addGroups = func(groups *[]oscalTypes.Group) {
+		if groups == nil {
+			return
+		}
+		for _, group := range *groups {
+			addParams(group.Params)
+			addControls(group.Controls)
+			addGroups(group.Groups)
+		}
+	}
+	addParams(catalog.Params)
+	addControls(catalog.Controls)
+	addGroups(catalog.Groups)
+}
@@ -0,0 +1,168 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package complytime
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	oscalTypes "github.com/defenseunicorns/go-oscal/src/types/oscal-1-1-3"
+	"github.com/stretchr/testify/require"
+)
+
+const testCompDefPath = "testdata/complytime/bundles/example-component-definition.json"
+
+func newTestArtifactValidator(t *testing.T) *ArtifactValidator {
+	appDir, err := newApplicationDirectory("testdata", false)
+	require.NoError(t, err)
+	validator := NewArtifactValidator(appDir)
+	validator.ValidateBundle()
+	return validator
+}
+
+func TestArtifactValidator_ValidateBundle(t *testing.T) {
+	validator := newTestArtifactValidator(t)
+	require.Equal(t, []Problem{
+		{
+			File:    testCompDefPath,
+			Path:    "/component-definition/components/1/title",
+			Message: "no plugin manifest c2p-myplugin-manifest.json found for validation component \"myplugin\" in testdata/complytime/plugins",
+		},
+		{
+			File:    "testdata/complytime/controls/sample-catalog.json",
+			Path:    "/catalog/groups/0/controls/0/params",
+			Message: "minItems: got 0, want 1",
+		},
+		{
+			File:    "testdata/complytime/controls/sample-catalog.json",
+			Path:    "/catalog/params",
+			Message: "minItems: got 0, want 1",
+		},
+	}, validator.Problems())
+	require.Contains(t, validator.frameworks, "example")
+	require.Contains(t, validator.rules, "rule-1")
+}
+
+func TestArtifactValidator_ValidatePlan(t *testing.T) {
+	tests := []struct {
+		name        string
+		frameworkID string
+		wantProblem Problem
+	}{
+		{
+			name:        "Valid/KnownFramework",
+			frameworkID: "example",
+		},
+		{
+			name:        "Invalid/UnknownFramework",
+			frameworkID: "unknown",
+			wantProblem: Problem{
+				Path:    "/assessment-plan/metadata/props/0/value",
+				Message: "framework \"unknown\" does not match any profile in the bundle",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			planPath := filepath.Join(t.TempDir(), "assessment-plan.json")
+			plan := oscalTypes.AssessmentPlan{
+				UUID: "228ff6d0-0d67-4c15-9c16-ece9a554c4de",
+				Metadata: oscalTypes.Metadata{
+					Title:        "example",
+					OscalVersion: "1.1.3",
+					Version:      "1.0.0",
+					LastModified: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+				},
+				ImportSsp: oscalTypes.ImportSsp{Href: "ssp.json"},
+				ReviewedControls: oscalTypes.ReviewedControls{
+					ControlSelections: []oscalTypes.AssessedControls{{}},
+				},
+			}
+			require.NoError(t, WritePlan(&plan, tt.frameworkID, planPath))
+
+			validator := newTestArtifactValidator(t)
+			bundleProblems := len(validator.Problems())
+			validator.ValidatePlan(planPath)
+			problems := validator.Problems()
+			if tt.wantProblem.Message == "" {
+				require.Len(t, problems, bundleProblems)
+				return
+			}
+			tt.wantProblem.File = planPath
+			require.Contains(t, problems, tt.wantProblem)
+		})
+	}
+}
+
+func TestArtifactValidator_ValidateScopeConfig(t *testing.T) {
+	tests := []struct {
+		name         string
+		config       string
+		wantProblems []Problem
+	}{
+		{
+			name: "Valid/KnownRules",
+			config: `frameworkId: example
+includeControls:
+- controlId: example-1
+  controlTitle: Example
+  includeRules:
+  - rule-1
+- controlId: EX-*
+  includeRules:
+  - rule-*
+globalExcludeRules:
+- "*"
+`,
+		},
+		{
+			name: "Invalid/UnknownReferences",
+			config: `frameworkId: unknown
+includeControls:
+- controlId: ""
+  includeRules:
+  - rule-2
+  - /rule-(/
+`,
+			wantProblems: []Problem{
+				{Path: "/frameworkId", Message: "framework \"unknown\" does not match any profile in the bundle"},
+				{Path: "/includeControls/0/controlId", Message: "controlId must be set"},
+				{Path: "/includeControls/0/includeRules/0", Message: "rule \"rule-2\" is not defined in the bundle"},
+				{Path: "/includeControls/0/includeRules/1", Message: "invalid regular expression \"/rule-(/\": error parsing regexp: missing closing ): `rule-(`"},
+			},
+		},
+		{
+			name: "Invalid/UnknownField",
+			config: `frameworkId: example
+includeControl: []
+`,
+			wantProblems: []Problem{
+				{Message: "invalid assessment scope: [2:1] unknown field \"includeControl\""},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			configPath := filepath.Join(t.TempDir(), "config.yml")
+			require.NoError(t, os.WriteFile(configPath, []byte(tt.config), 0600))
+
+			appDir, err := newApplicationDirectory("testdata", false)
+			require.NoError(t, err)
+			validator := NewArtifactValidator(appDir)
+			validator.frameworks["example"] = struct{}{}
+			validator.rules["rule-1"] = struct{}{}
+			validator.ValidateScopeConfig(configPath)
+
+			for i := range tt.wantProblems {
+				tt.wantProblems[i].File = configPath
+			}
+			if len(tt.wantProblems) == 0 {
+				require.Empty(t, validator.Problems())
+				return
+			}
+			require.Equal(t, tt.wantProblems, validator.Problems())
+		})
+	}
+}
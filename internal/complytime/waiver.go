@@ -0,0 +1,271 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package complytime
+
+import (
+	"fmt"
+	"time"
+
+	oscalTypes "github.com/defenseunicorns/go-oscal/src/types/oscal-1-1-3"
+	"github.com/hashicorp/go-hclog"
+	"github.com/oscal-compass/oscal-sdk-go/extensions"
+)
+
+// Property names of the waiver metadata on the waived activities and steps of the assessment
+// plan and on the findings of the assessment results.
+const (
+	WaiverReasonProp   = "Waiver_Reason"
+	WaiverApproverProp = "Waiver_Approver"
+	WaiverTicketProp   = "Waiver_Ticket"
+	WaiverExpiresProp  = "Waiver_Expires"
+)
+
+// Waiver is the justification of a rule waived with waiveRules or globalWaiveRules
+// in the assessment scope.
+type Waiver struct {
+	// Rule is the ID of the waived rule, or "*" for all waived rules without their own waiver.
+	Rule     string `yaml:"rule"`
+	Reason   string `yaml:"reason,omitempty"`
+	Approver string `yaml:"approver,omitempty"`
+	Ticket   string `yaml:"ticket,omitempty"`
+	// Expires is the last day of the waiver in YYYY-MM-DD format. After that
+	// day the rule is evaluated again.
+	Expires string `yaml:"expires,omitempty"`
+}
+
+// Expired returns true if the waiver ended before the given time. Waivers without
+// an expiry date never expire.
+func (w Waiver) Expired(now time.Time) (bool, error) {
+	if w.Expires == "" {
+		return false, nil
+	}
+	expires, err := time.ParseInLocation(time.DateOnly, w.Expires, now.Location())
+	if err != nil {
+		return false, fmt.Errorf("invalid expiry date %q of the waiver for rule %q: must be YYYY-MM-DD", w.Expires, w.Rule)
+	}
+	return !now.Before(expires.AddDate(0, 0, 1)), nil
+}
+
+// props returns the waiver metadata as properties.
+func (w Waiver) props() []oscalTypes.Property {
+	var props []oscalTypes.Property
+	for _, field := range []struct{ name, value string }{
+		{WaiverReasonProp, w.Reason},
+		{WaiverApproverProp, w.Approver},
+		{WaiverTicketProp, w.Ticket},
+		{WaiverExpiresProp, w.Expires},
+	} {
+		if field.value != "" {
+			props = append(props, oscalTypes.Property{Name: field.name, Value: field.value, Ns: extensions.TrestleNameSpace})
+		}
+	}
+	return props
+}
+
+// waiverFor returns the waiver of the rule, falling back to the waiver of all rules.
+func (a AssessmentScope) waiverFor(ruleID string) (Waiver, bool) {
+	i, found := a.waiverIndex(ruleID)
+	if !found {
+		return Waiver{}, false
+	}
+	return a.Waivers[i], true
+}
+
+// waiverIndex returns the index of the waiver of the rule. A waiver naming the rule takes precedence
+// over the first waiver whose pattern matches the rule, which takes precedence over the waiver of all rules.
+func (a AssessmentScope) waiverIndex(ruleID string) (int, bool) {
+	pattern, wildcard := -1, -1
+	for i, waiver := range a.Waivers {
+		switch {
+		case waiver.Rule == ruleID:
+			return i, true
+		case waiver.Rule == scopeWildcard:
+			if wildcard < 0 {
+				wildcard = i
+			}
+		case pattern < 0 && isPattern(waiver.Rule) && MatchesRule(ruleID, []string{waiver.Rule}):
+			pattern = i
+		}
+	}
+	if pattern >= 0 {
+		return pattern, true
+	}
+	if wildcard >= 0 {
+		return wildcard, true
+	}
+	return -1, false
+}
+
+// validateWaivers checks that every waiver names a rule and has a valid expiry date.
+func (a AssessmentScope) validateWaivers() error {
+	for _, waiver := range a.Waivers {
+		if waiver.Rule == "" {
+			return fmt.Errorf("waiver rule must be set")
+		}
+		if _, err := waiver.Expired(time.Now()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// expiredWaivers returns the indexes of the waivers that ended before the given time.
+// A warning is logged for each expired waiver since its rules are evaluated again.
+func (a AssessmentScope) expiredWaivers(now time.Time, logger hclog.Logger) map[int]struct{} {
+	expired := make(map[int]struct{})
+	for i, waiver := range a.Waivers {
+		if isExpired, err := waiver.Expired(now); err == nil && isExpired {
+			expired[i] = struct{}{}
+			logger.Warn(fmt.Sprintf("The waiver of rule %s expired on %s, the rule will be evaluated.", waiver.Rule, waiver.Expires))
+		}
+	}
+	return expired
+}
+
+// isWaiverExpired returns true if the waiver applying to the rule is in the expired waivers.
+func (a AssessmentScope) isWaiverExpired(ruleID string, expired map[int]struct{}) bool {
+	i, found := a.waiverIndex(ruleID)
+	if !found {
+		return false
+	}
+	_, isExpired := expired[i]
+	return isExpired
+}
+
+// ExpireWaivers evaluates the rules of the assessment plan again when their waiver expired before
+// the given time. The waived property and the waiver metadata are removed from the activities and
+// steps of these rules, and a warning is logged for each rule.
+func ExpireWaivers(assessmentPlan *oscalTypes.AssessmentPlan, now time.Time, logger hclog.Logger) {
+	if assessmentPlan.LocalDefinitions == nil || assessmentPlan.LocalDefinitions.Activities == nil {
+		return
+	}
+	for i := range *assessmentPlan.LocalDefinitions.Activities {
+		activity := &(*assessmentPlan.LocalDefinitions.Activities)[i]
+		expired := false
+		if activity.Props != nil {
+			expired = expireWaiverProps(activity.Props, now)
+		}
+		if activity.Steps != nil {
+			for j := range *activity.Steps {
+				step := &(*activity.Steps)[j]
+				if step.Props != nil && expireWaiverProps(step.Props, now) {
+					expired = true
+				}
+			}
+		}
+		if expired {
+			logger.Warn(fmt.Sprintf("The waiver of rule %s expired, the rule will be evaluated.", activity.Title))
+		}
+	}
+}
+
+// expireWaiverProps removes the waived property and the waiver metadata if the waiver expired.
+func expireWaiverProps(props *[]oscalTypes.Property, now time.Time) bool {
+	waived, found := extensions.GetTrestleProp(extensions.WaivedRulesProperty, *props)
+	if !found || waived.Value != "true" {
+		return false
+	}
+	expires, found := extensions.GetTrestleProp(WaiverExpiresProp, *props)
+	if !found {
+		return false
+	}
+	if isExpired, err := (Waiver{Expires: expires.Value}).Expired(now); err != nil || !isExpired {
+		return false
+	}
+	var kept []oscalTypes.Property
+	for _, prop := range *props {
+		if isWaiverProp(prop) {
+			continue
+		}
+		kept = append(kept, prop)
+	}
+	*props = kept
+	return true
+}
+
+// isWaiverProp returns true for the waived property and the waiver metadata.
+func isWaiverProp(prop oscalTypes.Property) bool {
+	if prop.Ns != extensions.TrestleNameSpace {
+		return false
+	}
+	switch prop.Name {
+	case extensions.WaivedRulesProperty, WaiverReasonProp, WaiverApproverProp, WaiverTicketProp, WaiverExpiresProp:
+		return true
+	default:
+		return false
+	}
+}
+
+// AddWaiverProps carries the waiver metadata of the waived rules in the assessment plan into the
+// findings of the assessment results. Findings get the metadata of each waived rule observed for
+// them, with the rule ID in the remarks of each property.
+func AddWaiverProps(assessmentResults *oscalTypes.AssessmentResults, assessmentPlan *oscalTypes.AssessmentPlan) {
+	waiverProps := planWaiverProps(assessmentPlan)
+	if len(waiverProps) == 0 {
+		return
+	}
+	for i := range assessmentResults.Results {
+		result := &assessmentResults.Results[i]
+		if result.Observations == nil || result.Findings == nil {
+			continue
+		}
+		observationRules := make(map[string]string)
+		for _, observation := range *result.Observations {
+			if observation.Props == nil {
+				continue
+			}
+			if ruleID, found := extensions.GetTrestleProp(extensions.AssessmentRuleIdProp, *observation.Props); found {
+				observationRules[observation.UUID] = ruleID.Value
+			}
+		}
+		for j := range *result.Findings {
+			finding := &(*result.Findings)[j]
+			if finding.RelatedObservations == nil {
+				continue
+			}
+			added := make(map[string]bool)
+			for _, related := range *finding.RelatedObservations {
+				ruleID := observationRules[related.ObservationUuid]
+				props, waived := waiverProps[ruleID]
+				if !waived || added[ruleID] {
+					continue
+				}
+				added[ruleID] = true
+				if finding.Props == nil {
+					finding.Props = &[]oscalTypes.Property{}
+				}
+				for _, prop := range props {
+					prop.Remarks = ruleID
+					*finding.Props = append(*finding.Props, prop)
+				}
+			}
+		}
+	}
+}
+
+// planWaiverProps returns the waiver metadata of the waived activities in the assessment plan by rule ID.
+func planWaiverProps(assessmentPlan *oscalTypes.AssessmentPlan) map[string][]oscalTypes.Property {
+	waiverProps := make(map[string][]oscalTypes.Property)
+	if assessmentPlan.LocalDefinitions == nil || assessmentPlan.LocalDefinitions.Activities == nil {
+		return waiverProps
+	}
+	for _, activity := range *assessmentPlan.LocalDefinitions.Activities {
+		if activity.Props == nil {
+			continue
+		}
+		waived, found := extensions.GetTrestleProp(extensions.WaivedRulesProperty, *activity.Props)
+		if !found || waived.Value != "true" {
+			continue
+		}
+		var props []oscalTypes.Property
+		for _, prop := range *activity.Props {
+			if prop.Name != extensions.WaivedRulesProperty && isWaiverProp(prop) {
+				props = append(props, prop)
+			}
+		}
+		if len(props) > 0 {
+			waiverProps[activity.Title] = props
+		}
+	}
+	return waiverProps
+}
@@ -0,0 +1,196 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package complytime
+
+import (
+	"testing"
+	"time"
+
+	oscalTypes "github.com/defenseunicorns/go-oscal/src/types/oscal-1-1-3"
+	"github.com/hashicorp/go-hclog"
+	"github.com/oscal-compass/oscal-sdk-go/extensions"
+	"github.com/stretchr/testify/require"
+)
+
+func trestleProp(name, value string) oscalTypes.Property {
+	return oscalTypes.Property{Name: name, Value: value, Ns: extensions.TrestleNameSpace}
+}
+
+func TestWaiverExpired(t *testing.T) {
+	now := time.Date(2025, 6, 15, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name    string
+		expires string
+		want    bool
+		wantErr string
+	}{
+		{name: "Valid/NoExpiry"},
+		{name: "Valid/Future", expires: "2025-06-16"},
+		{name: "Valid/LastDay", expires: "2025-06-15"},
+		{name: "Valid/Expired", expires: "2025-06-14", want: true},
+		{
+			name:    "Invalid/Date",
+			expires: "15/06/2025",
+			wantErr: "invalid expiry date \"15/06/2025\" of the waiver for rule \"rule-1\": must be YYYY-MM-DD",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			expired, err := Waiver{Rule: "rule-1", Expires: tt.expires}.Expired(now)
+			if tt.wantErr != "" {
+				require.EqualError(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.want, expired)
+		})
+	}
+}
+
+func TestAssessmentScope_ApplyWaivers(t *testing.T) {
+	newPlan := func() *oscalTypes.AssessmentPlan {
+		var activities []oscalTypes.Activity
+		for _, rule := range []string{"rule-1", "rule-2", "rule-3"} {
+			activities = append(activities, oscalTypes.Activity{
+				Title: rule,
+				RelatedControls: &oscalTypes.ReviewedControls{
+					ControlSelections: []oscalTypes.AssessedControls{
+						{IncludeControls: &[]oscalTypes.AssessedControlsSelectControlById{{ControlId: "control-1"}}},
+					},
+				},
+			})
+		}
+		return &oscalTypes.AssessmentPlan{LocalDefinitions: &oscalTypes.LocalDefinitions{Activities: &activities}}
+	}
+	scope := AssessmentScope{
+		FrameworkID:      "test",
+		IncludeControls:  []ControlEntry{{ControlID: "control-1", IncludeRules: []string{"*"}, WaiveRules: []string{"rule-2"}}},
+		GlobalWaiveRules: []string{"rule-1", "rule-3"},
+		Waivers: []Waiver{
+			{Rule: "rule-1", Reason: "Not applicable to containers", Approver: "security-team", Ticket: "SEC-42", Expires: "2999-12-31"},
+			{Rule: "rule-2", Reason: "Temporary exception", Expires: "2000-01-01"},
+		},
+	}
+
+	plan := newPlan()
+	require.NoError(t, scope.ApplyScope(plan, hclog.NewNullLogger()))
+	activities := *plan.LocalDefinitions.Activities
+	require.Equal(t, &[]oscalTypes.Property{
+		trestleProp(extensions.WaivedRulesProperty, "true"),
+		trestleProp(WaiverReasonProp, "Not applicable to containers"),
+		trestleProp(WaiverApproverProp, "security-team"),
+		trestleProp(WaiverTicketProp, "SEC-42"),
+		trestleProp(WaiverExpiresProp, "2999-12-31"),
+	}, activities[0].Props)
+	// The waiver of rule-2 expired so the rule is evaluated again.
+	require.Nil(t, activities[1].Props)
+	// Waived rules without a waiver keep the waived property only.
+	require.Equal(t, &[]oscalTypes.Property{trestleProp(extensions.WaivedRulesProperty, "true")}, activities[2].Props)
+
+	// Pattern waivers apply to the rules they match, and their expiry applies to these rules.
+	scope.Waivers = []Waiver{
+		{Rule: "rule-[12]", Reason: "Pattern exception", Expires: "2999-12-31"},
+		{Rule: "/^rule-[23]$/", Reason: "Expired exception", Expires: "2000-01-01"},
+		{Rule: "rule-1", Reason: "Rule exception"},
+	}
+	plan = newPlan()
+	require.NoError(t, scope.ApplyScope(plan, hclog.NewNullLogger()))
+	activities = *plan.LocalDefinitions.Activities
+	require.Equal(t, &[]oscalTypes.Property{
+		trestleProp(extensions.WaivedRulesProperty, "true"),
+		trestleProp(WaiverReasonProp, "Rule exception"),
+	}, activities[0].Props)
+	require.Equal(t, &[]oscalTypes.Property{
+		trestleProp(extensions.WaivedRulesProperty, "true"),
+		trestleProp(WaiverReasonProp, "Pattern exception"),
+		trestleProp(WaiverExpiresProp, "2999-12-31"),
+	}, activities[1].Props)
+	require.Nil(t, activities[2].Props)
+
+	scope.Waivers = []Waiver{{Rule: "rule-1", Expires: "tomorrow"}}
+	require.EqualError(t, scope.ApplyScope(newPlan(), hclog.NewNullLogger()),
+		"invalid expiry date \"tomorrow\" of the waiver for rule \"rule-1\": must be YYYY-MM-DD")
+}
+
+func TestExpireWaivers(t *testing.T) {
+	waivedProps := func(expires string) *[]oscalTypes.Property {
+		return &[]oscalTypes.Property{
+			{Name: "method", Value: "TEST"},
+			trestleProp(extensions.WaivedRulesProperty, "true"),
+			trestleProp(WaiverReasonProp, "Temporary exception"),
+			trestleProp(WaiverExpiresProp, expires),
+		}
+	}
+	plan := &oscalTypes.AssessmentPlan{
+		LocalDefinitions: &oscalTypes.LocalDefinitions{
+			Activities: &[]oscalTypes.Activity{
+				{
+					Title: "rule-1",
+					Props: waivedProps("2025-01-31"),
+					Steps: &[]oscalTypes.Step{{Title: "check-1", Props: waivedProps("2025-01-31")}},
+				},
+				{
+					Title: "rule-2",
+					Props: waivedProps("2025-12-31"),
+				},
+			},
+		},
+	}
+
+	ExpireWaivers(plan, time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC), hclog.NewNullLogger())
+	activities := *plan.LocalDefinitions.Activities
+	require.Equal(t, &[]oscalTypes.Property{{Name: "method", Value: "TEST"}}, activities[0].Props)
+	require.Equal(t, &[]oscalTypes.Property{{Name: "method", Value: "TEST"}}, (*activities[0].Steps)[0].Props)
+	require.Equal(t, waivedProps("2025-12-31"), activities[1].Props)
+}
+
+func TestAddWaiverProps(t *testing.T) {
+	plan := &oscalTypes.AssessmentPlan{
+		LocalDefinitions: &oscalTypes.LocalDefinitions{
+			Activities: &[]oscalTypes.Activity{
+				{
+					Title: "rule-1",
+					Props: &[]oscalTypes.Property{
+						trestleProp(extensions.WaivedRulesProperty, "true"),
+						trestleProp(WaiverReasonProp, "Not applicable to containers"),
+						trestleProp(WaiverTicketProp, "SEC-42"),
+					},
+				},
+				{Title: "rule-2"},
+			},
+		},
+	}
+	observation := func(uuid, rule string) oscalTypes.Observation {
+		return oscalTypes.Observation{UUID: uuid, Props: &[]oscalTypes.Property{trestleProp(extensions.AssessmentRuleIdProp, rule)}}
+	}
+	related := func(uuids ...string) *[]oscalTypes.RelatedObservation {
+		var observations []oscalTypes.RelatedObservation
+		for _, uuid := range uuids {
+			observations = append(observations, oscalTypes.RelatedObservation{ObservationUuid: uuid})
+		}
+		return &observations
+	}
+	results := &oscalTypes.AssessmentResults{
+		Results: []oscalTypes.Result{
+			{
+				Observations: &[]oscalTypes.Observation{
+					observation("obs-1", "rule-1"),
+					observation("obs-2", "rule-1"),
+					observation("obs-3", "rule-2"),
+				},
+				Findings: &[]oscalTypes.Finding{
+					{Target: oscalTypes.FindingTarget{TargetId: "ac-1_smt"}, RelatedObservations: related("obs-1", "obs-2", "obs-3")},
+					{Target: oscalTypes.FindingTarget{TargetId: "ac-2_smt"}, RelatedObservations: related("obs-3")},
+				},
+			},
+		},
+	}
+
+	AddWaiverProps(results, plan)
+	findings := *results.Results[0].Findings
+	require.Equal(t, &[]oscalTypes.Property{
+		{Name: WaiverReasonProp, Value: "Not applicable to containers", Ns: extensions.TrestleNameSpace, Remarks: "rule-1"},
+		{Name: WaiverTicketProp, Value: "SEC-42", Ns: extensions.TrestleNameSpace, Remarks: "rule-1"},
+	}, findings[0].Props)
+	require.Nil(t, findings[1].Props)
+}
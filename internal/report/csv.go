@@ -0,0 +1,112 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package report
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/complytime/complyctl/internal/complytime"
+)
+
+// Delimited table formats
+const (
+	FormatCSV = "csv"
+	FormatTSV = "tsv"
+)
+
+// hostnameProp is the subject property set by plugins with the evaluated host.
+const hostnameProp = "hostname"
+
+// findingsHeader is the header of the flat export of the assessment results.
+var findingsHeader = []string{
+	"control_id",
+	"control_title",
+	"rule_id",
+	"check_id",
+	"plugin",
+	"subject",
+	"hostname",
+	"result",
+	"reason",
+	"collected",
+	"waived",
+}
+
+// RenderDelimited renders the report as a flat table with one row per control, rule, and subject.
+// Controls without assessed rules and rules without subjects are exported with a
+// not-assessed result so every reviewed control is present in the table.
+func RenderDelimited(report Report, definitions map[string]complytime.RuleDefinition, format string) ([]byte, error) {
+	var rows [][]string
+	for _, family := range report.Families {
+		for _, control := range family.Controls {
+			if len(control.Rules) == 0 {
+				rows = append(rows, []string{control.ID, control.Title, "", "", "", "", "", StatusNotAssessed, "", "", "false"})
+				continue
+			}
+			for _, rule := range control.Rules {
+				checkID := ""
+				if len(rule.CheckIDs) > 0 {
+					checkID = rule.CheckIDs[0]
+				}
+				collected := ""
+				if !rule.Collected.IsZero() {
+					collected = rule.Collected.Format(time.RFC3339)
+				}
+				plugin := definitions[rule.RuleID].Plugin
+				if len(rule.Subjects) == 0 {
+					rows = append(rows, []string{control.ID, control.Title, rule.RuleID, checkID, plugin, "", "", StatusNotAssessed, "", collected, "false"})
+					continue
+				}
+				for _, subject := range rule.Subjects {
+					hostname := ""
+					if value, found := findProp(hostnameProp, subject.Props); found {
+						hostname = value
+					}
+					rows = append(rows, []string{
+						control.ID,
+						control.Title,
+						rule.RuleID,
+						checkID,
+						plugin,
+						subject.Key(),
+						hostname,
+						subject.Result,
+						subject.Reason,
+						collected,
+						strconv.FormatBool(subject.Waived),
+					})
+				}
+			}
+		}
+	}
+
+	var buffer bytes.Buffer
+	if err := WriteDelimited(&buffer, format, findingsHeader, rows); err != nil {
+		return nil, err
+	}
+	return buffer.Bytes(), nil
+}
+
+// WriteDelimited writes the header and rows as CSV or TSV to the writer.
+func WriteDelimited(writer io.Writer, format string, header []string, rows [][]string) error {
+	csvWriter := csv.NewWriter(writer)
+	switch format {
+	case FormatCSV:
+	case FormatTSV:
+		csvWriter.Comma = '\t'
+	default:
+		return fmt.Errorf("unsupported delimited format %q: must be one of %s, %s", format, FormatCSV, FormatTSV)
+	}
+	if err := csvWriter.Write(header); err != nil {
+		return err
+	}
+	if err := csvWriter.WriteAll(rows); err != nil {
+		return err
+	}
+	return csvWriter.Error()
+}
@@ -0,0 +1,67 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package report
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/complytime/complyctl/internal/complytime"
+)
+
+func TestRenderDelimited(t *testing.T) {
+	plan, results, catalog := newTestArtifacts()
+	definitions := map[string]complytime.RuleDefinition{
+		"rule_fail": {ID: "rule_fail", Plugin: "openscap"},
+	}
+	data, err := RenderDelimited(New(plan, results, catalog), definitions, FormatCSV)
+	require.NoError(t, err)
+
+	expected := `control_id,control_title,rule_id,check_id,plugin,subject,hostname,result,reason,collected,waived
+ac-1,Policy and Procedures,rule_pass,check_rule_pass,,host-a,,pass,,,false
+ac-2,Account Management,rule_fail,check_rule_fail,openscap,host-a,,fail,,,false
+ac-2,Account Management,rule_pass,check_rule_pass,,host-a,,pass,,,false
+au-1,Audit Policy,rule_error,check_rule_error,,host-a,,error,,,false
+zz-1,,,,,,,not-assessed,,,false
+`
+	require.Equal(t, expected, string(data))
+}
+
+func TestWriteDelimited(t *testing.T) {
+	tests := []struct {
+		name    string
+		format  string
+		want    string
+		wantErr string
+	}{
+		{
+			name:   "Valid/CSV",
+			format: FormatCSV,
+			want:   "id,value\nrule-1,\"a, b\"\n",
+		},
+		{
+			name:   "Valid/TSV",
+			format: FormatTSV,
+			want:   "id\tvalue\nrule-1\ta, b\n",
+		},
+		{
+			name:    "Invalid/Format",
+			format:  "xlsx",
+			wantErr: "unsupported delimited format \"xlsx\": must be one of csv, tsv",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			err := WriteDelimited(&buf, tt.format, []string{"id", "value"}, [][]string{{"rule-1", "a, b"}})
+			if tt.wantErr != "" {
+				require.EqualError(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.want, buf.String())
+		})
+	}
+}
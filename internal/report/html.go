@@ -0,0 +1,78 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package report
+
+import (
+	"bytes"
+	_ "embed"
+	"fmt"
+	"html/template"
+	"net/url"
+	"strings"
+	"time"
+)
+
+//go:embed templates/report.html
+var htmlTemplate string
+
+// htmlFuncs are the template functions available to the HTML report template.
+var htmlFuncs = template.FuncMap{
+	"searchText": searchText,
+	"formatTime": func(t time.Time) string {
+		if t.IsZero() {
+			return "-"
+		}
+		return t.Format(time.RFC3339)
+	},
+	"evidenceURL": evidenceURL,
+	"percent": func(part, total int) string {
+		if total == 0 {
+			return "0"
+		}
+		return fmt.Sprintf("%.0f", float64(part)*100/float64(total))
+	},
+}
+
+// RenderHTML renders the report as a self-contained HTML document.
+// All styles and scripts are inlined so the report can be viewed offline.
+func RenderHTML(report Report) ([]byte, error) {
+	tmpl, err := template.New("report").Funcs(htmlFuncs).Parse(htmlTemplate)
+	if err != nil {
+		return nil, err
+	}
+	var buffer bytes.Buffer
+	if err := tmpl.Execute(&buffer, report); err != nil {
+		return nil, err
+	}
+	return buffer.Bytes(), nil
+}
+
+// searchText returns the lowercase text used to filter a control in the HTML report.
+func searchText(control Control) string {
+	terms := []string{control.ID, control.Title}
+	for _, rule := range control.Rules {
+		terms = append(terms, rule.RuleID, rule.Title)
+		terms = append(terms, rule.CheckIDs...)
+		for _, subject := range rule.Subjects {
+			terms = append(terms, subject.Key())
+		}
+	}
+	return strings.ToLower(strings.Join(terms, " "))
+}
+
+// evidenceURL returns the evidence link for the HTML report.
+// html/template only allows http(s) links, so local evidence files are explicitly allowed here.
+// Links with any other scheme are not rendered.
+func evidenceURL(href string) template.URL {
+	uri, err := url.Parse(href)
+	if err != nil {
+		return ""
+	}
+	switch uri.Scheme {
+	case "http", "https", "file":
+		// #nosec G203 -- the scheme is restricted to non-executable links
+		return template.URL(uri.String())
+	default:
+		return ""
+	}
+}
@@ -0,0 +1,45 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package report
+
+import (
+	"html/template"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRenderHTML(t *testing.T) {
+	plan, results, catalog := newTestArtifacts()
+	html, err := RenderHTML(New(plan, results, catalog))
+	require.NoError(t, err)
+
+	content := string(html)
+	require.Contains(t, content, "<title>Compliance Report - example</title>")
+	require.Contains(t, content, "Access Control")
+	require.Contains(t, content, `data-status="fail"`)
+	require.Contains(t, content, `<a href="file:///var/lib/arf.xml">file:///var/lib/arf.xml</a>`)
+	require.Contains(t, content, "[ac-01_odp.01]")
+
+	// The report must not reference external assets
+	require.NotContains(t, content, "<link")
+	require.NotContains(t, content, "src=")
+	require.NotContains(t, content, "http")
+}
+
+func TestEvidenceURL(t *testing.T) {
+	tests := []struct {
+		name string
+		href string
+		want template.URL
+	}{
+		{name: "Valid/File", href: "file:///var/lib/arf.xml", want: "file:///var/lib/arf.xml"},
+		{name: "Valid/HTTPS", href: "https://example.com/evidence", want: "https://example.com/evidence"},
+		{name: "Invalid/Script", href: "javascript:alert(1)", want: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, evidenceURL(tt.href))
+		})
+	}
+}
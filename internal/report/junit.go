@@ -0,0 +1,161 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package report
+
+import (
+	"encoding/xml"
+	"fmt"
+	"strings"
+
+	"github.com/complytime/complyctl/internal/complytime"
+)
+
+// junitTestSuites is the root element of a JUnit XML report.
+type junitTestSuites struct {
+	XMLName  xml.Name         `xml:"testsuites"`
+	Name     string           `xml:"name,attr"`
+	Tests    int              `xml:"tests,attr"`
+	Failures int              `xml:"failures,attr"`
+	Errors   int              `xml:"errors,attr"`
+	Skipped  int              `xml:"skipped,attr"`
+	Suites   []junitTestSuite `xml:"testsuite"`
+}
+
+// junitTestSuite maps to an assessed control.
+type junitTestSuite struct {
+	Name       string          `xml:"name,attr"`
+	Tests      int             `xml:"tests,attr"`
+	Failures   int             `xml:"failures,attr"`
+	Errors     int             `xml:"errors,attr"`
+	Skipped    int             `xml:"skipped,attr"`
+	Timestamp  string          `xml:"timestamp,attr,omitempty"`
+	Properties []junitProperty `xml:"properties>property,omitempty"`
+	TestCases  []junitTestCase `xml:"testcase"`
+}
+
+// junitProperty is a name and value pair of a test suite.
+type junitProperty struct {
+	Name  string `xml:"name,attr"`
+	Value string `xml:"value,attr"`
+}
+
+// junitTestCase maps to a rule observation.
+type junitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	Failure   *junitMessage `xml:"failure,omitempty"`
+	Error     *junitMessage `xml:"error,omitempty"`
+	Skipped   *junitMessage `xml:"skipped,omitempty"`
+	SystemOut string        `xml:"system-out,omitempty"`
+}
+
+// junitMessage is the outcome of a test case that did not pass.
+type junitMessage struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr,omitempty"`
+	Text    string `xml:",chardata"`
+}
+
+// RenderJUnit renders the report as JUnit XML.
+// Each assessed control is a test suite and each rule observation of the control is a test case.
+// Failed results are failures, error results are errors, and waived or not applicable results are skipped.
+func RenderJUnit(report Report) ([]byte, error) {
+	suites := junitTestSuites{Name: "complyctl"}
+	if report.Framework != "" {
+		suites.Name = fmt.Sprintf("complyctl %s", report.Framework)
+	}
+	timestamp := ""
+	if !report.Generated.IsZero() {
+		timestamp = report.Generated.Format("2006-01-02T15:04:05")
+	}
+
+	for _, family := range report.Families {
+		for _, control := range family.Controls {
+			suite := junitTestSuite{
+				Name:      control.ID,
+				Timestamp: timestamp,
+			}
+			if control.Title != "" {
+				suite.Properties = append(suite.Properties, junitProperty{Name: "title", Value: control.Title})
+			}
+			if family.ID != "" {
+				suite.Properties = append(suite.Properties, junitProperty{Name: "family", Value: family.ID})
+			}
+			for _, rule := range control.Rules {
+				testCase := newJUnitTestCase(control.ID, rule)
+				suite.Tests++
+				switch {
+				case testCase.Failure != nil:
+					suite.Failures++
+				case testCase.Error != nil:
+					suite.Errors++
+				case testCase.Skipped != nil:
+					suite.Skipped++
+				}
+				suite.TestCases = append(suite.TestCases, testCase)
+			}
+			suites.Tests += suite.Tests
+			suites.Failures += suite.Failures
+			suites.Errors += suite.Errors
+			suites.Skipped += suite.Skipped
+			suites.Suites = append(suites.Suites, suite)
+		}
+	}
+
+	data, err := xml.MarshalIndent(suites, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(xml.Header), append(data, '\n')...), nil
+}
+
+// newJUnitTestCase creates a test case from the subjects of a rule.
+// Failures take precedence over errors, which take precedence over skipped subjects.
+func newJUnitTestCase(controlID string, rule complytime.RuleResult) junitTestCase {
+	testCase := junitTestCase{
+		Name:      rule.RuleID,
+		ClassName: controlID,
+	}
+
+	var failed, errored, skipped, output []string
+	for _, subject := range rule.Subjects {
+		line := subjectMessage(rule, subject)
+		output = append(output, fmt.Sprintf("%s: %s", subject.Key(), subject.Result))
+		switch {
+		case subject.Waived && complytime.IsFailingResult(subject.Result):
+			skipped = append(skipped, "waived: "+line)
+		case subject.IsNotApplicable():
+			skipped = append(skipped, line)
+		case subject.Result == StatusFail:
+			failed = append(failed, line)
+		case subject.Result == StatusError:
+			errored = append(errored, line)
+		}
+	}
+	testCase.SystemOut = strings.Join(output, "\n")
+
+	switch {
+	case len(failed) > 0:
+		testCase.Failure = &junitMessage{Message: strings.Join(failed, "; "), Type: StatusFail, Text: rule.Description}
+	case len(errored) > 0:
+		testCase.Error = &junitMessage{Message: strings.Join(errored, "; "), Type: StatusError, Text: rule.Description}
+	case len(skipped) > 0:
+		testCase.Skipped = &junitMessage{Message: strings.Join(skipped, "; ")}
+	case len(rule.Subjects) == 0:
+		testCase.Skipped = &junitMessage{Message: "no subjects were evaluated"}
+	}
+	return testCase
+}
+
+// subjectMessage returns the reason of the subject result, prefixed by the subject
+// when the rule was evaluated on several subjects.
+func subjectMessage(rule complytime.RuleResult, subject complytime.SubjectResult) string {
+	reason := subject.Reason
+	if reason == "" {
+		reason = fmt.Sprintf("result is %s", subject.Result)
+	}
+	if len(rule.Subjects) > 1 {
+		return fmt.Sprintf("%s: %s", subject.Key(), reason)
+	}
+	return reason
+}
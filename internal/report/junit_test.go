@@ -0,0 +1,97 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package report
+
+import (
+	"encoding/xml"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/complytime/complyctl/internal/complytime"
+)
+
+func TestRenderJUnit(t *testing.T) {
+	plan, results, catalog := newTestArtifacts()
+	data, err := RenderJUnit(New(plan, results, catalog))
+	require.NoError(t, err)
+
+	var suites junitTestSuites
+	require.NoError(t, xml.Unmarshal(data, &suites))
+	require.Equal(t, "complyctl example", suites.Name)
+	require.Equal(t, 4, suites.Tests)
+	require.Equal(t, 1, suites.Failures)
+	require.Equal(t, 1, suites.Errors)
+	require.Len(t, suites.Suites, 4)
+
+	accountManagement := suites.Suites[1]
+	require.Equal(t, "ac-2", accountManagement.Name)
+	require.Equal(t, []junitProperty{{Name: "title", Value: "Account Management"}, {Name: "family", Value: "ac"}}, accountManagement.Properties)
+	require.Len(t, accountManagement.TestCases, 2)
+	require.Equal(t, "rule_fail", accountManagement.TestCases[0].Name)
+	require.Equal(t, "ac-2", accountManagement.TestCases[0].ClassName)
+	require.NotNil(t, accountManagement.TestCases[0].Failure)
+	require.Nil(t, accountManagement.TestCases[1].Failure)
+
+	// Controls without rules have an empty test suite
+	require.Equal(t, "zz-1", suites.Suites[3].Name)
+	require.Equal(t, 0, suites.Suites[3].Tests)
+}
+
+func TestNewJUnitTestCase(t *testing.T) {
+	tests := []struct {
+		name        string
+		subjects    []complytime.SubjectResult
+		wantFailure *junitMessage
+		wantError   *junitMessage
+		wantSkipped *junitMessage
+	}{
+		{
+			name:     "Valid/Pass",
+			subjects: []complytime.SubjectResult{{ResourceID: "host-a", Result: "pass", Reason: "openscap rule-result is pass"}},
+		},
+		{
+			name:        "Valid/Failure",
+			subjects:    []complytime.SubjectResult{{ResourceID: "host-a", Result: "fail", Reason: "openscap rule-result is fail"}},
+			wantFailure: &junitMessage{Message: "openscap rule-result is fail", Type: "fail", Text: "Rule description"},
+		},
+		{
+			name:      "Valid/Error",
+			subjects:  []complytime.SubjectResult{{ResourceID: "host-a", Result: "error", Reason: "openscap rule-result is unknown"}},
+			wantError: &junitMessage{Message: "openscap rule-result is unknown", Type: "error", Text: "Rule description"},
+		},
+		{
+			name:        "Valid/NotApplicable",
+			subjects:    []complytime.SubjectResult{{ResourceID: "host-a", Result: "error", Reason: "openscap rule-result is notapplicable"}},
+			wantSkipped: &junitMessage{Message: "openscap rule-result is notapplicable"},
+		},
+		{
+			name:        "Valid/Waived",
+			subjects:    []complytime.SubjectResult{{ResourceID: "host-a", Result: "fail", Reason: "openscap rule-result is fail", Waived: true}},
+			wantSkipped: &junitMessage{Message: "waived: openscap rule-result is fail"},
+		},
+		{
+			name:        "Valid/NoSubjects",
+			wantSkipped: &junitMessage{Message: "no subjects were evaluated"},
+		},
+		{
+			name: "Valid/FailureOnOneOfSeveralSubjects",
+			subjects: []complytime.SubjectResult{
+				{ResourceID: "host-a", Result: "pass", Reason: "openscap rule-result is pass"},
+				{ResourceID: "host-b", Result: "fail", Reason: "openscap rule-result is fail"},
+				{ResourceID: "host-c", Result: "error", Reason: "openscap rule-result is error"},
+			},
+			wantFailure: &junitMessage{Message: "host-b: openscap rule-result is fail", Type: "fail", Text: "Rule description"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rule := complytime.RuleResult{RuleID: "rule_1", Description: "Rule description", Subjects: tt.subjects}
+			testCase := newJUnitTestCase("ac-1", rule)
+			require.Equal(t, "rule_1", testCase.Name)
+			require.Equal(t, tt.wantFailure, testCase.Failure)
+			require.Equal(t, tt.wantError, testCase.Error)
+			require.Equal(t, tt.wantSkipped, testCase.Skipped)
+		})
+	}
+}
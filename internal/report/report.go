@@ -0,0 +1,296 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package report
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	oscalTypes "github.com/defenseunicorns/go-oscal/src/types/oscal-1-1-3"
+	"github.com/oscal-compass/oscal-sdk-go/extensions"
+
+	"github.com/complytime/complyctl/internal/complytime"
+)
+
+// Statuses of controls and rules in a Report.
+const (
+	StatusPass        = "pass"
+	StatusFail        = "fail"
+	StatusError       = "error"
+	StatusWaived      = complytime.ResultWaived
+	StatusNotAssessed = complytime.ResultNotAssessed
+)
+
+// otherFamilyTitle is the title of the family of controls not found in the catalog.
+const otherFamilyTitle = "Other Controls"
+
+// paramInsertPattern matches parameter insertions in control statements.
+var paramInsertPattern = regexp.MustCompile(`{{\s*insert:\s*param,\s*([^\s}]+)\s*}}`)
+
+// StatusCounts counts controls by status.
+type StatusCounts struct {
+	Pass        int
+	Fail        int
+	Error       int
+	Waived      int
+	NotAssessed int
+}
+
+// Add increments the count for the given status.
+func (s *StatusCounts) Add(status string) {
+	switch status {
+	case StatusPass:
+		s.Pass++
+	case StatusFail:
+		s.Fail++
+	case StatusError:
+		s.Error++
+	case StatusWaived:
+		s.Waived++
+	default:
+		s.NotAssessed++
+	}
+}
+
+// Total returns the sum of all counts.
+func (s StatusCounts) Total() int {
+	return s.Pass + s.Fail + s.Error + s.Waived + s.NotAssessed
+}
+
+// Control is a reviewed control with its catalog information and assessed rules.
+type Control struct {
+	ID        string
+	Title     string
+	Statement string
+	Status    string
+	Rules     []complytime.RuleResult
+}
+
+// Family groups controls by their catalog group.
+type Family struct {
+	ID       string
+	Title    string
+	Counts   StatusCounts
+	Controls []Control
+}
+
+// Report is a view of OSCAL Assessment Results organized by control family.
+type Report struct {
+	Title     string
+	Framework string
+	Generated time.Time
+	Summary   StatusCounts
+	Families  []Family
+	// Rules holds the assessed rules sorted by rule ID.
+	Rules []complytime.RuleResult
+}
+
+// catalogControl is the catalog information for a control.
+type catalogControl struct {
+	title       string
+	statement   string
+	familyID    string
+	familyTitle string
+}
+
+// New creates a Report from the given OSCAL Assessment Plan, Assessment Results, and Catalog.
+// The catalog is optional and is used to resolve control titles, statements, and families.
+func New(assessmentPlan *oscalTypes.AssessmentPlan, assessmentResults *oscalTypes.AssessmentResults, catalog *oscalTypes.Catalog) Report {
+	index := complytime.NewResultsIndex(assessmentResults)
+	report := Report{
+		Title:     assessmentResults.Metadata.Title,
+		Generated: assessmentResults.Metadata.LastModified,
+	}
+	if assessmentPlan != nil && assessmentPlan.Metadata.Props != nil {
+		if frameworkProp, found := extensions.GetTrestleProp(extensions.FrameworkProp, *assessmentPlan.Metadata.Props); found {
+			report.Framework = frameworkProp.Value
+		}
+	}
+
+	rulesByControl := PlanControlRules(assessmentPlan)
+	for _, ruleID := range index.RuleIDs() {
+		rule := index.Rules[ruleID]
+		report.Rules = append(report.Rules, *rule)
+		for _, controlID := range rule.ControlIDs {
+			rulesByControl[controlID] = complytime.AppendUnique(rulesByControl[controlID], ruleID)
+		}
+	}
+
+	catalogControls := indexCatalog(catalog)
+	families := make(map[string]*Family)
+	var familyIDs []string
+	for _, controlID := range index.ControlIDs() {
+		control := Control{ID: controlID}
+		info, found := catalogControls[controlID]
+		if !found {
+			info = catalogControl{familyTitle: otherFamilyTitle}
+		}
+		control.Title = info.title
+		control.Statement = info.statement
+
+		for _, ruleID := range rulesByControl[controlID] {
+			if rule, ok := index.Rules[ruleID]; ok {
+				control.Rules = append(control.Rules, *rule)
+			}
+		}
+		sort.Slice(control.Rules, func(i, j int) bool { return control.Rules[i].RuleID < control.Rules[j].RuleID })
+		control.Status = ControlStatus(control.Rules)
+
+		family, ok := families[info.familyID]
+		if !ok {
+			family = &Family{ID: info.familyID, Title: info.familyTitle}
+			families[info.familyID] = family
+			familyIDs = append(familyIDs, info.familyID)
+		}
+		family.Controls = append(family.Controls, control)
+		family.Counts.Add(control.Status)
+		report.Summary.Add(control.Status)
+	}
+
+	// Keep controls that are not in the catalog last
+	sort.Slice(familyIDs, func(i, j int) bool {
+		if familyIDs[i] == "" || familyIDs[j] == "" {
+			return familyIDs[j] == ""
+		}
+		return familyIDs[i] < familyIDs[j]
+	})
+	for _, familyID := range familyIDs {
+		report.Families = append(report.Families, *families[familyID])
+	}
+	return report
+}
+
+// ControlStatus returns the aggregated status of a control from its rules.
+// Failures take precedence over errors, which take precedence over waived rules.
+func ControlStatus(rules []complytime.RuleResult) string {
+	status := StatusNotAssessed
+	for _, rule := range rules {
+		ruleStatus := rule.Status()
+		if statusPriority(ruleStatus) > statusPriority(status) {
+			status = ruleStatus
+		}
+	}
+	return status
+}
+
+// statusPriority orders statuses from the least to the most severe.
+func statusPriority(status string) int {
+	switch status {
+	case StatusPass:
+		return 1
+	case StatusWaived:
+		return 2
+	case StatusError:
+		return 3
+	case StatusFail:
+		return 4
+	default:
+		return 0
+	}
+}
+
+// PlanControlRules returns the rule IDs of the assessment plan activities by related control ID.
+// Activities without related controls were excluded from the scope and are not returned.
+func PlanControlRules(assessmentPlan *oscalTypes.AssessmentPlan) map[string][]string {
+	rulesByControl := make(map[string][]string)
+	if assessmentPlan == nil || assessmentPlan.LocalDefinitions == nil || assessmentPlan.LocalDefinitions.Activities == nil {
+		return rulesByControl
+	}
+	for _, activity := range *assessmentPlan.LocalDefinitions.Activities {
+		if activity.Title == "" || activity.RelatedControls == nil {
+			continue
+		}
+		for _, controlSelection := range activity.RelatedControls.ControlSelections {
+			if controlSelection.IncludeControls == nil {
+				continue
+			}
+			for _, control := range *controlSelection.IncludeControls {
+				rulesByControl[control.ControlId] = complytime.AppendUnique(rulesByControl[control.ControlId], activity.Title)
+			}
+		}
+	}
+	return rulesByControl
+}
+
+// indexCatalog returns the catalog information of all controls by control ID.
+func indexCatalog(catalog *oscalTypes.Catalog) map[string]catalogControl {
+	controls := make(map[string]catalogControl)
+	if catalog == nil {
+		return controls
+	}
+	if catalog.Controls != nil {
+		indexControls(controls, *catalog.Controls, "", otherFamilyTitle)
+	}
+	if catalog.Groups != nil {
+		indexGroups(controls, *catalog.Groups)
+	}
+	return controls
+}
+
+// indexGroups adds the controls of the given groups and their nested groups to the index.
+func indexGroups(controls map[string]catalogControl, groups []oscalTypes.Group) {
+	for _, group := range groups {
+		if group.Controls != nil {
+			indexControls(controls, *group.Controls, group.ID, group.Title)
+		}
+		if group.Groups != nil {
+			indexGroups(controls, *group.Groups)
+		}
+	}
+}
+
+// indexControls adds the given controls and their enhancements to the index.
+func indexControls(controls map[string]catalogControl, catalogControls []oscalTypes.Control, familyID, familyTitle string) {
+	for _, control := range catalogControls {
+		info := catalogControl{
+			title:       control.Title,
+			familyID:    familyID,
+			familyTitle: familyTitle,
+		}
+		if control.Parts != nil {
+			var lines []string
+			for _, part := range *control.Parts {
+				if part.Name == "statement" {
+					lines = append(lines, partProse(part, 0)...)
+				}
+			}
+			info.statement = strings.Join(lines, "\n")
+		}
+		controls[control.ID] = info
+		if control.Controls != nil {
+			indexControls(controls, *control.Controls, familyID, familyTitle)
+		}
+	}
+}
+
+// partProse returns the prose of a part and its sub-parts, prefixed by their labels.
+func partProse(part oscalTypes.Part, depth int) []string {
+	var lines []string
+	if part.Prose != "" {
+		prose := paramInsertPattern.ReplaceAllString(part.Prose, "[$1]")
+		if part.Props != nil {
+			if label, found := findProp("label", *part.Props); found {
+				prose = label + " " + prose
+			}
+		}
+		lines = append(lines, strings.Repeat("  ", depth)+prose)
+	}
+	if part.Parts != nil {
+		for _, subPart := range *part.Parts {
+			lines = append(lines, partProse(subPart, depth+1)...)
+		}
+	}
+	return lines
+}
+
+// findProp returns the value of the first property with the given name.
+func findProp(name string, props []oscalTypes.Property) (string, bool) {
+	for _, prop := range props {
+		if prop.Name == name {
+			return prop.Value, true
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,200 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package report
+
+import (
+	"testing"
+
+	oscalTypes "github.com/defenseunicorns/go-oscal/src/types/oscal-1-1-3"
+	"github.com/oscal-compass/oscal-sdk-go/extensions"
+	"github.com/stretchr/testify/require"
+
+	"github.com/complytime/complyctl/internal/complytime"
+)
+
+// newTestSubject returns an observation subject with the given result.
+func newTestSubject(resourceID, result string) oscalTypes.SubjectReference {
+	return oscalTypes.SubjectReference{
+		SubjectUuid: "subject-" + resourceID,
+		Title:       resourceID,
+		Type:        "resource",
+		Props: &[]oscalTypes.Property{
+			{Name: "resource-id", Value: resourceID, Ns: extensions.TrestleNameSpace},
+			{Name: "result", Value: result, Ns: extensions.TrestleNameSpace},
+		},
+	}
+}
+
+// newTestObservation returns an observation for the given rule and subjects.
+func newTestObservation(uuid, ruleID string, subjects ...oscalTypes.SubjectReference) oscalTypes.Observation {
+	return oscalTypes.Observation{
+		UUID:        uuid,
+		Title:       ruleID,
+		Description: "Description of " + ruleID,
+		Props: &[]oscalTypes.Property{
+			{Name: extensions.AssessmentRuleIdProp, Value: ruleID, Ns: extensions.TrestleNameSpace},
+			{Name: extensions.AssessmentCheckIdProp, Value: "check_" + ruleID, Ns: extensions.TrestleNameSpace},
+		},
+		Subjects: &subjects,
+		RelevantEvidence: &[]oscalTypes.RelevantEvidence{
+			{Href: "file:///var/lib/arf.xml", Description: "ARF_FILE"},
+		},
+	}
+}
+
+// newTestActivity returns an assessment plan activity for the given rule and related controls.
+func newTestActivity(ruleID string, controlIDs ...string) oscalTypes.Activity {
+	var controls []oscalTypes.AssessedControlsSelectControlById
+	for _, controlID := range controlIDs {
+		controls = append(controls, oscalTypes.AssessedControlsSelectControlById{ControlId: controlID})
+	}
+	return oscalTypes.Activity{
+		Title: ruleID,
+		RelatedControls: &oscalTypes.ReviewedControls{
+			ControlSelections: []oscalTypes.AssessedControls{{IncludeControls: &controls}},
+		},
+	}
+}
+
+// newTestArtifacts returns an assessment plan, assessment results, and catalog for testing.
+func newTestArtifacts() (*oscalTypes.AssessmentPlan, *oscalTypes.AssessmentResults, *oscalTypes.Catalog) {
+	plan := &oscalTypes.AssessmentPlan{
+		Metadata: oscalTypes.Metadata{
+			Props: &[]oscalTypes.Property{
+				{Name: extensions.FrameworkProp, Value: "example", Ns: extensions.TrestleNameSpace},
+			},
+		},
+		LocalDefinitions: &oscalTypes.LocalDefinitions{
+			Activities: &[]oscalTypes.Activity{
+				newTestActivity("rule_pass", "ac-1", "ac-2"),
+				newTestActivity("rule_fail", "ac-2"),
+				newTestActivity("rule_error", "au-1"),
+				{Title: "rule_excluded"},
+			},
+		},
+	}
+
+	includeControls := []oscalTypes.AssessedControlsSelectControlById{
+		{ControlId: "ac-1"}, {ControlId: "ac-2"}, {ControlId: "au-1"}, {ControlId: "zz-1"},
+	}
+	results := &oscalTypes.AssessmentResults{
+		Metadata: oscalTypes.Metadata{Title: "Example Results"},
+		Results: []oscalTypes.Result{
+			{
+				ReviewedControls: oscalTypes.ReviewedControls{
+					ControlSelections: []oscalTypes.AssessedControls{{IncludeControls: &includeControls}},
+				},
+				Observations: &[]oscalTypes.Observation{
+					newTestObservation("obs-1", "rule_pass", newTestSubject("host-a", "pass")),
+					newTestObservation("obs-2", "rule_fail", newTestSubject("host-a", "fail")),
+					newTestObservation("obs-3", "rule_error", newTestSubject("host-a", "error")),
+				},
+			},
+		},
+	}
+
+	catalog := &oscalTypes.Catalog{
+		Groups: &[]oscalTypes.Group{
+			{
+				ID:    "ac",
+				Title: "Access Control",
+				Controls: &[]oscalTypes.Control{
+					{
+						ID:    "ac-1",
+						Title: "Policy and Procedures",
+						Parts: &[]oscalTypes.Part{
+							{
+								Name:  "statement",
+								Prose: "Develop and document:",
+								Parts: &[]oscalTypes.Part{
+									{
+										Name:  "item",
+										Prose: "An access control policy for {{ insert: param, ac-01_odp.01 }}.",
+										Props: &[]oscalTypes.Property{{Name: "label", Value: "a."}},
+									},
+								},
+							},
+							{Name: "guidance", Prose: "Not part of the statement."},
+						},
+						Controls: &[]oscalTypes.Control{{ID: "ac-2", Title: "Account Management"}},
+					},
+				},
+			},
+			{
+				ID:    "au",
+				Title: "Audit and Accountability",
+				Controls: &[]oscalTypes.Control{
+					{ID: "au-1", Title: "Audit Policy"},
+				},
+			},
+		},
+	}
+	return plan, results, catalog
+}
+
+func TestNew(t *testing.T) {
+	plan, results, catalog := newTestArtifacts()
+	report := New(plan, results, catalog)
+
+	require.Equal(t, "example", report.Framework)
+	require.Equal(t, "Example Results", report.Title)
+	require.Equal(t, StatusCounts{Pass: 1, Fail: 1, Error: 1, NotAssessed: 1}, report.Summary)
+	require.Len(t, report.Rules, 3)
+
+	require.Len(t, report.Families, 3)
+	accessControl := report.Families[0]
+	require.Equal(t, "ac", accessControl.ID)
+	require.Equal(t, "Access Control", accessControl.Title)
+	require.Equal(t, StatusCounts{Pass: 1, Fail: 1}, accessControl.Counts)
+	require.Equal(t, "Policy and Procedures", accessControl.Controls[0].Title)
+	require.Equal(t, "Develop and document:\n  a. An access control policy for [ac-01_odp.01].", accessControl.Controls[0].Statement)
+	require.Equal(t, StatusPass, accessControl.Controls[0].Status)
+
+	// Enhancements are grouped with their parent control family
+	accountManagement := accessControl.Controls[1]
+	require.Equal(t, "ac-2", accountManagement.ID)
+	require.Equal(t, StatusFail, accountManagement.Status)
+	require.Len(t, accountManagement.Rules, 2)
+	require.Equal(t, "rule_fail", accountManagement.Rules[0].RuleID)
+
+	require.Equal(t, "au", report.Families[1].ID)
+	require.Equal(t, StatusError, report.Families[1].Controls[0].Status)
+
+	// Controls not found in the catalog are reported last
+	other := report.Families[2]
+	require.Equal(t, "", other.ID)
+	require.Equal(t, otherFamilyTitle, other.Title)
+	require.Equal(t, StatusNotAssessed, other.Controls[0].Status)
+}
+
+func TestNewWithoutCatalog(t *testing.T) {
+	plan, results, _ := newTestArtifacts()
+	report := New(plan, results, nil)
+	require.Len(t, report.Families, 1)
+	require.Equal(t, otherFamilyTitle, report.Families[0].Title)
+	require.Len(t, report.Families[0].Controls, 4)
+}
+
+func TestControlStatus(t *testing.T) {
+	pass := complytime.RuleResult{Subjects: []complytime.SubjectResult{{Result: "pass"}}}
+	fail := complytime.RuleResult{Subjects: []complytime.SubjectResult{{Result: "fail"}}}
+	waived := complytime.RuleResult{Subjects: []complytime.SubjectResult{{Result: "fail", Waived: true}}}
+	errored := complytime.RuleResult{Subjects: []complytime.SubjectResult{{Result: "error"}}}
+
+	tests := []struct {
+		name       string
+		rules      []complytime.RuleResult
+		wantStatus string
+	}{
+		{name: "Valid/NoRules", wantStatus: StatusNotAssessed},
+		{name: "Valid/Pass", rules: []complytime.RuleResult{pass}, wantStatus: StatusPass},
+		{name: "Valid/Waived", rules: []complytime.RuleResult{pass, waived}, wantStatus: StatusWaived},
+		{name: "Valid/Error", rules: []complytime.RuleResult{waived, errored}, wantStatus: StatusError},
+		{name: "Valid/Fail", rules: []complytime.RuleResult{fail, errored, pass}, wantStatus: StatusFail},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.wantStatus, ControlStatus(tt.rules))
+		})
+	}
+}
@@ -0,0 +1,223 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package report
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/complytime/complyctl/internal/complytime"
+)
+
+const (
+	sarifVersion = "2.1.0"
+	sarifSchema  = "https://json.schemastore.org/sarif-2.1.0.json"
+	toolName     = "complyctl"
+	toolURI      = "https://github.com/complytime/complyctl"
+)
+
+// SARIF result levels
+const (
+	sarifLevelError   = "error"
+	sarifLevelWarning = "warning"
+	sarifLevelNote    = "note"
+	sarifLevelNone    = "none"
+)
+
+// SARIF result kinds
+const (
+	sarifKindPass          = "pass"
+	sarifKindFail          = "fail"
+	sarifKindReview        = "review"
+	sarifKindNotApplicable = "notApplicable"
+)
+
+type sarifLog struct {
+	Version string     `json:"version"`
+	Schema  string     `json:"$schema"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool    sarifTool     `json:"tool"`
+	Results []sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name           string      `json:"name"`
+	InformationURI string      `json:"informationUri"`
+	Rules          []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID                   string              `json:"id"`
+	ShortDescription     *sarifMessage       `json:"shortDescription,omitempty"`
+	DefaultConfiguration *sarifConfiguration `json:"defaultConfiguration,omitempty"`
+	Properties           map[string]any      `json:"properties,omitempty"`
+}
+
+type sarifConfiguration struct {
+	Level string `json:"level"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifResult struct {
+	RuleID       string             `json:"ruleId"`
+	RuleIndex    int                `json:"ruleIndex"`
+	Kind         string             `json:"kind"`
+	Level        string             `json:"level"`
+	Message      sarifMessage       `json:"message"`
+	Locations    []sarifLocation    `json:"locations,omitempty"`
+	Suppressions []sarifSuppression `json:"suppressions,omitempty"`
+	Properties   map[string]any     `json:"properties,omitempty"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation sarifPhysicalLocation `json:"physicalLocation"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+}
+
+type sarifArtifactLocation struct {
+	URI         string        `json:"uri"`
+	Description *sarifMessage `json:"description,omitempty"`
+}
+
+type sarifSuppression struct {
+	Kind          string `json:"kind"`
+	Justification string `json:"justification,omitempty"`
+}
+
+// RenderSARIF renders the report as a SARIF 2.1.0 log.
+// Rules are described by the given rule definitions from the component definitions and
+// each evaluated subject of a rule observation is a result located at the rule evidence.
+func RenderSARIF(report Report, definitions map[string]complytime.RuleDefinition) ([]byte, error) {
+	controlsByRule := make(map[string][]string)
+	for _, family := range report.Families {
+		for _, control := range family.Controls {
+			for _, rule := range control.Rules {
+				controlsByRule[rule.RuleID] = complytime.AppendUnique(controlsByRule[rule.RuleID], control.ID)
+			}
+		}
+	}
+
+	run := sarifRun{
+		Tool: sarifTool{Driver: sarifDriver{
+			Name:           toolName,
+			InformationURI: toolURI,
+			Rules:          []sarifRule{},
+		}},
+		Results: []sarifResult{},
+	}
+	for ruleIndex, rule := range report.Rules {
+		definition, found := definitions[rule.RuleID]
+		if !found {
+			definition = complytime.RuleDefinition{ID: rule.RuleID, Description: rule.Description}
+		}
+		run.Tool.Driver.Rules = append(run.Tool.Driver.Rules, newSARIFRule(definition))
+
+		controlIDs := controlsByRule[rule.RuleID]
+		sort.Strings(controlIDs)
+		for _, subject := range rule.Subjects {
+			result := sarifResult{
+				RuleID:    rule.RuleID,
+				RuleIndex: ruleIndex,
+				Message:   sarifMessage{Text: sarifResultMessage(rule, subject)},
+				Properties: map[string]any{
+					"subject": subject.Key(),
+					"result":  subject.Result,
+				},
+			}
+			if len(controlIDs) > 0 {
+				result.Properties["controlIds"] = controlIDs
+			}
+			if len(rule.CheckIDs) > 0 {
+				result.Properties["checkIds"] = rule.CheckIDs
+			}
+			result.Kind, result.Level = sarifKindAndLevel(subject, definition.Severity)
+			if subject.Waived && complytime.IsFailingResult(subject.Result) {
+				result.Suppressions = []sarifSuppression{{Kind: "external", Justification: "waived in the assessment plan"}}
+			}
+			for _, evidence := range rule.Evidence {
+				location := sarifLocation{PhysicalLocation: sarifPhysicalLocation{
+					ArtifactLocation: sarifArtifactLocation{URI: evidence.Href},
+				}}
+				if evidence.Description != "" {
+					location.PhysicalLocation.ArtifactLocation.Description = &sarifMessage{Text: evidence.Description}
+				}
+				result.Locations = append(result.Locations, location)
+			}
+			run.Results = append(run.Results, result)
+		}
+	}
+
+	log := sarifLog{Version: sarifVersion, Schema: sarifSchema, Runs: []sarifRun{run}}
+	data, err := json.MarshalIndent(log, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append(data, '\n'), nil
+}
+
+// newSARIFRule creates a SARIF reporting descriptor from a rule definition.
+func newSARIFRule(definition complytime.RuleDefinition) sarifRule {
+	rule := sarifRule{ID: definition.ID}
+	if definition.Description != "" {
+		rule.ShortDescription = &sarifMessage{Text: definition.Description}
+	}
+	if definition.Severity != "" {
+		rule.DefaultConfiguration = &sarifConfiguration{Level: severityLevel(definition.Severity)}
+		rule.Properties = map[string]any{"severity": definition.Severity}
+	}
+	return rule
+}
+
+// sarifKindAndLevel returns the SARIF result kind and level of a subject result.
+// The level of failures is derived from the rule severity when present.
+func sarifKindAndLevel(subject complytime.SubjectResult, severity string) (string, string) {
+	switch {
+	case subject.Result == StatusPass:
+		return sarifKindPass, sarifLevelNone
+	case subject.IsNotApplicable():
+		return sarifKindNotApplicable, sarifLevelNone
+	case subject.Result == StatusFail:
+		if severity == "" {
+			return sarifKindFail, sarifLevelError
+		}
+		return sarifKindFail, severityLevel(severity)
+	default:
+		return sarifKindReview, sarifLevelWarning
+	}
+}
+
+// severityLevel maps a rule severity to a SARIF level.
+func severityLevel(severity string) string {
+	switch severity {
+	case "critical", "high":
+		return sarifLevelError
+	case "medium", "moderate":
+		return sarifLevelWarning
+	case "low", "info", "informational":
+		return sarifLevelNote
+	default:
+		return sarifLevelWarning
+	}
+}
+
+// sarifResultMessage returns the message of a SARIF result for a subject.
+func sarifResultMessage(rule complytime.RuleResult, subject complytime.SubjectResult) string {
+	if subject.Reason != "" {
+		return fmt.Sprintf("%s on %s: %s", rule.RuleID, subject.Key(), subject.Reason)
+	}
+	return fmt.Sprintf("%s on %s: result is %s", rule.RuleID, subject.Key(), subject.Result)
+}
@@ -0,0 +1,71 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package report
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/complytime/complyctl/internal/complytime"
+)
+
+func TestRenderSARIF(t *testing.T) {
+	plan, results, catalog := newTestArtifacts()
+	definitions := map[string]complytime.RuleDefinition{
+		"rule_fail": {ID: "rule_fail", Description: "Failing rule", Severity: "medium"},
+	}
+	data, err := RenderSARIF(New(plan, results, catalog), definitions)
+	require.NoError(t, err)
+
+	var log sarifLog
+	require.NoError(t, json.Unmarshal(data, &log))
+	require.Equal(t, "2.1.0", log.Version)
+	require.Len(t, log.Runs, 1)
+
+	rules := log.Runs[0].Tool.Driver.Rules
+	require.Len(t, rules, 3)
+	require.Equal(t, "rule_error", rules[0].ID)
+	require.Equal(t, &sarifMessage{Text: "Description of rule_error"}, rules[0].ShortDescription)
+	require.Nil(t, rules[0].DefaultConfiguration)
+	require.Equal(t, "rule_fail", rules[1].ID)
+	require.Equal(t, &sarifMessage{Text: "Failing rule"}, rules[1].ShortDescription)
+	require.Equal(t, &sarifConfiguration{Level: "warning"}, rules[1].DefaultConfiguration)
+
+	sarifResults := log.Runs[0].Results
+	require.Len(t, sarifResults, 3)
+	failed := sarifResults[1]
+	require.Equal(t, "rule_fail", failed.RuleID)
+	require.Equal(t, 1, failed.RuleIndex)
+	require.Equal(t, "fail", failed.Kind)
+	require.Equal(t, "warning", failed.Level)
+	require.Equal(t, "rule_fail on host-a: result is fail", failed.Message.Text)
+	require.Equal(t, "file:///var/lib/arf.xml", failed.Locations[0].PhysicalLocation.ArtifactLocation.URI)
+	require.Equal(t, []any{"ac-2"}, failed.Properties["controlIds"])
+	require.Equal(t, "pass", sarifResults[2].Kind)
+}
+
+func TestSARIFKindAndLevel(t *testing.T) {
+	tests := []struct {
+		name      string
+		subject   complytime.SubjectResult
+		severity  string
+		wantKind  string
+		wantLevel string
+	}{
+		{name: "Valid/Pass", subject: complytime.SubjectResult{Result: "pass"}, wantKind: "pass", wantLevel: "none"},
+		{name: "Valid/FailNoSeverity", subject: complytime.SubjectResult{Result: "fail"}, wantKind: "fail", wantLevel: "error"},
+		{name: "Valid/FailLowSeverity", subject: complytime.SubjectResult{Result: "fail"}, severity: "low", wantKind: "fail", wantLevel: "note"},
+		{name: "Valid/FailHighSeverity", subject: complytime.SubjectResult{Result: "fail"}, severity: "high", wantKind: "fail", wantLevel: "error"},
+		{name: "Valid/Error", subject: complytime.SubjectResult{Result: "error", Reason: "openscap rule-result is error"}, wantKind: "review", wantLevel: "warning"},
+		{name: "Valid/NotApplicable", subject: complytime.SubjectResult{Result: "error", Reason: "openscap rule-result is notapplicable"}, wantKind: "notApplicable", wantLevel: "none"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			kind, level := sarifKindAndLevel(tt.subject, tt.severity)
+			require.Equal(t, tt.wantKind, kind)
+			require.Equal(t, tt.wantLevel, level)
+		})
+	}
+}
@@ -0,0 +1,413 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package terminal
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/help"
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/complytime/complyctl/internal/complytime"
+)
+
+// Rule states of a control in the scope editor.
+const (
+	RuleIncluded = "included"
+	RuleExcluded = "excluded"
+	RuleWaived   = "waived"
+)
+
+// notApplicable is the placeholder of controls without parameters in the scope config.
+const notApplicable = "N/A"
+
+// defaultEditorHeight is the number of visible rows until the terminal size is known.
+const defaultEditorHeight = 20
+
+var (
+	cursorStyle   = lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("212"))
+	disabledStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("240"))
+	titleStyle    = lipgloss.NewStyle().Bold(true)
+	statusStyle   = lipgloss.NewStyle().Foreground(lipgloss.Color("214"))
+)
+
+var _ tea.Model = (*ScopeEditor)(nil)
+
+// scopeKeyMap holds the key bindings of the scope editor.
+type scopeKeyMap struct {
+	Up, Down, Toggle, Open, Back, Include, Exclude, Waive, Previous, Next, Save, Quit key.Binding
+}
+
+var scopeKeys = scopeKeyMap{
+	Up:       key.NewBinding(key.WithKeys("up", "k"), key.WithHelp("↑/k", "up")),
+	Down:     key.NewBinding(key.WithKeys("down", "j"), key.WithHelp("↓/j", "down")),
+	Toggle:   key.NewBinding(key.WithKeys(" "), key.WithHelp("space", "toggle")),
+	Open:     key.NewBinding(key.WithKeys("enter"), key.WithHelp("enter", "edit control")),
+	Back:     key.NewBinding(key.WithKeys("esc", "backspace"), key.WithHelp("esc", "back")),
+	Include:  key.NewBinding(key.WithKeys("i"), key.WithHelp("i", "include")),
+	Exclude:  key.NewBinding(key.WithKeys("e"), key.WithHelp("e", "exclude")),
+	Waive:    key.NewBinding(key.WithKeys("w"), key.WithHelp("w", "waive")),
+	Previous: key.NewBinding(key.WithKeys("left", "h"), key.WithHelp("←/h", "previous value")),
+	Next:     key.NewBinding(key.WithKeys("right", "l"), key.WithHelp("→/l", "next value")),
+	Save:     key.NewBinding(key.WithKeys("s"), key.WithHelp("s", "save")),
+	Quit:     key.NewBinding(key.WithKeys("q", "ctrl+c"), key.WithHelp("q", "quit without saving")),
+}
+
+// scopeControl is a control of the framework in the scope editor.
+type scopeControl struct {
+	entry   complytime.ControlEntry
+	inScope bool
+	rules   []string
+	// ruleStates holds the state of each rule of the control.
+	ruleStates map[string]string
+	// rulesEdited is true once a rule state is changed. The rule selection of the
+	// other controls is kept as loaded, including its patterns.
+	rulesEdited bool
+	// alternatives holds the selectable values of each parameter.
+	alternatives map[string][]string
+}
+
+// ScopeEditor is a bubbletea model to browse the controls of a framework, toggle the
+// include, exclude, and waive state of their rules, and select parameter values.
+type ScopeEditor struct {
+	scope    complytime.AssessmentScope
+	controls []scopeControl
+	// selected is the index of the edited control, or -1 when browsing controls.
+	selected int
+	cursor   int
+	offset   int
+	height   int
+	status   string
+	saved    bool
+	help     help.Model
+}
+
+// NewScopeEditor returns a scope editor starting from the given scope. All controls of the
+// framework are listed from the defaults; controls missing from the scope are out of scope.
+// The rules of each control and the alternatives of each parameter are used for editing.
+func NewScopeEditor(scope, defaults complytime.AssessmentScope, controlRules map[string][]string, alternatives map[string][]string) *ScopeEditor {
+	entries := make(map[string]complytime.ControlEntry)
+	for _, entry := range defaults.IncludeControls {
+		entries[entry.ControlID] = entry
+	}
+	inScope := make(map[string]bool)
+	for _, entry := range scope.IncludeControls {
+		if defaultEntry, found := entries[entry.ControlID]; found && entry.ControlTitle == "" {
+			entry.ControlTitle = defaultEntry.ControlTitle
+		}
+		entries[entry.ControlID] = entry
+		inScope[entry.ControlID] = true
+	}
+	ids := make([]string, 0, len(entries))
+	for id := range entries {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	editor := &ScopeEditor{scope: scope, selected: -1, height: defaultEditorHeight, help: help.New()}
+	for _, id := range ids {
+		entry := entries[id]
+		// Copy the parameters so that editing does not modify the given scopes.
+		parameters := entry.SelectParameters
+		entry.SelectParameters = nil
+		for _, parameter := range parameters {
+			if parameter.Name != notApplicable {
+				entry.SelectParameters = append(entry.SelectParameters, parameter)
+			}
+		}
+		control := scopeControl{
+			entry:        entry,
+			inScope:      inScope[id],
+			rules:        controlRules[id],
+			ruleStates:   make(map[string]string),
+			alternatives: make(map[string][]string),
+		}
+		for _, rule := range control.rules {
+			control.ruleStates[rule] = ruleState(control.entry, rule)
+		}
+		for _, parameter := range control.entry.SelectParameters {
+			control.alternatives[parameter.Name] = alternatives[parameter.Name]
+		}
+		editor.controls = append(editor.controls, control)
+	}
+	return editor
+}
+
+// ruleState returns the state of the rule in the control entry.
+func ruleState(entry complytime.ControlEntry, rule string) string {
+	if complytime.MatchesRule(rule, entry.ExcludeRules) || !complytime.MatchesRule(rule, entry.IncludeRules) {
+		return RuleExcluded
+	}
+	if complytime.MatchesRule(rule, entry.WaiveRules) {
+		return RuleWaived
+	}
+	return RuleIncluded
+}
+
+// Saved returns true if the user saved the scope before leaving the editor.
+func (m *ScopeEditor) Saved() bool {
+	return m.saved
+}
+
+// Scope returns the edited assessment scope.
+func (m *ScopeEditor) Scope() complytime.AssessmentScope {
+	scope := m.scope
+	scope.IncludeControls = nil
+	for _, control := range m.controls {
+		if !control.inScope {
+			continue
+		}
+		entry := control.entry
+		// Controls without known or edited rules keep their rule selection unchanged.
+		if len(control.rules) > 0 && control.rulesEdited {
+			entry.IncludeRules = []string{"*"}
+			entry.ExcludeRules = nil
+			entry.WaiveRules = nil
+			for _, rule := range control.rules {
+				switch control.ruleStates[rule] {
+				case RuleExcluded:
+					entry.ExcludeRules = append(entry.ExcludeRules, rule)
+				case RuleWaived:
+					entry.WaiveRules = append(entry.WaiveRules, rule)
+				}
+			}
+		}
+		if len(entry.SelectParameters) == 0 {
+			entry.SelectParameters = []complytime.ParameterEntry{{Name: notApplicable, Value: notApplicable}}
+		}
+		scope.IncludeControls = append(scope.IncludeControls, entry)
+	}
+	return scope
+}
+
+func (m *ScopeEditor) Init() tea.Cmd { return nil }
+
+func (m *ScopeEditor) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		// Leave room for the title, the status, and the help lines.
+		m.height = max(msg.Height-6, 1)
+		m.help.Width = msg.Width
+	case tea.KeyMsg:
+		m.status = ""
+		switch {
+		case key.Matches(msg, scopeKeys.Quit):
+			return m, tea.Quit
+		case key.Matches(msg, scopeKeys.Save):
+			m.saved = true
+			return m, tea.Quit
+		case key.Matches(msg, scopeKeys.Up):
+			m.moveCursor(-1)
+		case key.Matches(msg, scopeKeys.Down):
+			m.moveCursor(1)
+		case m.selected < 0:
+			m.updateControlList(msg)
+		default:
+			m.updateControl(msg)
+		}
+	}
+	return m, nil
+}
+
+// updateControlList handles the keys of the control list.
+func (m *ScopeEditor) updateControlList(msg tea.KeyMsg) {
+	if len(m.controls) == 0 {
+		return
+	}
+	control := &m.controls[m.cursor]
+	switch {
+	case key.Matches(msg, scopeKeys.Toggle):
+		control.inScope = !control.inScope
+	case key.Matches(msg, scopeKeys.Open):
+		m.selected = m.cursor
+		control.inScope = true
+		m.cursor, m.offset = 0, 0
+	}
+}
+
+// updateControl handles the keys of the edited control.
+func (m *ScopeEditor) updateControl(msg tea.KeyMsg) {
+	control := &m.controls[m.selected]
+	if key.Matches(msg, scopeKeys.Back) {
+		m.cursor, m.offset = m.selected, 0
+		m.selected = -1
+		m.moveCursor(0)
+		return
+	}
+	if m.cursor < len(control.rules) {
+		rule := control.rules[m.cursor]
+		if m.globalState(rule) != "" {
+			m.status = fmt.Sprintf("Rule %s is %s globally and cannot be changed per control.", rule, m.globalState(rule))
+			return
+		}
+		switch {
+		case key.Matches(msg, scopeKeys.Include):
+			control.ruleStates[rule] = RuleIncluded
+		case key.Matches(msg, scopeKeys.Exclude):
+			control.ruleStates[rule] = RuleExcluded
+		case key.Matches(msg, scopeKeys.Waive):
+			control.ruleStates[rule] = RuleWaived
+		case key.Matches(msg, scopeKeys.Toggle):
+			control.ruleStates[rule] = nextRuleState(control.ruleStates[rule])
+		default:
+			return
+		}
+		control.rulesEdited = true
+		return
+	}
+
+	parameter := &control.entry.SelectParameters[m.cursor-len(control.rules)]
+	alternatives := control.alternatives[parameter.Name]
+	step := 0
+	switch {
+	case key.Matches(msg, scopeKeys.Next, scopeKeys.Toggle):
+		step = 1
+	case key.Matches(msg, scopeKeys.Previous):
+		step = -1
+	default:
+		return
+	}
+	if len(alternatives) == 0 {
+		m.status = fmt.Sprintf("Parameter %s has no alternatives to select.", parameter.Name)
+		return
+	}
+	current := -1
+	for i, alternative := range alternatives {
+		if alternative == parameter.Value {
+			current = i
+		}
+	}
+	if current < 0 && step < 0 {
+		current = 0
+	}
+	parameter.Value = alternatives[(current+step+len(alternatives))%len(alternatives)]
+}
+
+// nextRuleState cycles the rule state from included to excluded to waived.
+func nextRuleState(state string) string {
+	switch state {
+	case RuleIncluded:
+		return RuleExcluded
+	case RuleExcluded:
+		return RuleWaived
+	default:
+		return RuleIncluded
+	}
+}
+
+// globalState returns the state of a globally excluded or waived rule.
+func (m *ScopeEditor) globalState(rule string) string {
+	switch {
+	case complytime.MatchesRule(rule, m.scope.GlobalExcludeRules):
+		return RuleExcluded
+	case complytime.MatchesRule(rule, m.scope.GlobalWaiveRules):
+		return RuleWaived
+	default:
+		return ""
+	}
+}
+
+// items returns the number of rows in the current view.
+func (m *ScopeEditor) items() int {
+	if m.selected < 0 {
+		return len(m.controls)
+	}
+	control := m.controls[m.selected]
+	return len(control.rules) + len(control.entry.SelectParameters)
+}
+
+// moveCursor moves the cursor and scrolls the view to keep the cursor visible.
+func (m *ScopeEditor) moveCursor(delta int) {
+	m.cursor = min(max(m.cursor+delta, 0), max(m.items()-1, 0))
+	if m.cursor < m.offset {
+		m.offset = m.cursor
+	}
+	if m.cursor >= m.offset+m.height {
+		m.offset = m.cursor - m.height + 1
+	}
+}
+
+func (m *ScopeEditor) View() string {
+	var lines []string
+	var bindings []key.Binding
+	if m.selected < 0 {
+		inScope := 0
+		for _, control := range m.controls {
+			if control.inScope {
+				inScope++
+			}
+		}
+		lines = append(lines, titleStyle.Render(fmt.Sprintf("Framework %s: %d of %d controls in scope", m.scope.FrameworkID, inScope, len(m.controls))))
+		for _, control := range m.controls {
+			lines = append(lines, controlLine(control))
+		}
+		bindings = []key.Binding{scopeKeys.Up, scopeKeys.Down, scopeKeys.Toggle, scopeKeys.Open, scopeKeys.Save, scopeKeys.Quit}
+	} else {
+		control := m.controls[m.selected]
+		lines = append(lines, titleStyle.Render(fmt.Sprintf("Control %s %s", control.entry.ControlID, control.entry.ControlTitle)))
+		for _, rule := range control.rules {
+			state := control.ruleStates[rule]
+			if global := m.globalState(rule); global != "" {
+				state = global + " (global)"
+			}
+			lines = append(lines, fmt.Sprintf("rule      %-50s %s", rule, state))
+		}
+		for _, parameter := range control.entry.SelectParameters {
+			values := "any value"
+			if alternatives := control.alternatives[parameter.Name]; len(alternatives) > 0 {
+				values = strings.Join(alternatives, ", ")
+			}
+			lines = append(lines, fmt.Sprintf("parameter %-50s %s [%s]", parameter.Name, parameter.Value, values))
+		}
+		bindings = []key.Binding{scopeKeys.Up, scopeKeys.Down, scopeKeys.Include, scopeKeys.Exclude, scopeKeys.Waive,
+			scopeKeys.Previous, scopeKeys.Next, scopeKeys.Back, scopeKeys.Save, scopeKeys.Quit}
+	}
+
+	var view strings.Builder
+	view.WriteString(lines[0] + "\n")
+	rows := lines[1:]
+	for i := m.offset; i < len(rows) && i < m.offset+m.height; i++ {
+		if i == m.cursor {
+			view.WriteString(cursorStyle.Render("> "+rows[i]) + "\n")
+		} else {
+			view.WriteString("  " + rows[i] + "\n")
+		}
+	}
+	if len(rows) == 0 {
+		view.WriteString(disabledStyle.Render("  No rules or parameters.") + "\n")
+	}
+	view.WriteString(statusStyle.Render(m.status) + "\n")
+	view.WriteString(m.help.ShortHelpView(bindings) + "\n")
+	return view.String()
+}
+
+// controlLine summarizes the scope and rule states of a control.
+func controlLine(control scopeControl) string {
+	mark := "[ ]"
+	if control.inScope {
+		mark = "[x]"
+	}
+	counts := make(map[string]int)
+	for _, rule := range control.rules {
+		counts[control.ruleStates[rule]]++
+	}
+	summary := fmt.Sprintf("%d rules: %d included, %d excluded, %d waived", len(control.rules),
+		counts[RuleIncluded], counts[RuleExcluded], counts[RuleWaived])
+	line := fmt.Sprintf("%s %-12s %-50s %s", mark, control.entry.ControlID, truncate(control.entry.ControlTitle, 50), summary)
+	if !control.inScope {
+		return disabledStyle.Render(line)
+	}
+	return line
+}
+
+func truncate(text string, width int) string {
+	runes := []rune(text)
+	if len(runes) <= width {
+		return text
+	}
+	return string(runes[:width-1]) + "…"
+}
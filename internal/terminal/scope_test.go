@@ -0,0 +1,212 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package terminal
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/stretchr/testify/require"
+
+	"github.com/complytime/complyctl/internal/complytime"
+)
+
+func runeKey(r string) tea.KeyMsg {
+	return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(r)}
+}
+
+func TestScopeEditor(t *testing.T) {
+	defaults := complytime.AssessmentScope{
+		FrameworkID: "cis",
+		IncludeControls: []complytime.ControlEntry{
+			{
+				ControlID:        "ac-1",
+				ControlTitle:     "Policy and Procedures",
+				IncludeRules:     []string{"*"},
+				SelectParameters: []complytime.ParameterEntry{{Name: "var_timeout", Value: "600"}},
+			},
+			{
+				ControlID:        "ac-2",
+				ControlTitle:     "Account Management",
+				IncludeRules:     []string{"*"},
+				SelectParameters: []complytime.ParameterEntry{{Name: "N/A", Value: "N/A"}},
+			},
+		},
+	}
+	controlRules := map[string][]string{
+		"ac-1": {"rule_a", "rule_b"},
+		"ac-2": {"rule_c"},
+	}
+	alternatives := map[string][]string{"var_timeout": {"300", "600", "900"}}
+
+	tests := []struct {
+		name      string
+		scope     complytime.AssessmentScope
+		keys      []tea.KeyMsg
+		wantSaved bool
+		want      []complytime.ControlEntry
+	}{
+		{
+			name:      "Valid/Defaults",
+			scope:     defaults,
+			keys:      []tea.KeyMsg{runeKey("s")},
+			wantSaved: true,
+			want: []complytime.ControlEntry{
+				{
+					ControlID:        "ac-1",
+					ControlTitle:     "Policy and Procedures",
+					IncludeRules:     []string{"*"},
+					SelectParameters: []complytime.ParameterEntry{{Name: "var_timeout", Value: "600"}},
+				},
+				{
+					ControlID:        "ac-2",
+					ControlTitle:     "Account Management",
+					IncludeRules:     []string{"*"},
+					SelectParameters: []complytime.ParameterEntry{{Name: "N/A", Value: "N/A"}},
+				},
+			},
+		},
+		{
+			name:  "Valid/EditControl",
+			scope: defaults,
+			keys: []tea.KeyMsg{
+				// Open ac-1, exclude rule_a, waive rule_b, and select the next timeout.
+				{Type: tea.KeyEnter},
+				runeKey("e"),
+				{Type: tea.KeyDown},
+				runeKey("w"),
+				{Type: tea.KeyDown},
+				{Type: tea.KeyRight},
+				// Go back and remove ac-2 from the scope.
+				{Type: tea.KeyEsc},
+				{Type: tea.KeyDown},
+				{Type: tea.KeySpace},
+				runeKey("s"),
+			},
+			wantSaved: true,
+			want: []complytime.ControlEntry{
+				{
+					ControlID:        "ac-1",
+					ControlTitle:     "Policy and Procedures",
+					IncludeRules:     []string{"*"},
+					ExcludeRules:     []string{"rule_a"},
+					WaiveRules:       []string{"rule_b"},
+					SelectParameters: []complytime.ParameterEntry{{Name: "var_timeout", Value: "900"}},
+				},
+			},
+		},
+		{
+			name: "Valid/GlobalRuleLocked",
+			scope: complytime.AssessmentScope{
+				FrameworkID:        "cis",
+				IncludeControls:    []complytime.ControlEntry{{ControlID: "ac-2", IncludeRules: []string{"*"}}},
+				GlobalExcludeRules: []string{"rule_c"},
+			},
+			keys: []tea.KeyMsg{
+				// ac-1 is out of scope and ac-2 has the globally excluded rule.
+				{Type: tea.KeyDown},
+				{Type: tea.KeyEnter},
+				runeKey("w"),
+				runeKey("s"),
+			},
+			wantSaved: true,
+			want: []complytime.ControlEntry{
+				{
+					ControlID:        "ac-2",
+					ControlTitle:     "Account Management",
+					IncludeRules:     []string{"*"},
+					SelectParameters: []complytime.ParameterEntry{{Name: "N/A", Value: "N/A"}},
+				},
+			},
+		},
+		{
+			name: "Valid/PreserveSelectors",
+			scope: complytime.AssessmentScope{
+				FrameworkID: "cis",
+				IncludeControls: []complytime.ControlEntry{
+					{ControlID: "ac-*", IncludeRules: []string{"*"}},
+					{ControlID: "ac-1", IncludeRules: []string{"rule_*"}, WaiveRules: []string{"/_b$/"}},
+					{ControlID: "ac-2", IncludeRules: []string{"rule_*"}},
+				},
+				Waivers: []complytime.Waiver{{Rule: "rule_*", Reason: "accepted risk"}},
+			},
+			keys: []tea.KeyMsg{
+				// Open ac-2 and exclude rule_c; the selector and ac-1 are not edited.
+				{Type: tea.KeyDown},
+				{Type: tea.KeyDown},
+				{Type: tea.KeyEnter},
+				runeKey("e"),
+				runeKey("s"),
+			},
+			wantSaved: true,
+			want: []complytime.ControlEntry{
+				{
+					ControlID:        "ac-*",
+					IncludeRules:     []string{"*"},
+					SelectParameters: []complytime.ParameterEntry{{Name: "N/A", Value: "N/A"}},
+				},
+				{
+					ControlID:        "ac-1",
+					ControlTitle:     "Policy and Procedures",
+					IncludeRules:     []string{"rule_*"},
+					WaiveRules:       []string{"/_b$/"},
+					SelectParameters: []complytime.ParameterEntry{{Name: "N/A", Value: "N/A"}},
+				},
+				{
+					ControlID:        "ac-2",
+					ControlTitle:     "Account Management",
+					IncludeRules:     []string{"*"},
+					ExcludeRules:     []string{"rule_c"},
+					SelectParameters: []complytime.ParameterEntry{{Name: "N/A", Value: "N/A"}},
+				},
+			},
+		},
+		{
+			name: "Valid/EditPatternRules",
+			scope: complytime.AssessmentScope{
+				FrameworkID:     "cis",
+				IncludeControls: []complytime.ControlEntry{{ControlID: "ac-1", IncludeRules: []string{"rule_*"}, WaiveRules: []string{"/_b$/"}}},
+			},
+			keys: []tea.KeyMsg{
+				// rule_b is shown as waived by the pattern, so excluding rule_a keeps it waived.
+				{Type: tea.KeyEnter},
+				runeKey("e"),
+				runeKey("s"),
+			},
+			wantSaved: true,
+			want: []complytime.ControlEntry{
+				{
+					ControlID:        "ac-1",
+					ControlTitle:     "Policy and Procedures",
+					IncludeRules:     []string{"*"},
+					ExcludeRules:     []string{"rule_a"},
+					WaiveRules:       []string{"rule_b"},
+					SelectParameters: []complytime.ParameterEntry{{Name: "N/A", Value: "N/A"}},
+				},
+			},
+		},
+		{
+			name:  "Valid/Quit",
+			scope: defaults,
+			keys:  []tea.KeyMsg{{Type: tea.KeySpace}, runeKey("q")},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			editor := NewScopeEditor(tt.scope, defaults, controlRules, alternatives)
+			for _, msg := range tt.keys {
+				editor.Update(msg)
+			}
+			require.Equal(t, tt.wantSaved, editor.Saved())
+			if tt.wantSaved {
+				scope := editor.Scope()
+				require.Equal(t, tt.want, scope.IncludeControls)
+				require.Equal(t, tt.scope.GlobalExcludeRules, scope.GlobalExcludeRules)
+				require.Equal(t, tt.scope.Waivers, scope.Waivers)
+			}
+			// The given scopes are not modified by the editor.
+			require.Equal(t, "600", defaults.IncludeControls[0].SelectParameters[0].Value)
+		})
+	}
+}
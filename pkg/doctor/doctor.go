@@ -0,0 +1,74 @@
+// SPDX-License-Identifier: Apache-2.0
+
+// Package doctor defines the environment checks reported by "complyctl doctor".
+//
+// Plugins contribute their own checks by handling the Command argument: when a plugin
+// executable is started as "<plugin> doctor", it writes its checks to stdout with Write and exits
+// instead of serving the plugin.
+package doctor
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// Command is the argument passed to plugin executables to run their checks.
+const Command = "doctor"
+
+// Status is the outcome of a check.
+type Status string
+
+// Supported check statuses
+const (
+	StatusPass Status = "pass"
+	StatusWarn Status = "warn"
+	StatusFail Status = "fail"
+)
+
+// Check is the result of a single environment check.
+type Check struct {
+	// Name is a short description of what was checked.
+	Name   string `json:"name"`
+	Status Status `json:"status"`
+	// Message describes the outcome of the check.
+	Message string `json:"message,omitempty"`
+	// Hint describes how to fix a failed or warning check.
+	Hint string `json:"hint,omitempty"`
+}
+
+// Pass returns a passing check.
+func Pass(name, message string) Check {
+	return Check{Name: name, Status: StatusPass, Message: message}
+}
+
+// Warn returns a warning check with a fix hint.
+func Warn(name, message, hint string) Check {
+	return Check{Name: name, Status: StatusWarn, Message: message, Hint: hint}
+}
+
+// Fail returns a failed check with a fix hint.
+func Fail(name, message, hint string) Check {
+	return Check{Name: name, Status: StatusFail, Message: message, Hint: hint}
+}
+
+// Write writes the checks as JSON to the writer.
+func Write(w io.Writer, checks []Check) error {
+	return json.NewEncoder(w).Encode(checks)
+}
+
+// Read reads the checks written by Write.
+func Read(data []byte) ([]Check, error) {
+	var checks []Check
+	if err := json.Unmarshal(data, &checks); err != nil {
+		return nil, fmt.Errorf("invalid checks: %w", err)
+	}
+	for _, check := range checks {
+		switch check.Status {
+		case StatusPass, StatusWarn, StatusFail:
+		default:
+			return nil, fmt.Errorf("invalid status %q for check %q", check.Status, check.Name)
+		}
+	}
+	return checks, nil
+}
@@ -0,0 +1,56 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package doctor
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteRead(t *testing.T) {
+	checks := []Check{
+		Pass("oscap installed", "found /usr/bin/oscap"),
+		Fail("datastream", "no datastream found", "Install scap-security-guide."),
+	}
+	var buf bytes.Buffer
+	require.NoError(t, Write(&buf, checks))
+
+	got, err := Read(buf.Bytes())
+	require.NoError(t, err)
+	require.Equal(t, checks, got)
+}
+
+func TestRead(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		wantErr string
+	}{
+		{
+			name: "Valid/Empty",
+			data: "[]",
+		},
+		{
+			name:    "Invalid/NotJSON",
+			data:    "This binary is a plugin.",
+			wantErr: "invalid checks: invalid character 'T' looking for beginning of value",
+		},
+		{
+			name:    "Invalid/Status",
+			data:    `[{"name": "oscap installed", "status": "ok"}]`,
+			wantErr: "invalid status \"ok\" for check \"oscap installed\"",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Read([]byte(tt.data))
+			if tt.wantErr != "" {
+				require.EqualError(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
@@ -36,7 +36,7 @@ fi
 
 echo "Installing dependencies..."
 dnf update -y
-dnf install git wget make scap-security-guide -y
+dnf install git wget make scap-security-guide openssl -y
 rm -rf /usr/bin/go
 go_mod="https://raw.githubusercontent.com/complytime/complyctl/main/go.mod"
 go_version=$(curl -s $go_mod | grep '^go' | awk '{print $2}')
@@ -60,9 +60,23 @@ set +e
 echo "Attempting to run the command complyctl list."
 bin/complyctl list 2>/dev/null
 echo "An error occurred, but script continues after the complyctl list."
-# Copy the artifacts to workspace
-cp docs/samples/sample-component-definition.json ~/.local/share/complytime/bundles
-cp docs/samples/sample-profile.json docs/samples/sample-catalog.json ~/.local/share/complytime/controls
+# Copy the artifacts to a bundle laid out like the complytime directory
+bundle=$(mktemp -d)
+mkdir -p "$bundle/bundles" "$bundle/controls"
+sed "s|trestle://controls/|file://controls/|" docs/samples/sample-component-definition.json > "$bundle/bundles/sample-component-definition.json"
+sed "s|trestle://controls/|file://controls/|" docs/samples/sample-profile.json > "$bundle/controls/sample-profile.json"
+cp docs/samples/sample-catalog.json "$bundle/controls"
+# Sign the artifacts with a key generated for this system and trust its public key
+keydir=$(mktemp -d)
+mkdir -p /etc/complytime/keys
+openssl genpkey -algorithm ed25519 -out "$keydir/signing.key"
+openssl pkey -in "$keydir/signing.key" -pubout -out /etc/complytime/keys/quick-start.pub
+for file in bundles/sample-component-definition.json controls/sample-profile.json controls/sample-catalog.json; do
+    openssl pkeyutl -sign -rawin -inkey "$keydir/signing.key" -in "$bundle/$file" | base64 -w 0 > "$bundle/$file.sig"
+done
+rm -rf "$keydir"
+# Install the signed artifacts with their signatures
+bin/complyctl bundle install "$bundle" --name quick-start
 
 # Copy the binary plugin and manifest files
 cp -rp bin/openscap-plugin ~/.local/share/complytime/plugins
@@ -5,6 +5,7 @@
 # This script performs the following setup tasks:
 #  - Build complyctl and initializes the complytime workspace.
 #  - Downloads the chosen product's controls and component definitions.
+#  - Signs them with a generated key trusted by complyctl and installs them as a bundle.
 #  - Sets up necessary plugins.
 #
 # Usage:
@@ -30,13 +31,26 @@ echo "Attempting to run the command complyctl list."
 set +e
 complyctl list 2>/dev/null
 echo "The error is expected because there is no content, this will create needed directoris for further test."
-# Download OSCAL content
-wget $URL/profiles/$3/profile.json -O $HOME/$WDIR/controls/profile.json
-wget $URL/catalogs/$2/catalog.json -O $HOME/$WDIR/controls/catalog.json
-wget $URL/component-definitions/$1/$3/component-definition.json -O $HOME/$WDIR/bundles/component-definition.json
+# Download OSCAL content to a bundle laid out like the complytime directory
+BUNDLE=$(mktemp -d)
+mkdir -p $BUNDLE/controls $BUNDLE/bundles
+wget $URL/profiles/$3/profile.json -O $BUNDLE/controls/profile.json
+wget $URL/catalogs/$2/catalog.json -O $BUNDLE/controls/catalog.json
+wget $URL/component-definitions/$1/$3/component-definition.json -O $BUNDLE/bundles/component-definition.json
 # Update trestle path
-sed -i "s|trestle://catalogs/$2/catalog.json|trestle://controls/catalog.json|" $HOME/$WDIR/controls/profile.json
-sed -i "s|trestle://profiles/$3/profile.json|trestle://controls/profile.json|" $HOME/$WDIR/bundles/component-definition.json
+sed -i "s|trestle://catalogs/$2/catalog.json|file://controls/catalog.json|" $BUNDLE/controls/profile.json
+sed -i "s|trestle://profiles/$3/profile.json|file://controls/profile.json|" $BUNDLE/bundles/component-definition.json
+# Sign the content with a key generated for the test environment and trust its public key
+KEYDIR=$(mktemp -d)
+mkdir -p /etc/complytime/keys
+openssl genpkey -algorithm ed25519 -out $KEYDIR/signing.key
+openssl pkey -in $KEYDIR/signing.key -pubout -out /etc/complytime/keys/test-content.pub
+for file in controls/profile.json controls/catalog.json bundles/component-definition.json; do
+    openssl pkeyutl -sign -rawin -inkey $KEYDIR/signing.key -in $BUNDLE/$file | base64 -w 0 > $BUNDLE/$file.sig
+done
+rm -rf $KEYDIR
+# Install the signed content, the files are kept as is with their signatures
+complyctl bundle install $BUNDLE --name $1
 # Setup plugin
 cp -rp bin/openscap-plugin $HOME/$WDIR/plugins
 checksum=$(sha256sum $HOME/$WDIR/plugins/openscap-plugin| cut -d " " -f 1 )
@@ -202,12 +202,12 @@ func TestComplyctlGenerate(t *testing.T) {
 func TestComplyctlScan(t *testing.T) {
 	// Run the "complyctl scan" command
 	// In order to improve the performace, without md will be covered in the CustomizePlanWorkflow
-	cmd := exec.Command("complyctl", "scan", "--with-md")
+	cmd := exec.Command("complyctl", "scan", "--report-format", "markdown")
 	output, err := cmd.CombinedOutput()
 
 	// Ensure there is no error when running the command
 	if err != nil {
-		t.Fatalf("Error running complyctl scan --with-md: %v\nOutput: %s", err, string(output))
+		t.Fatalf("Error running complyctl scan --report-format markdown: %v\nOutput: %s", err, string(output))
 	}
 
 	// Check if the output contains expected text